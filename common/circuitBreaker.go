@@ -0,0 +1,297 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending the request while the provider is considered unavailable.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+const (
+	// DefaultCircuitFailureThreshold is the number of consecutive failures which opens the circuit.
+	DefaultCircuitFailureThreshold = 5
+	// DefaultCircuitCooldown is how long the circuit stays open before a probe request is let through.
+	DefaultCircuitCooldown = 30 * time.Second
+)
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects requests with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a single probe request through, its outcome closes or reopens the circuit.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitSnapshot describes CircuitBreaker for monitoring.
+type CircuitSnapshot struct {
+	State               CircuitState
+	ConsecutiveFailures int
+	// OpenedAt is when the circuit was last opened, zero if it never was.
+	OpenedAt time.Time
+}
+
+// CircuitBreakerOption configures the CircuitBreaker.
+type CircuitBreakerOption func(params *circuitBreakerParams)
+
+type circuitBreakerParams struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+}
+
+// WithFailureThreshold sets how many consecutive 5xx responses or timeouts open the circuit.
+func WithFailureThreshold(threshold int) CircuitBreakerOption {
+	return func(params *circuitBreakerParams) {
+		params.threshold = threshold
+	}
+}
+
+// WithCooldown sets how long the circuit stays open before a probe request is let through.
+func WithCooldown(cooldown time.Duration) CircuitBreakerOption {
+	return func(params *circuitBreakerParams) {
+		params.cooldown = cooldown
+	}
+}
+
+// WithCircuitClock overrides current time, it is useful for testing.
+func WithCircuitClock(now func() time.Time) CircuitBreakerOption {
+	return func(params *circuitBreakerParams) {
+		params.now = now
+	}
+}
+
+func (p *circuitBreakerParams) prepare() {
+	if p.threshold <= 0 {
+		p.threshold = DefaultCircuitFailureThreshold
+	}
+
+	if p.cooldown <= 0 {
+		p.cooldown = DefaultCircuitCooldown
+	}
+
+	if p.now == nil {
+		p.now = time.Now
+	}
+}
+
+// CircuitBreaker stops requests to a provider after consecutive 5xx responses or timeouts.
+// After the cooldown a single probe request is sent, success closes the circuit, failure keeps it open.
+// Other responses, including 4xx, count as success, since the provider is reachable.
+// Pass it to the connector via its WithCircuitBreaker option, or assign it to HTTPClient.CircuitBreaker.
+// CircuitBreaker is safe for concurrent use.
+type CircuitBreaker struct {
+	mut      sync.Mutex
+	params   circuitBreakerParams
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	// probing is set while the half-open probe request is in flight, probeID identifies it.
+	probing bool
+	probeID uint64
+}
+
+// circuitTicket is handed out by allow and returned to done, it tells the probe from other requests.
+type circuitTicket struct {
+	probeID uint64
+}
+
+// NewCircuitBreaker creates a closed circuit breaker.
+func NewCircuitBreaker(opts ...CircuitBreakerOption) *CircuitBreaker {
+	params := circuitBreakerParams{}
+	for _, opt := range opts {
+		opt(&params)
+	}
+
+	params.prepare()
+
+	return &CircuitBreaker{
+		params: params,
+	}
+}
+
+// State returns current state. Open circuit is reported as half-open once the cooldown has passed.
+func (b *CircuitBreaker) State() CircuitState {
+	return b.Snapshot().State
+}
+
+// Snapshot returns current state together with failure details.
+func (b *CircuitBreaker) Snapshot() CircuitSnapshot {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	b.advance()
+
+	return CircuitSnapshot{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		OpenedAt:            b.openedAt,
+	}
+}
+
+// Reset closes the circuit, ex: when the provider is known to be back.
+func (b *CircuitBreaker) Reset() {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	b.state = CircuitClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// allow returns ErrCircuitOpen if the request must not be sent.
+// Every allowed request must be followed by a call to done with the returned ticket.
+func (b *CircuitBreaker) allow() (circuitTicket, error) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	b.advance()
+
+	switch b.state {
+	case CircuitClosed:
+		return circuitTicket{}, nil
+	case CircuitHalfOpen:
+		if !b.probing {
+			b.probing = true
+			b.probeID++
+
+			return circuitTicket{probeID: b.probeID}, nil
+		}
+
+		return circuitTicket{}, fmt.Errorf("%w: probe request is in progress", ErrCircuitOpen)
+	case CircuitOpen:
+		fallthrough
+	default:
+		retryAt := b.openedAt.Add(b.params.cooldown)
+
+		return circuitTicket{}, fmt.Errorf("%w: retry after %v", ErrCircuitOpen, retryAt.Format(time.RFC3339))
+	}
+}
+
+// done records the outcome of an allowed request.
+// Once the circuit has opened, only the probe decides the state. Requests sent before opening are ignored.
+func (b *CircuitBreaker) done(ticket circuitTicket, res *http.Response, err error) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+
+	wasProbe := b.probing && ticket.probeID != 0 && ticket.probeID == b.probeID
+	if wasProbe {
+		b.probing = false
+	} else if b.state != CircuitClosed {
+		return
+	}
+
+	switch {
+	case isCircuitFailure(res, err):
+		b.failures++
+
+		if wasProbe || b.failures >= b.params.threshold {
+			b.state = CircuitOpen
+			b.openedAt = b.params.now()
+		}
+	case err != nil && res == nil:
+		// Request didn't reach the provider for reasons unrelated to its health, ex: it was canceled.
+	default:
+		b.state = CircuitClosed
+		b.failures = 0
+	}
+}
+
+// advance moves open circuit to half-open after the cooldown.
+func (b *CircuitBreaker) advance() {
+	if b.state == CircuitOpen && !b.params.now().Before(b.openedAt.Add(b.params.cooldown)) {
+		b.state = CircuitHalfOpen
+	}
+}
+
+func isCircuitFailure(res *http.Response, err error) bool {
+	if res != nil {
+		return res.StatusCode >= http.StatusInternalServerError
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// CircuitKey identifies CircuitBreaker within CircuitBreakerRegistry.
+// Connection is empty for a breaker shared by every connection of the provider.
+type CircuitKey struct {
+	Provider   string
+	Connection string
+}
+
+// CircuitBreakerRegistry holds circuit breakers per provider and per connection.
+// Per provider breakers react to provider outages, per connection breakers isolate broken instances,
+// ex: a single Salesforce org being down. Registry is safe for concurrent use.
+type CircuitBreakerRegistry struct {
+	mut      sync.Mutex
+	opts     []CircuitBreakerOption
+	breakers map[CircuitKey]*CircuitBreaker
+}
+
+// NewCircuitBreakerRegistry creates registry, options are applied to every breaker it creates.
+func NewCircuitBreakerRegistry(opts ...CircuitBreakerOption) *CircuitBreakerRegistry {
+	return &CircuitBreakerRegistry{
+		opts:     opts,
+		breakers: make(map[CircuitKey]*CircuitBreaker),
+	}
+}
+
+// Get returns circuit breaker for the key, creating it on the first call.
+func (r *CircuitBreakerRegistry) Get(key CircuitKey) *CircuitBreaker {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	breaker, ok := r.breakers[key]
+	if !ok {
+		breaker = NewCircuitBreaker(r.opts...)
+		r.breakers[key] = breaker
+	}
+
+	return breaker
+}
+
+// Snapshot returns state of every circuit breaker, for monitoring.
+func (r *CircuitBreakerRegistry) Snapshot() map[CircuitKey]CircuitSnapshot {
+	r.mut.Lock()
+	breakers := make(map[CircuitKey]*CircuitBreaker, len(r.breakers))
+
+	for key, breaker := range r.breakers {
+		breakers[key] = breaker
+	}
+	r.mut.Unlock()
+
+	snapshot := make(map[CircuitKey]CircuitSnapshot, len(breakers))
+	for key, breaker := range breakers {
+		snapshot[key] = breaker.Snapshot()
+	}
+
+	return snapshot
+}
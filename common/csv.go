@@ -3,23 +3,90 @@ package common
 import (
 	"bytes"
 	"context"
+	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 )
 
-/*
-  // TODO: Below is work around to have CSV file type to be supported
-// Currently done on JSONHTTPClient, but we might need a separate CSVHTTPClient instead
-  // Research and see if there is a better way to do this
-*/
+var (
+	// ErrNotCSV is returned when a response is not CSV.
+	ErrNotCSV = errors.New("response is not CSV")
+	// ErrCSVHeader is returned when the header row is missing or has duplicate columns.
+	ErrCSVHeader = errors.New("invalid CSV header")
+)
 
-func (j *JSONHTTPClient) PutCSV(ctx context.Context, url string, reqBody []byte, headers ...Header) ([]byte, error) {
+// CSVFormat describes the dialect of CSV documents. Zero value is comma separated with LF line endings.
+type CSVFormat struct {
+	// Delimiter separates columns, defaults to comma.
+	Delimiter rune
+	// UseCRLF makes written lines end with CRLF. Either ending is accepted when reading.
+	UseCRLF bool
+}
+
+func (f CSVFormat) delimiter() rune {
+	if f.Delimiter == 0 {
+		return ','
+	}
+
+	return f.Delimiter
+}
+
+// CSVHTTPClient is an HTTP client that reads and writes CSV documents.
+type CSVHTTPClient struct {
+	HTTPClient         *HTTPClient        // underlying HTTP client. Required.
+	ErrorPostProcessor ErrorPostProcessor // Errors returned from CRUD methods will go via this method. Optional.
+}
+
+// Get makes a GET request to the given URL and returns a reader of CSV records, which streams the response body.
+// The first row is the header, records are keyed by its column names. The caller must close the reader.
+// If the response is not a 2xx, an error is returned, the same way as by JSONHTTPClient.Get.
+func (j *CSVHTTPClient) Get(ctx context.Context,
+	url string, format CSVFormat, headers ...Header,
+) (*CSVRecordReader, error) {
+	fullURL, err := j.HTTPClient.getURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := MakeGetRequest(ctx, fullURL, addAcceptCSVHeader(headers))
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := j.HTTPClient.Stream(req) //nolint:bodyclose
+	if err != nil {
+		return nil, j.ErrorPostProcessor.handleError(err)
+	}
+
+	if res == nil {
+		return NewCSVRecordReader(http.NoBody, format), nil
+	}
+
+	if err = checkCSVContentType(res); err != nil {
+		_ = res.Body.Close()
+
+		return nil, err
+	}
+
+	return NewCSVRecordReader(res.Body, format), nil
+}
+
+// Put makes a PUT request sending CSV document, the response body is returned as is.
+func (j *CSVHTTPClient) Put(ctx context.Context, url string, reqBody []byte, headers ...Header) ([]byte, error) {
 	fullURL, err := j.HTTPClient.getURL(url)
 	if err != nil {
 		return nil, j.ErrorPostProcessor.handleError(err)
 	}
 
-	_, body, err := j.httpPutCSV(ctx, fullURL, headers, reqBody) // nolint:bodyclose
+	req, err := makeTextCSVPutRequest(ctx, fullURL, headers, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	_, body, err := j.HTTPClient.sendRequest(req) // nolint:bodyclose
 	if err != nil {
 		return nil, j.ErrorPostProcessor.handleError(err)
 	}
@@ -27,15 +94,160 @@ func (j *JSONHTTPClient) PutCSV(ctx context.Context, url string, reqBody []byte,
 	return body, nil
 }
 
-func (j *JSONHTTPClient) httpPutCSV(ctx context.Context, url string,
-	headers []Header, body []byte,
-) (*http.Response, []byte, error) {
-	req, err := makeTextCSVPutRequest(ctx, url, headers, body)
+func addAcceptCSVHeader(headers []Header) []Header {
+	if headers == nil {
+		headers = make([]Header, 0)
+	}
+
+	return append(headers, Header{Key: "Accept", Value: "text/csv"})
+}
+
+// checkCSVContentType accepts text/csv and missing content type.
+func checkCSVContentType(res *http.Response) error {
+	ct := res.Header.Get("Content-Type")
+	if len(ct) == 0 {
+		return nil
+	}
+
+	mimeType, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return fmt.Errorf("failed to parse content type: %w", err)
+	}
+
+	if mimeType != "text/csv" {
+		return fmt.Errorf("%w: expected content type to be text/csv, got %s", ErrNotCSV, mimeType)
+	}
+
+	return nil
+}
+
+// CSVRecordReader reads CSV rows one by one as records keyed by column names of the header row.
+type CSVRecordReader struct {
+	body   io.Reader
+	reader *csv.Reader
+	header []string
+}
+
+// NewCSVRecordReader creates a reader of CSV document, the first row must be the header.
+func NewCSVRecordReader(body io.Reader, format CSVFormat) *CSVRecordReader {
+	reader := csv.NewReader(body)
+	reader.Comma = format.delimiter()
+	reader.ReuseRecord = true
+
+	return &CSVRecordReader{
+		body:   body,
+		reader: reader,
+	}
+}
+
+// Header returns column names. Empty document has no header and io.EOF is returned.
+func (r *CSVRecordReader) Header() ([]string, error) {
+	if r.header != nil {
+		return r.header, nil
+	}
+
+	row, err := r.reader.Read()
+	if err != nil {
+		return nil, r.readError(err)
+	}
+
+	header := make([]string, len(row))
+	seen := make(map[string]bool, len(row))
+
+	for index, name := range row {
+		if seen[name] {
+			return nil, fmt.Errorf("%w: duplicate column '%s'", ErrCSVHeader, name)
+		}
+
+		seen[name] = true
+		header[index] = name
+	}
+
+	r.header = header
+
+	return r.header, nil
+}
+
+// Next returns the next record, or io.EOF when there are no more records.
+func (r *CSVRecordReader) Next() (map[string]string, error) {
+	header, err := r.Header()
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.reader.Read()
 	if err != nil {
-		return nil, nil, err
+		return nil, r.readError(err)
+	}
+
+	record := make(map[string]string, len(header))
+	for index, name := range header {
+		record[name] = row[index]
+	}
+
+	return record, nil
+}
+
+// Close closes the underlying body, if it can be closed.
+func (r *CSVRecordReader) Close() error {
+	if closer, ok := r.body.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// readError marks malformed content as ErrNotCSV, failures of the underlying body are returned as is.
+func (r *CSVRecordReader) readError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return fmt.Errorf("%w: %w", ErrNotCSV, err)
+	}
+
+	return err
+}
+
+// CSVRecordToReadResultRow converts a CSV record to a row of read result.
+// Values stay strings, CSV carries no type information.
+func CSVRecordToReadResultRow(record map[string]string, fields []string, extract FieldsExtractor) ReadResultRow {
+	raw := make(map[string]any, len(record))
+	for key, value := range record {
+		raw[key] = value
+	}
+
+	return ReadResultRow{
+		Fields: extract(fields, raw),
+		Raw:    raw,
+	}
+}
+
+// MarshalCSV writes header and rows as CSV document in the given format.
+func MarshalCSV(header []string, rows [][]string, format CSVFormat) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	writer := csv.NewWriter(&buffer)
+	writer.Comma = format.delimiter()
+	writer.UseCRLF = format.UseCRLF
+
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// PutCSV sends CSV document, see CSVHTTPClient.Put.
+func (j *JSONHTTPClient) PutCSV(ctx context.Context, url string, reqBody []byte, headers ...Header) ([]byte, error) {
+	client := CSVHTTPClient{
+		HTTPClient:         j.HTTPClient,
+		ErrorPostProcessor: j.ErrorPostProcessor,
 	}
 
-	return j.HTTPClient.sendRequest(req)
+	return client.Put(ctx, url, reqBody, headers...)
 }
 
 func makeTextCSVPutRequest(ctx context.Context, url string, headers []Header, body []byte) (*http.Request, error) {
@@ -49,19 +261,3 @@ func makeTextCSVPutRequest(ctx context.Context, url string, headers []Header, bo
 
 	return addHeaders(req, headers), nil
 }
-
-// TODO: to be migrated to CSVHTTPClient once implemented
-// func (j *JSONHTTPClient) GetCSV(ctx context.Context, url string, headers ...Header) ([]byte, error) {
-// 	fullURL, err := j.getURL(url)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-
-// 	// Make the request, get the response body
-// 	_, body, err := j.httpGet(ctx, fullURL, headers) //nolint:bodyclose
-// 	if err != nil {
-// 		return nil, fmt.Errorf("error in httpGet: %w", err)
-// 	}
-
-// 	return body, nil
-// }
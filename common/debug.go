@@ -8,6 +8,8 @@ import (
 	"os"
 )
 
+// PrintRequestAndResponse dumps raw request and response to stdout, secrets included.
+// Use NewDebugLogger anywhere but local development.
 func PrintRequestAndResponse(req *http.Request, rsp *http.Response) {
 	dumpRequest(os.Stdout, req, rsp)
 }
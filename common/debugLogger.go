@@ -0,0 +1,293 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// DefaultDebugBodySize is how many bytes of a body debug logger prints.
+	DefaultDebugBodySize = 4096
+
+	// DebugBodyLimit is how many bytes of a response body are buffered for debug callbacks.
+	// Longer bodies are streamed to the caller, they are never held in memory as a whole.
+	DebugBodyLimit = 64 * 1024
+)
+
+// ErrDebugBodyTruncated ends the response body seen by debug callbacks when it exceeds DebugBodyLimit.
+var ErrDebugBodyTruncated = errors.New("body exceeds debug limit")
+
+// defaultRedactedBodyPaths are credentials found in OAuth token requests and responses.
+var defaultRedactedBodyPaths = []string{ // nolint:gochecknoglobals
+	"access_token",
+	"refresh_token",
+	"id_token",
+	"client_secret",
+}
+
+// DebugLoggerOption configures the debug logger.
+type DebugLoggerOption func(params *debugLoggerParams)
+
+type debugLoggerParams struct {
+	logger      *slog.Logger
+	level       slog.Level
+	headers     map[string]bool
+	bodyPaths   [][]string
+	maxBodySize int
+}
+
+// WithDebugSlogLogger sets the logger to write to, slog.Default() is used otherwise.
+func WithDebugSlogLogger(logger *slog.Logger) DebugLoggerOption {
+	return func(params *debugLoggerParams) {
+		params.logger = logger
+	}
+}
+
+// WithDebugLogLevel sets the level of log records, it is slog.LevelDebug by default.
+func WithDebugLogLevel(level slog.Level) DebugLoggerOption {
+	return func(params *debugLoggerParams) {
+		params.level = level
+	}
+}
+
+// WithRedactedHeaders hides values of additional headers, ex: custom API key header.
+// Authorization, cookies and common API key headers are always redacted.
+func WithRedactedHeaders(names ...string) DebugLoggerOption {
+	return func(params *debugLoggerParams) {
+		for _, name := range names {
+			params.headers[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// WithRedactedBodyPaths hides values found at dot separated paths of JSON bodies, ex: "user.ssn".
+// Keys are matched ignoring the case, arrays are traversed, so "contacts.email" hides email of every contact.
+// Paths made of a single key also hide form fields and query parameters with that name.
+// OAuth tokens and client secret are always redacted.
+func WithRedactedBodyPaths(paths ...string) DebugLoggerOption {
+	return func(params *debugLoggerParams) {
+		for _, path := range paths {
+			params.bodyPaths = append(params.bodyPaths, strings.Split(strings.ToLower(path), "."))
+		}
+	}
+}
+
+// WithMaxBodySize sets how many bytes of a body are printed, the rest is truncated.
+func WithMaxBodySize(size int) DebugLoggerOption {
+	return func(params *debugLoggerParams) {
+		params.maxBodySize = size
+	}
+}
+
+// NewDebugLogger returns a structured, secret redacting alternative to PrintRequestAndResponse.
+// It plugs into WithOAuthDebug and WithHeaderDebug:
+//
+//	common.WithOAuthDebug(common.NewDebugLogger(
+//		common.WithRedactedHeaders("X-Custom-Key"),
+//		common.WithRedactedBodyPaths("password", "contact.ssn"),
+//	))
+func NewDebugLogger(opts ...DebugLoggerOption) func(req *http.Request, rsp *http.Response) {
+	params := &debugLoggerParams{
+		level:       slog.LevelDebug,
+		headers:     make(map[string]bool),
+		maxBodySize: DefaultDebugBodySize,
+	}
+
+	for _, name := range secretHeaders {
+		params.headers[http.CanonicalHeaderKey(name)] = true
+	}
+
+	WithRedactedBodyPaths(defaultRedactedBodyPaths...)(params)
+
+	for _, opt := range opts {
+		opt(params)
+	}
+
+	return params.log
+}
+
+func (p *debugLoggerParams) log(req *http.Request, rsp *http.Response) {
+	logger := p.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	ctx := context.Background()
+	if req != nil {
+		ctx = req.Context()
+	}
+
+	if !logger.Enabled(ctx, p.level) {
+		return
+	}
+
+	attrs := make([]any, 0, 2) // nolint:gomnd
+
+	if req != nil {
+		attrs = append(attrs, slog.Group("request",
+			"method", req.Method,
+			"url", p.redactURL(req.URL),
+			"headers", p.redactHeaders(req.Header),
+			"body", p.requestBody(req),
+		))
+	}
+
+	if rsp != nil {
+		attrs = append(attrs, slog.Group("response",
+			"status", rsp.StatusCode,
+			"headers", p.redactHeaders(rsp.Header),
+			"body", p.responseBody(rsp),
+		))
+	}
+
+	logger.Log(ctx, p.level, "HTTP request", attrs...)
+}
+
+func (p *debugLoggerParams) redactHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+
+	for name, values := range header {
+		if p.headers[http.CanonicalHeaderKey(name)] {
+			headers[name] = RedactedHeaderValue
+		} else {
+			headers[name] = strings.Join(values, ", ")
+		}
+	}
+
+	return headers
+}
+
+func (p *debugLoggerParams) redactURL(location *url.URL) string {
+	if location == nil {
+		return ""
+	}
+
+	redacted := *location
+	if query := location.Query(); len(query) != 0 && p.redactValues(query) {
+		redacted.RawQuery = query.Encode()
+	}
+
+	return redacted.String()
+}
+
+// redactValues hides form fields or query parameters matching single key paths. Reports if anything was hidden.
+func (p *debugLoggerParams) redactValues(values url.Values) bool {
+	changed := false
+
+	for name := range values {
+		for _, path := range p.bodyPaths {
+			if len(path) == 1 && path[0] == strings.ToLower(name) {
+				values.Set(name, RedactedHeaderValue)
+
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
+
+// requestBody reads a copy of the body, it is available only for requests created with replayable bodies.
+func (p *debugLoggerParams) requestBody(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return fmt.Sprintf("<unavailable: %v>", err)
+	}
+
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, DebugBodyLimit+1))
+	if err != nil {
+		return fmt.Sprintf("<unavailable: %v>", err)
+	}
+
+	return p.redactBody(req.Header.Get("Content-Type"), data, len(data) > DebugBodyLimit)
+}
+
+// responseBody reads up to DebugBodyLimit bytes of the body and puts them back in front of the unread rest.
+func (p *debugLoggerParams) responseBody(rsp *http.Response) string {
+	if rsp.Body == nil || rsp.Body == http.NoBody {
+		return ""
+	}
+
+	data, err := io.ReadAll(io.LimitReader(rsp.Body, DebugBodyLimit+1))
+	rsp.Body = prependBody(data, rsp.Body)
+
+	truncated := errors.Is(err, ErrDebugBodyTruncated) || len(data) > DebugBodyLimit
+	if err != nil && !truncated {
+		return fmt.Sprintf("<unavailable: %v>", err)
+	}
+
+	return p.redactBody(rsp.Header.Get("Content-Type"), data, truncated)
+}
+
+// redactBody hides secrets in the body. Truncated JSON cannot be parsed, so it is not shown at all.
+func (p *debugLoggerParams) redactBody(contentType string, data []byte, truncated bool) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if truncated && (strings.Contains(contentType, "json") || bytes.HasPrefix(trimmed, []byte("{")) ||
+		bytes.HasPrefix(trimmed, []byte("["))) {
+		return fmt.Sprintf("<JSON body of more than %d bytes is not shown>", DebugBodyLimit)
+	}
+
+	switch {
+	case len(p.bodyPaths) == 0:
+	case strings.Contains(contentType, "application/x-www-form-urlencoded"):
+		if values, err := url.ParseQuery(string(data)); err == nil && p.redactValues(values) {
+			data = []byte(values.Encode())
+		}
+	default:
+		var body any
+		if err := json.Unmarshal(data, &body); err == nil {
+			for _, path := range p.bodyPaths {
+				redactJSONPath(body, path)
+			}
+
+			if redacted, err := json.Marshal(body); err == nil {
+				data = redacted
+			}
+		}
+	}
+
+	if p.maxBodySize > 0 && len(data) > p.maxBodySize {
+		return fmt.Sprintf("%s... (%d bytes truncated)", data[:p.maxBodySize], len(data)-p.maxBodySize)
+	}
+
+	return string(data)
+}
+
+func redactJSONPath(node any, path []string) {
+	switch typed := node.(type) {
+	case []any:
+		for _, item := range typed {
+			redactJSONPath(item, path)
+		}
+	case map[string]any:
+		for key, value := range typed {
+			if strings.ToLower(key) != path[0] {
+				continue
+			}
+
+			if len(path) == 1 {
+				typed[key] = RedactedHeaderValue
+			} else {
+				redactJSONPath(value, path[1:])
+			}
+		}
+	}
+}
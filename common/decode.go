@@ -0,0 +1,335 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrDecodeRow is returned when a single row cannot be decoded into the target type.
+	ErrDecodeRow = errors.New("failed to decode row")
+	// ErrDecodeTarget is returned when the target type is not a struct.
+	ErrDecodeTarget = errors.New("decoding target must be a struct")
+	// ErrDecodeValue is returned when a field value is incompatible with the struct field type.
+	ErrDecodeValue = errors.New("incompatible value")
+)
+
+// TimeLayouts are date formats accepted when decoding string values into time.Time.
+// Providers rarely agree on a single format, so layouts are tried in order.
+var TimeLayouts = []string{ // nolint:gochecknoglobals
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000-0700", // Salesforce
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04:05.999999999", // no zone, assumed UTC
+	"2006-01-02 15:04:05",
+	time.DateOnly,
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+var timeType = reflect.TypeOf(time.Time{}) // nolint:gochecknoglobals
+
+// RowDecodeError describes why the row at Index could not be decoded.
+type RowDecodeError struct {
+	Index int
+	Err   error
+}
+
+func (e *RowDecodeError) Error() string {
+	return fmt.Sprintf("%v at index %v: %v", ErrDecodeRow, e.Index, e.Err)
+}
+
+func (e *RowDecodeError) Unwrap() []error {
+	return []error{ErrDecodeRow, e.Err}
+}
+
+// DecodeRows converts every row of the read result into T, which must be a struct.
+// The returned slice is aligned with result.Data, rows that failed to decode are left as zero values
+// and reported as joined *RowDecodeError. Use errors.As to inspect a single failure.
+//
+// See DecodeRow for the rules of mapping fields.
+func DecodeRows[T any](result *ReadResult) ([]T, error) {
+	if result == nil {
+		return nil, nil
+	}
+
+	output := make([]T, len(result.Data))
+	failures := make([]error, 0)
+
+	for index, row := range result.Data {
+		value, err := DecodeRow[T](row)
+		if err != nil {
+			failures = append(failures, &RowDecodeError{Index: index, Err: err})
+
+			continue
+		}
+
+		output[index] = value
+	}
+
+	return output, errors.Join(failures...)
+}
+
+// DecodeRow converts a single row into T, which must be a struct.
+// Struct fields are matched by their json tag, or by their name when there is no tag, ignoring the case.
+// Values are looked up in row Fields first, then in Raw, so requested fields take precedence.
+//
+// Besides regular JSON types, the following conversions are supported:
+//   - numbers, booleans and time.Time from strings, ex: "42", "true", "2024-05-01T10:00:00.000+0000";
+//   - strings from numbers and booleans.
+//
+// Time strings are parsed using TimeLayouts.
+func DecodeRow[T any](row ReadResultRow) (T, error) {
+	var output T
+
+	target := reflect.ValueOf(&output).Elem()
+	if target.Kind() != reflect.Struct {
+		return output, fmt.Errorf("%w: %T", ErrDecodeTarget, output)
+	}
+
+	source := make(map[string]any, len(row.Raw)+len(row.Fields))
+	for key, value := range row.Raw {
+		source[strings.ToLower(key)] = value
+	}
+
+	for key, value := range row.Fields {
+		source[strings.ToLower(key)] = value
+	}
+
+	if err := decodeStruct(target, source); err != nil {
+		return output, err
+	}
+
+	return output, nil
+}
+
+func decodeStruct(target reflect.Value, source map[string]any) error {
+	targetType := target.Type()
+
+	for i := 0; i < targetType.NumField(); i++ {
+		field := targetType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, skip := fieldName(field)
+		if skip {
+			continue
+		}
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && name == field.Name {
+			// Embedded struct without a tag is flattened, the same way encoding/json does it.
+			if err := decodeStruct(target.Field(i), source); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		value, ok := source[strings.ToLower(name)]
+		if !ok {
+			continue
+		}
+
+		if err := assignValue(target.Field(i), value); err != nil {
+			return fmt.Errorf("field %v: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func fieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if len(name) == 0 {
+		name = field.Name
+	}
+
+	return name, false
+}
+
+func assignValue(target reflect.Value, value any) error { // nolint:cyclop
+	if value == nil {
+		return nil
+	}
+
+	if target.Kind() == reflect.Pointer {
+		pointer := reflect.New(target.Type().Elem())
+		if err := assignValue(pointer.Elem(), value); err != nil {
+			return err
+		}
+
+		target.Set(pointer)
+
+		return nil
+	}
+
+	if target.Type() == timeType {
+		return assignTime(target, value)
+	}
+
+	switch target.Kind() { // nolint:exhaustive
+	case reflect.String:
+		return assignString(target, value)
+	case reflect.Bool:
+		return assignBool(target, value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return assignInt(target, value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return assignUint(target, value)
+	case reflect.Float32, reflect.Float64:
+		return assignFloat(target, value)
+	case reflect.Interface:
+		if reflect.TypeOf(value).AssignableTo(target.Type()) {
+			target.Set(reflect.ValueOf(value))
+
+			return nil
+		}
+	}
+
+	// Composite types are decoded by the standard library.
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDecodeValue, err)
+	}
+
+	if err = json.Unmarshal(data, target.Addr().Interface()); err != nil {
+		return fmt.Errorf("%w: %w", ErrDecodeValue, err)
+	}
+
+	return nil
+}
+
+func assignString(target reflect.Value, value any) error {
+	switch typed := value.(type) {
+	case string:
+		target.SetString(typed)
+	case float64:
+		target.SetString(strconv.FormatFloat(typed, 'f', -1, 64))
+	case bool:
+		target.SetString(strconv.FormatBool(typed))
+	case json.Number:
+		target.SetString(typed.String())
+	default:
+		return fmt.Errorf("%w: cannot use %T as string", ErrDecodeValue, value)
+	}
+
+	return nil
+}
+
+func assignBool(target reflect.Value, value any) error {
+	switch typed := value.(type) {
+	case bool:
+		target.SetBool(typed)
+	case string:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(typed))
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrDecodeValue, err)
+		}
+
+		target.SetBool(parsed)
+	default:
+		return fmt.Errorf("%w: cannot use %T as bool", ErrDecodeValue, value)
+	}
+
+	return nil
+}
+
+func assignInt(target reflect.Value, value any) error {
+	number, err := numericString(value)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := strconv.ParseInt(number, 10, target.Type().Bits())
+	if err != nil {
+		// Numbers may be sent in exponent notation, which is still a valid integer.
+		float, floatErr := strconv.ParseFloat(number, 64)
+		if floatErr != nil || float != float64(int64(float)) {
+			return fmt.Errorf("%w: %w", ErrDecodeValue, err)
+		}
+
+		parsed = int64(float)
+	}
+
+	if target.OverflowInt(parsed) {
+		return fmt.Errorf("%w: %v overflows %v", ErrDecodeValue, parsed, target.Type())
+	}
+
+	target.SetInt(parsed)
+
+	return nil
+}
+
+func assignUint(target reflect.Value, value any) error {
+	number, err := numericString(value)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := strconv.ParseUint(number, 10, target.Type().Bits())
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDecodeValue, err)
+	}
+
+	target.SetUint(parsed)
+
+	return nil
+}
+
+func assignFloat(target reflect.Value, value any) error {
+	number, err := numericString(value)
+	if err != nil {
+		return err
+	}
+
+	parsed, err := strconv.ParseFloat(number, target.Type().Bits())
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrDecodeValue, err)
+	}
+
+	target.SetFloat(parsed)
+
+	return nil
+}
+
+// numericString normalizes numbers and numeric strings, ex: Hubspot returns all numbers as strings.
+func numericString(value any) (string, error) {
+	switch typed := value.(type) {
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64), nil
+	case json.Number:
+		return typed.String(), nil
+	case string:
+		return strings.TrimSpace(typed), nil
+	default:
+		return "", fmt.Errorf("%w: cannot use %T as number", ErrDecodeValue, value)
+	}
+}
+
+func assignTime(target reflect.Value, value any) error {
+	text, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("%w: cannot use %T as time", ErrDecodeValue, value)
+	}
+
+	for _, layout := range TimeLayouts {
+		if parsed, err := time.Parse(layout, text); err == nil {
+			target.Set(reflect.ValueOf(parsed))
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: unknown time format %q", ErrDecodeValue, text)
+}
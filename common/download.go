@@ -0,0 +1,86 @@
+package common
+
+import (
+	"context"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// Download is a file streamed from the provider. The caller must close the Body.
+type Download struct {
+	Body        io.ReadCloser
+	ContentType string
+	// Size in bytes, or UnknownSize when the provider didn't report it.
+	Size int64
+	// FileName comes from Content-Disposition header, it is empty if the provider didn't suggest one.
+	FileName string
+}
+
+// Download makes a GET request and returns the response body as a stream, together with file details.
+// If the response is not a 2xx, an error is returned, the same way as by Get.
+func (h *HTTPClient) Download(ctx context.Context, url string, headers ...Header) (*Download, error) {
+	fullURL, err := h.getURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := MakeGetRequest(ctx, fullURL, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := h.Stream(req) //nolint:bodyclose
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return &Download{
+			Body: http.NoBody,
+			Size: 0,
+		}, nil
+	}
+
+	return newDownload(res), nil
+}
+
+// Download makes a GET request and returns the file as a stream, errors are post processed as by Get.
+func (j *JSONHTTPClient) Download(ctx context.Context, url string, headers ...Header) (*Download, error) {
+	download, err := j.HTTPClient.Download(ctx, url, headers...)
+	if err != nil {
+		return nil, j.ErrorPostProcessor.handleError(err)
+	}
+
+	return download, nil
+}
+
+func newDownload(res *http.Response) *Download {
+	size := res.ContentLength
+	if size < 0 {
+		size = UnknownSize
+	}
+
+	return &Download{
+		Body:        res.Body,
+		ContentType: res.Header.Get("Content-Type"),
+		Size:        size,
+		FileName:    fileNameFromHeader(res.Header),
+	}
+}
+
+// fileNameFromHeader reads file name from Content-Disposition header.
+// Extended notation for non-ASCII names, filename*, is supported as well.
+func fileNameFromHeader(header http.Header) string {
+	disposition := header.Get("Content-Disposition")
+	if len(disposition) == 0 {
+		return ""
+	}
+
+	_, params, err := mime.ParseMediaType(disposition)
+	if err != nil {
+		return ""
+	}
+
+	return params["filename"]
+}
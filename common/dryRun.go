@@ -0,0 +1,109 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrDryRun is returned instead of sending a request that would change provider data.
+var ErrDryRun = errors.New("dry run, request was not sent")
+
+// RedactedHeaderValue replaces secrets in PreparedRequest headers.
+const RedactedHeaderValue = "REDACTED"
+
+// secretHeaders are never shown in PreparedRequest nor in debug logs. Authentication headers are normally added
+// by AuthenticatedHTTPClient after dry run stops the request, this is a safety net for connectors adding them directly.
+var secretHeaders = []string{ // nolint:gochecknoglobals
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+}
+
+// PreparedRequest is a fully resolved request that dry run has stopped.
+type PreparedRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// DryRunError carries the request which would have been sent.
+// Inspect it via errors.As, it also matches ErrDryRun via errors.Is.
+type DryRunError struct {
+	Request *PreparedRequest
+}
+
+func (e *DryRunError) Error() string {
+	return fmt.Sprintf("%v: %v %v", ErrDryRun, e.Request.Method, e.Request.URL)
+}
+
+func (e *DryRunError) Unwrap() error {
+	return ErrDryRun
+}
+
+type dryRunContextKey struct{}
+
+// WithDryRun makes every Write, Delete and bulk operation called with the returned context
+// build its request without sending it. Connector returns *DryRunError holding the request.
+// GET requests are still sent, so that reads and metadata lookups keep working.
+// Operations made of several dependent requests stop at the first one that changes data.
+//
+//	_, err := conn.Write(common.WithDryRun(ctx), params)
+//	var dryRun *common.DryRunError
+//	if errors.As(err, &dryRun) {
+//		fmt.Println(dryRun.Request.Method, dryRun.Request.URL)
+//	}
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunContextKey{}, true)
+}
+
+// WithoutDryRun lets requests, which only read data even though they are not GET, go through.
+func WithoutDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunContextKey{}, false)
+}
+
+// IsDryRun reports whether requests made with this context must not change provider data.
+func IsDryRun(ctx context.Context) bool {
+	dryRun, ok := ctx.Value(dryRunContextKey{}).(bool)
+
+	return ok && dryRun
+}
+
+// newPreparedRequest copies the request, redacting secrets and the given headers. Request body is consumed.
+func newPreparedRequest(req *http.Request, redacted []string) (*PreparedRequest, error) {
+	header := req.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	for _, names := range [][]string{secretHeaders, redacted} {
+		for _, name := range names {
+			if len(header.Values(name)) != 0 {
+				header.Set(name, RedactedHeaderValue)
+			}
+		}
+	}
+
+	var body []byte
+
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading request body: %w", err)
+		}
+
+		body = data
+	}
+
+	return &PreparedRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: header,
+		Body:   body,
+	}, nil
+}
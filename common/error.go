@@ -10,28 +10,33 @@ import (
 // InterpretError interprets the given HTTP response (in a fairly straightforward
 // way) and returns an error that can be handled by the caller.
 func InterpretError(res *http.Response, body []byte) error {
-	switch res.StatusCode {
+	return NewHTTPStatusError(res.StatusCode, fmt.Errorf("%w: %s", statusCategory(res.StatusCode), string(body)))
+}
+
+// statusCategory returns the base error for the HTTP status.
+func statusCategory(status int) error {
+	switch status {
 	case http.StatusUnauthorized:
 		// Access token invalid, refresh token and retry
-		return NewHTTPStatusError(res.StatusCode, fmt.Errorf("%w: %s", ErrAccessToken, string(body)))
+		return ErrAccessToken
 	case http.StatusForbidden:
 		// Forbidden, not retryable
-		return NewHTTPStatusError(res.StatusCode, fmt.Errorf("%w: %s", ErrForbidden, string(body)))
+		return ErrForbidden
 	case http.StatusNotFound:
 		// Semantics are debatable (temporarily missing vs. permanently gone), but for now treat this as a retryable error
-		return NewHTTPStatusError(res.StatusCode, fmt.Errorf("%w: %s", ErrRetryable, string(body)))
+		return ErrRetryable
 	case http.StatusTooManyRequests:
 		// Too many requests, retryable
-		return NewHTTPStatusError(res.StatusCode, fmt.Errorf("%w: %s", ErrRetryable, string(body)))
+		return ErrRetryable
 	}
 
-	if res.StatusCode >= 400 && res.StatusCode < 500 {
-		return NewHTTPStatusError(res.StatusCode, fmt.Errorf("%w: %s", ErrCaller, string(body)))
-	} else if res.StatusCode >= 500 && res.StatusCode < 600 {
-		return NewHTTPStatusError(res.StatusCode, fmt.Errorf("%w: %s", ErrServer, string(body)))
+	if status >= 400 && status < 500 {
+		return ErrCaller
+	} else if status >= 500 && status < 600 {
+		return ErrServer
 	}
 
-	return NewHTTPStatusError(res.StatusCode, fmt.Errorf("%w: %s", ErrUnknown, string(body)))
+	return ErrUnknown
 }
 
 func PanicRecovery(wrapup func(cause error)) {
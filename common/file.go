@@ -0,0 +1,42 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteFileAtomic writes into a temporary file which then replaces the target,
+// so that the file is never left half written and readers never see partial content.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+
+	// Removal fails once the file is renamed, which is expected.
+	defer os.Remove(file.Name()) //nolint:errcheck
+
+	if _, err = file.Write(data); err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	if err = file.Chmod(perm); err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	if err = file.Sync(); err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
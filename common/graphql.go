@@ -0,0 +1,506 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/amp-labs/connectors/common/jsonquery"
+	"github.com/spyzhov/ajson"
+)
+
+var (
+	// ErrGraphQL is matched by GraphQLErrors, use errors.As to get the details.
+	ErrGraphQL = errors.New("GraphQL error")
+	// ErrMissingGraphQLQuery is returned when the request has no query.
+	ErrMissingGraphQLQuery = errors.New("GraphQL query is empty")
+)
+
+// GraphQLHTTPClient posts GraphQL operations to a single endpoint.
+type GraphQLHTTPClient struct {
+	JSONClient *JSONHTTPClient // underlying JSON client. Required.
+	// URL is the GraphQL endpoint, ex: "https://api.github.com/graphql", or relative to the HTTPClient base.
+	URL string
+}
+
+// GraphQLRequest is a GraphQL operation, the body of POST request.
+type GraphQLRequest struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	OperationName string         `json:"operationName,omitempty"`
+}
+
+// WithCursor returns a copy of the request, where the variable holds the cursor of the page to read.
+// The variable is left unset for the first page.
+func (r GraphQLRequest) WithCursor(variable string, page NextPageToken) GraphQLRequest {
+	variables := make(map[string]any, len(r.Variables)+1)
+	for key, value := range r.Variables {
+		variables[key] = value
+	}
+
+	if len(page) != 0 {
+		variables[variable] = page.String()
+	}
+
+	r.Variables = variables
+
+	return r
+}
+
+// GraphQLError is an entry of the "errors" array.
+type GraphQLError struct {
+	Message    string            `json:"message"`
+	Path       []any             `json:"path,omitempty"`
+	Locations  []GraphQLLocation `json:"locations,omitempty"`
+	Extensions map[string]any    `json:"extensions,omitempty"`
+	// Type is a non-standard error code used by some providers, ex: GitHub "RATE_LIMITED".
+	Type string `json:"type,omitempty"`
+}
+
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Code returns the error code, from extensions or the non-standard type field.
+func (e GraphQLError) Code() string {
+	if code, ok := e.Extensions["code"].(string); ok {
+		return code
+	}
+
+	return e.Type
+}
+
+// GraphQLErrors is the "errors" array returned by the provider.
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, graphErr := range e {
+		messages[i] = graphErr.Message
+	}
+
+	return fmt.Sprintf("%v: %s", ErrGraphQL, strings.Join(messages, "; "))
+}
+
+func (e GraphQLErrors) Is(target error) bool {
+	return target == ErrGraphQL //nolint:errorlint,goerr113
+}
+
+// GraphQLResponse is the result of an operation.
+type GraphQLResponse struct {
+	// Code is the HTTP status code of the response.
+	Code int
+	// Headers are the HTTP headers of the response.
+	Headers http.Header
+	// Data is the "data" object, it is nil when the operation failed as a whole.
+	Data *ajson.Node
+	// Errors are set when the data is partial, ex: some of requested fields couldn't be resolved.
+	Errors GraphQLErrors
+	// Extensions is the "extensions" object, providers report query cost there. It can be nil.
+	Extensions *ajson.Node
+}
+
+// JSONResponse returns the data as JSON response, so it can be used with ParseResult.
+func (r *GraphQLResponse) JSONResponse() *JSONHTTPResponse {
+	return &JSONHTTPResponse{
+		Code:    r.Code,
+		Headers: r.Headers,
+		Body:    r.Data,
+	}
+}
+
+// GraphQLCost is the query complexity reported via extensions. Fields not reported by the provider are zero.
+type GraphQLCost struct {
+	// Requested is the estimated cost of the query.
+	Requested float64
+	// Actual is what the query has cost.
+	Actual float64
+	// Available is the budget left.
+	Available float64
+	// Maximum is the budget size.
+	Maximum float64
+	// RestoreRate is how fast the budget is restored, per second.
+	RestoreRate float64
+}
+
+// Cost returns the query cost found in extensions under "cost" or "complexity", ex:
+//
+//	"extensions": {"cost": {"requestedQueryCost": 12, "actualQueryCost": 10,
+//		"throttleStatus": {"maximumAvailable": 1000, "currentlyAvailable": 990, "restoreRate": 50}}}
+func (r *GraphQLResponse) Cost() (*GraphQLCost, bool) {
+	if r.Extensions == nil || !r.Extensions.IsObject() {
+		return nil, false
+	}
+
+	for _, key := range []string{"cost", "complexity"} {
+		node, err := r.Extensions.GetKey(key)
+		if err != nil || !node.IsObject() {
+			continue
+		}
+
+		cost := &GraphQLCost{
+			Requested: numberOrZero(node, "requestedQueryCost", "query"),
+			Actual:    numberOrZero(node, "actualQueryCost"),
+			Available: numberOrZero(node, "currentlyAvailable", "after"),
+			Maximum:   numberOrZero(node, "maximumAvailable", "before"),
+		}
+
+		if throttle, err := node.GetKey("throttleStatus"); err == nil && throttle.IsObject() {
+			cost.Available = numberOrZero(throttle, "currentlyAvailable")
+			cost.Maximum = numberOrZero(throttle, "maximumAvailable")
+			cost.RestoreRate = numberOrZero(throttle, "restoreRate")
+		}
+
+		return cost, true
+	}
+
+	return nil, false
+}
+
+// numberOrZero returns the first numeric value among the keys.
+func numberOrZero(node *ajson.Node, keys ...string) float64 {
+	for _, key := range keys {
+		value, err := node.GetKey(key)
+		if err == nil && value.IsNumeric() {
+			return value.MustNumeric()
+		}
+	}
+
+	return 0
+}
+
+type graphQLBody struct {
+	Data       *ajson.Node
+	Errors     GraphQLErrors
+	Extensions *ajson.Node
+}
+
+// Query posts the operation and returns the response. When the provider returns errors without data,
+// GraphQLErrors is returned as an error, classified by their codes, ex: rate limit as ErrLimitExceeded.
+// Errors along with partial data are not an error, they are available via GraphQLResponse.Errors.
+func (c *GraphQLHTTPClient) Query(ctx context.Context,
+	request GraphQLRequest, headers ...Header,
+) (*GraphQLResponse, error) {
+	if len(strings.TrimSpace(request.Query)) == 0 {
+		return nil, ErrMissingGraphQLQuery
+	}
+
+	// Queries are reads, unlike mutations they are sent even in dry run mode.
+	// Operation which cannot be told apart is treated as a write.
+	if graphQLOperationType(request.Query, request.OperationName) == "query" {
+		ctx = WithoutDryRun(ctx)
+	}
+
+	rsp, err := c.JSONClient.Post(ctx, c.URL, request, headers...)
+	if err != nil {
+		return nil, err
+	}
+
+	if rsp == nil || rsp.Body == nil {
+		return nil, ErrEmptyJSONHTTPResponse
+	}
+
+	body, err := parseGraphQLBody(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body.Errors) != 0 && body.Data == nil {
+		return nil, c.JSONClient.ErrorPostProcessor.handleError(
+			NewHTTPStatusError(rsp.Code, fmt.Errorf("%w: %w", graphQLCategory(body.Errors), body.Errors)))
+	}
+
+	return &GraphQLResponse{
+		Code:       rsp.Code,
+		Headers:    rsp.Headers,
+		Data:       body.Data,
+		Errors:     body.Errors,
+		Extensions: body.Extensions,
+	}, nil
+}
+
+// graphQLOperation is a top level definition of the document.
+type graphQLOperation struct {
+	kind string // query, mutation, subscription or fragment
+	name string
+}
+
+// graphQLOperationType returns the type of the operation executed by the document: the one named
+// by operationName, or the only operation of the document. Empty string is returned when it cannot be determined.
+// Comments, strings, fragments and nested selections are skipped, the document is not validated otherwise.
+func graphQLOperationType(document, operationName string) string {
+	operations := make([]graphQLOperation, 0, 1)
+
+	for _, operation := range graphQLDefinitions(document) {
+		if operation.kind != "fragment" {
+			operations = append(operations, operation)
+		}
+	}
+
+	if len(operationName) == 0 {
+		if len(operations) != 1 {
+			return ""
+		}
+
+		return operations[0].kind
+	}
+
+	for _, operation := range operations {
+		if operation.name == operationName {
+			return operation.kind
+		}
+	}
+
+	return ""
+}
+
+// graphQLDefinitions lists definitions of the document. Selection set without a keyword is an anonymous query.
+func graphQLDefinitions(document string) []graphQLOperation { // nolint:cyclop
+	var (
+		definitions []graphQLOperation
+		current     *graphQLOperation
+		depth       int
+		directive   bool
+	)
+
+	for pos := 0; pos < len(document); pos++ {
+		char := document[pos]
+
+		switch {
+		case char == '#':
+			// Comment runs until the end of line.
+			for pos < len(document) && document[pos] != '\n' {
+				pos++
+			}
+		case char == '"':
+			pos = skipGraphQLString(document, pos)
+		case char == '{' || char == '(' || char == '[':
+			if char == '{' && depth == 0 {
+				if current == nil {
+					current = &graphQLOperation{kind: "query"}
+				}
+
+				definitions = append(definitions, *current)
+				current = nil
+			}
+
+			depth++
+		case char == '}' || char == ')' || char == ']':
+			depth--
+		case char == '@' && depth == 0:
+			directive = true
+		case depth == 0 && isGraphQLNameStart(char):
+			end := pos
+			for end < len(document) && isGraphQLNameChar(document[end]) {
+				end++
+			}
+
+			token := document[pos:end]
+			pos = end - 1
+
+			switch {
+			case directive:
+				directive = false
+			case current == nil:
+				current = &graphQLOperation{kind: token}
+			case len(current.name) == 0:
+				current.name = token
+			}
+		}
+	}
+
+	return definitions
+}
+
+// skipGraphQLString returns position of the closing quote of a string or block string starting at pos.
+func skipGraphQLString(document string, pos int) int {
+	if strings.HasPrefix(document[pos:], `"""`) {
+		end := strings.Index(document[pos+3:], `"""`)
+		if end == -1 {
+			return len(document)
+		}
+
+		return pos + 3 + end + 2
+	}
+
+	for pos++; pos < len(document); pos++ {
+		switch document[pos] {
+		case '\\':
+			pos++
+		case '"', '\n':
+			return pos
+		}
+	}
+
+	return pos
+}
+
+func isGraphQLNameStart(char byte) bool {
+	return char == '_' || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+func isGraphQLNameChar(char byte) bool {
+	return isGraphQLNameStart(char) || (char >= '0' && char <= '9')
+}
+
+func parseGraphQLBody(node *ajson.Node) (*graphQLBody, error) {
+	body := &graphQLBody{}
+
+	query := jsonquery.New(node)
+
+	data, err := query.Object("data", true)
+	if err != nil {
+		return nil, err
+	}
+
+	body.Data = data
+
+	extensions, err := query.Object("extensions", true)
+	if err != nil {
+		return nil, err
+	}
+
+	body.Extensions = extensions
+
+	errorsNode, err := query.Array("errors", true)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range errorsNode {
+		data, err := ajson.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+
+		var graphErr GraphQLError
+		if err = json.Unmarshal(data, &graphErr); err != nil {
+			return nil, fmt.Errorf("%w: GraphQL error is not an object: %w", ErrNotJSON, err)
+		}
+
+		body.Errors = append(body.Errors, graphErr)
+	}
+
+	return body, nil
+}
+
+// graphQLCategory returns the base error for the codes, GraphQL errors are usually returned with 200 status.
+func graphQLCategory(errs GraphQLErrors) error {
+	for _, graphErr := range errs {
+		switch strings.ToUpper(graphErr.Code()) {
+		case "RATE_LIMITED", "THROTTLED", "MAX_COMPLEXITY_EXCEEDED", "COMPLEXITYEXCEPTION":
+			return ErrLimitExceeded
+		case "UNAUTHENTICATED":
+			return ErrAccessToken
+		case "FORBIDDEN", "ACCESS_DENIED":
+			return ErrForbidden
+		case "INTERNAL_SERVER_ERROR":
+			return ErrServer
+		}
+	}
+
+	return ErrCaller
+}
+
+// GraphQLConnection reads a cursor-based connection, as defined by Relay specification,
+// located in the data at the path, ex: "repository", "issues". Records are taken from "nodes" or "edges".
+// Its methods match the functions expected by ParseResult.
+type GraphQLConnection struct {
+	Path []string
+}
+
+// Records returns nodes of the connection.
+func (c GraphQLConnection) Records(data *ajson.Node) ([]map[string]any, error) {
+	connection, err := c.find(data)
+	if err != nil || connection == nil {
+		return []map[string]any{}, err
+	}
+
+	query := jsonquery.New(connection)
+
+	nodes, err := query.Array("nodes", true)
+	if err != nil {
+		return nil, err
+	}
+
+	if nodes != nil {
+		return jsonquery.Convertor.ArrayToMap(nodes)
+	}
+
+	edges, err := query.Array("edges", true)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes = make([]*ajson.Node, 0, len(edges))
+
+	for _, edge := range edges {
+		node, err := jsonquery.New(edge).Object("node", false)
+		if err != nil {
+			return nil, err
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	return jsonquery.Convertor.ArrayToMap(nodes)
+}
+
+// NextPage returns the end cursor, when the connection has next page.
+func (c GraphQLConnection) NextPage(data *ajson.Node) (string, error) {
+	connection, err := c.find(data)
+	if err != nil || connection == nil {
+		return "", err
+	}
+
+	pageInfo, err := jsonquery.New(connection).Object("pageInfo", true)
+	if err != nil || pageInfo == nil {
+		return "", err
+	}
+
+	hasNext, err := pageInfo.GetKey("hasNextPage")
+	if err != nil || !hasNext.IsBool() || !hasNext.MustBool() {
+		return "", nil //nolint:nilerr
+	}
+
+	return jsonquery.New(pageInfo).StrWithDefault("endCursor", "")
+}
+
+// Size returns "totalCount" of the connection, or the number of records on the page if it wasn't requested.
+func (c GraphQLConnection) Size(data *ajson.Node) (int64, error) {
+	connection, err := c.find(data)
+	if err != nil || connection == nil {
+		return 0, err
+	}
+
+	total, err := jsonquery.New(connection).Integer("totalCount", true)
+	if err != nil {
+		return 0, err
+	}
+
+	if total != nil {
+		return *total, nil
+	}
+
+	records, err := c.Records(data)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(records)), nil
+}
+
+// find returns the connection object, or nil if it is missing or null.
+func (c GraphQLConnection) find(data *ajson.Node) (*ajson.Node, error) {
+	if data == nil || len(c.Path) == 0 {
+		return data, nil
+	}
+
+	last := len(c.Path) - 1
+
+	return jsonquery.New(data, c.Path[:last]...).Object(c.Path[last], true)
+}
@@ -90,7 +90,7 @@ func (c *headerAuthClient) Do(req *http.Request) (*http.Response, error) {
 	}
 
 	if c.debug != nil {
-		c.debug(req, cloneResponse(rsp))
+		c.debug(req, cloneResponseWithBody(rsp))
 	}
 
 	return rsp, nil
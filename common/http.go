@@ -31,6 +31,13 @@ type HTTPClient struct {
 	Base         string                  // optional base URL. If not set, then all URLs must be absolute.
 	Client       AuthenticatedHTTPClient // underlying HTTP client. Required.
 	ErrorHandler ErrorHandler            // optional error handler. If not set, then the default error handler is used.
+	// optional circuit breaker. If set, requests fail with ErrCircuitOpen while the provider is unavailable.
+	CircuitBreaker *CircuitBreaker
+	// optional middleware around every request, the first interceptor is the outermost.
+	Interceptors []Interceptor
+	// optional headers hidden in PreparedRequest of dry run, ex: API key header of the provider
+	// or headers added by interceptors. Authorization, cookies and common API key headers are always hidden.
+	RedactedHeaders []string
 }
 
 // getURL returns the base prefixed URL.
@@ -267,7 +274,7 @@ func makeDeleteRequest(ctx context.Context, url string, headers []Header) (*http
 // sendRequest sends the given request and returns the response & response body.
 func (h *HTTPClient) sendRequest(req *http.Request) (*http.Response, []byte, error) {
 	// Send the request
-	res, err := h.Client.Do(req)
+	res, err := h.Do(req)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -289,14 +296,78 @@ func (h *HTTPClient) sendRequest(req *http.Request) (*http.Response, []byte, err
 
 	// Check the response status code
 	if res.StatusCode < 200 || res.StatusCode > 299 {
-		if h.ErrorHandler != nil {
-			return nil, nil, h.ErrorHandler(res, body)
+		return nil, nil, h.statusError(res, body)
+	}
+
+	return res, body, nil
+}
+
+// Stream sends the given request and returns the response with unread body, so that large responses
+// can be processed without holding them in memory. The caller must close the body.
+// Non-2xx responses are read and turned into errors, the same way as by other methods.
+// If ErrorHandler ignores the error, both response and error are nil.
+func (h *HTTPClient) Stream(req *http.Request) (*http.Response, error) {
+	res, err := h.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode >= 200 && res.StatusCode <= 299 {
+		return res, nil
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	return nil, h.statusError(res, body)
+}
+
+// statusError interprets non-2xx response.
+func (h *HTTPClient) statusError(res *http.Response, body []byte) error {
+	if h.ErrorHandler != nil {
+		return h.ErrorHandler(res, body)
+	}
+
+	return InterpretError(res, body)
+}
+
+// Do sends a prepared request through interceptors, it is used for requests other methods cannot build.
+// The response is returned as is, the caller must check the status code and close the body.
+func (h *HTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return chain(h.Interceptors, h.roundTrip)(req)
+}
+
+// roundTrip is the innermost step of the interceptor chain, interceptors see the request as it is sent.
+func (h *HTTPClient) roundTrip(req *http.Request) (*http.Response, error) {
+	if IsDryRun(req.Context()) && req.Method != http.MethodGet && req.Method != http.MethodHead {
+		prepared, err := newPreparedRequest(req, h.RedactedHeaders)
+		if err != nil {
+			return nil, err
 		}
 
-		return nil, nil, InterpretError(res, body)
+		return nil, &DryRunError{Request: prepared}
 	}
 
-	return res, body, nil
+	var ticket circuitTicket
+
+	if h.CircuitBreaker != nil {
+		var err error
+		if ticket, err = h.CircuitBreaker.allow(); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := h.Client.Do(req)
+
+	if h.CircuitBreaker != nil {
+		h.CircuitBreaker.done(ticket, res, err)
+	}
+
+	return res, err
 }
 
 // getURL returns the given URL if it is an absolute URL, or the given URL joined with the base URL.
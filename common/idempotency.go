@@ -0,0 +1,236 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeaderName is the header understood by providers with native idempotency support.
+const IdempotencyKeyHeaderName = "Idempotency-Key"
+
+const (
+	// DefaultIdempotencyTTL is how long in-memory store remembers created records.
+	DefaultIdempotencyTTL = 24 * time.Hour
+
+	// DefaultIdempotencyLease is how long in-memory store keeps the key reserved
+	// for a create whose outcome is unknown, ex: the request timed out.
+	DefaultIdempotencyLease = 5 * time.Minute
+)
+
+var (
+	// ErrIdempotencyStore is returned when the store cannot be queried.
+	ErrIdempotencyStore = errors.New("idempotency store failure")
+
+	// ErrIdempotencyStoreRequired is returned for a key sent to a connector without a store.
+	ErrIdempotencyStoreRequired = errors.New("idempotency key requires idempotency store")
+
+	// ErrIdempotencyKeyInUse is returned while another create with the same key is in flight,
+	// or while the outcome of a previous attempt is unknown. The write may be retried later.
+	ErrIdempotencyKeyInUse = errors.New("idempotency key is in use")
+
+	// ErrIdempotencyKeyNotSupported is returned by connectors which cannot honour the key.
+	ErrIdempotencyKeyNotSupported = errors.New("idempotency key is not supported")
+)
+
+// IdempotencyKeyHeader creates header carrying the key.
+func IdempotencyKeyHeader(key string) Header {
+	return Header{
+		Key:   IdempotencyKeyHeaderName,
+		Value: key,
+	}
+}
+
+// IdempotencyStore persists which record was created under an idempotency key.
+// The store must be shared by every connector that may retry the write, including connectors
+// which are rebuilt per request. Implementations backed by shared storage allow retries on another machine.
+// Keys are not scoped by tenant, callers sharing a store across tenants must make keys unique.
+type IdempotencyStore interface {
+	// Reserve claims the key before the create is sent to the provider.
+	// When a record was already created under the key, its identifier is returned and done is true.
+	// When the key is reserved by another attempt, ErrIdempotencyKeyInUse is returned.
+	Reserve(ctx context.Context, key string) (recordID string, done bool, err error)
+	// Put remembers the record created under the reserved key.
+	Put(ctx context.Context, key string, recordID string) error
+	// Release drops the reservation, so that the key can be used again.
+	Release(ctx context.Context, key string) error
+}
+
+// WriteFunc has the signature of WriteConnector.Write.
+type WriteFunc func(ctx context.Context, params WriteParams) (*WriteResult, error)
+
+// IdempotentWrite emulates idempotency keys for providers without native support.
+// Create with a key that was already used returns the previously created record without calling the provider,
+// in which case WriteResult has only RecordId. Updates, writes without a key and dry runs are passed through,
+// dry run doesn't create anything, so it neither reserves nor consumes the key.
+//
+// The key is reserved before the request is sent, so concurrent creates don't both reach the provider.
+// Reservation is released when the provider has rejected the create. Any other failure, ex: timeout or 5xx,
+// may have created the record, the key stays reserved and retries get ErrIdempotencyKeyInUse until the store
+// lets the reservation lapse. The guarantee is best-effort: the provider is not searched for the record,
+// so a retry after the reservation has lapsed may create a duplicate.
+func IdempotentWrite(
+	ctx context.Context, store IdempotencyStore, params WriteParams, write WriteFunc,
+) (*WriteResult, error) {
+	if len(params.IdempotencyKey) == 0 || len(params.RecordId) != 0 || IsDryRun(ctx) {
+		return write(ctx, params)
+	}
+
+	if store == nil {
+		return nil, ErrIdempotencyStoreRequired
+	}
+
+	// Keys are scoped by object, the same key may be reused to create related records.
+	key := params.ObjectName + "/" + params.IdempotencyKey
+
+	recordID, done, err := store.Reserve(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrIdempotencyKeyInUse) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("%w: %w", ErrIdempotencyStore, err)
+	}
+
+	if done {
+		return &WriteResult{
+			Success:  true,
+			RecordId: recordID,
+		}, nil
+	}
+
+	result, err := write(ctx, params)
+	if err != nil || result == nil || !result.Success {
+		if isRejectedWrite(err) {
+			if releaseErr := store.Release(ctx, key); releaseErr != nil {
+				slog.Warn("Failed to release idempotency key", "key", key, "error", releaseErr)
+			}
+		}
+
+		return result, err
+	}
+
+	if err = store.Put(ctx, key, result.RecordId); err != nil {
+		// Record was created, failing now would make the caller retry and create a duplicate.
+		slog.Warn("Failed to remember idempotency key", "key", key, "recordId", result.RecordId, "error", err)
+	}
+
+	return result, nil
+}
+
+// isRejectedWrite reports whether the create certainly didn't happen. The provider responded
+// with 4xx or the request was refused before it was sent. Unsuccessful result without an error counts as rejected.
+func isRejectedWrite(err error) bool {
+	if err == nil {
+		return true
+	}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.HTTPStatus >= http.StatusBadRequest && statusErr.HTTPStatus < http.StatusInternalServerError
+	}
+
+	return errors.Is(err, ErrInvalidRecord) || errors.Is(err, ErrCaller) || errors.Is(err, ErrMissingObjects)
+}
+
+// InMemoryIdempotencyStore keeps keys in process memory. Created records expire after TTL,
+// reservations of creates with unknown outcome expire after the lease.
+// Share one store between connectors of a process, ex: pass it to every connector built by the Manager.
+type InMemoryIdempotencyStore struct {
+	mut       sync.Mutex
+	ttl       time.Duration
+	lease     time.Duration
+	entries   map[string]idempotencyEntry
+	lastSweep time.Time
+}
+
+type idempotencyEntry struct {
+	recordID string
+	done     bool
+	expires  time.Time
+}
+
+// NewInMemoryIdempotencyStore creates store, non-positive ttl and lease default to
+// DefaultIdempotencyTTL and DefaultIdempotencyLease.
+func NewInMemoryIdempotencyStore(ttl, lease time.Duration) *InMemoryIdempotencyStore {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+
+	if lease <= 0 {
+		lease = DefaultIdempotencyLease
+	}
+
+	return &InMemoryIdempotencyStore{
+		ttl:       ttl,
+		lease:     lease,
+		entries:   make(map[string]idempotencyEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *InMemoryIdempotencyStore) Reserve(_ context.Context, key string) (string, bool, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	entry, ok := s.entries[key]
+	if ok && now.Before(entry.expires) {
+		if entry.done {
+			return entry.recordID, true, nil
+		}
+
+		return "", false, ErrIdempotencyKeyInUse
+	}
+
+	s.entries[key] = idempotencyEntry{
+		expires: now.Add(s.lease),
+	}
+
+	return "", false, nil
+}
+
+func (s *InMemoryIdempotencyStore) Put(_ context.Context, key string, recordID string) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.entries[key] = idempotencyEntry{
+		recordID: recordID,
+		done:     true,
+		expires:  time.Now().Add(s.ttl),
+	}
+
+	return nil
+}
+
+func (s *InMemoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if entry, ok := s.entries[key]; ok && !entry.done {
+		delete(s.entries, key)
+	}
+
+	return nil
+}
+
+// sweep removes expired entries at most once per lease to keep Reserve cheap.
+func (s *InMemoryIdempotencyStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) <= s.lease {
+		return
+	}
+
+	for name, entry := range s.entries {
+		if now.After(entry.expires) {
+			delete(s.entries, name)
+		}
+	}
+
+	s.lastSweep = now
+}
@@ -0,0 +1,49 @@
+package common
+
+import (
+	"net/http"
+)
+
+// RoundTripFunc sends the request and returns the provider response.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Interceptor is a middleware around every request made by HTTPClient.
+// It may modify the request before passing it to next, inspect or replace the response
+// it gets back, or answer without calling next at all, ex: serving from cache.
+// Response body is not read yet, an interceptor that reads it must replace it with an unread copy.
+//
+//	func(req *http.Request, next common.RoundTripFunc) (*http.Response, error) {
+//		start := time.Now()
+//		rsp, err := next(req)
+//		observe(req.Method, req.URL.Path, time.Since(start))
+//
+//		return rsp, err
+//	}
+type Interceptor func(req *http.Request, next RoundTripFunc) (*http.Response, error)
+
+// ChainInterceptors composes interceptors into one. The first interceptor is the outermost,
+// it sees the request first and the response last.
+func ChainInterceptors(interceptors ...Interceptor) Interceptor {
+	return func(req *http.Request, next RoundTripFunc) (*http.Response, error) {
+		return chain(interceptors, next)(req)
+	}
+}
+
+// HeaderInterceptor adds headers to every request.
+func HeaderInterceptor(headers ...Header) Interceptor {
+	return func(req *http.Request, next RoundTripFunc) (*http.Response, error) {
+		return next(addHeaders(req, headers))
+	}
+}
+
+// chain wraps send with interceptors, so that the first interceptor is called first.
+func chain(interceptors []Interceptor, send RoundTripFunc) RoundTripFunc {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], send
+		send = func(req *http.Request) (*http.Response, error) {
+			return interceptor(req, next)
+		}
+	}
+
+	return send
+}
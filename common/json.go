@@ -102,19 +102,8 @@ func parseJSONResponse(res *http.Response, body []byte) (*JSONHTTPResponse, erro
 		return nil, nil //nolint:nilnil
 	}
 	// Ensure the response is JSON
-	ct := res.Header.Get("Content-Type")
-	if len(ct) > 0 {
-		mimeType, _, err := mime.ParseMediaType(ct)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse content type: %w", err)
-		}
-
-		// Providers implementing JSONAPISpeicifcations returns application/vnd.api+json
-		if mimeType != "application/json" && mimeType != "application/vnd.api+json" {
-			return nil, fmt.Errorf("%w: expected content type to be application/json or application/vnd.api+json , got %s",
-				ErrNotJSON, mimeType,
-			)
-		}
+	if err := checkJSONContentType(res); err != nil {
+		return nil, err
 	}
 
 	// Unmarshall the response body into JSON
@@ -131,6 +120,26 @@ func parseJSONResponse(res *http.Response, body []byte) (*JSONHTTPResponse, erro
 	}, nil
 }
 
+// checkJSONContentType returns error if the response declares content type other than JSON.
+func checkJSONContentType(res *http.Response) error {
+	ct := res.Header.Get("Content-Type")
+	if len(ct) > 0 {
+		mimeType, _, err := mime.ParseMediaType(ct)
+		if err != nil {
+			return fmt.Errorf("failed to parse content type: %w", err)
+		}
+
+		// Providers implementing JSONAPISpeicifcations returns application/vnd.api+json
+		if mimeType != "application/json" && mimeType != "application/vnd.api+json" {
+			return fmt.Errorf("%w: expected content type to be application/json or application/vnd.api+json , got %s",
+				ErrNotJSON, mimeType,
+			)
+		}
+	}
+
+	return nil
+}
+
 // UnmarshalJSON deserializes the response body into the given type.
 func UnmarshalJSON[T any](rsp *JSONHTTPResponse) (*T, error) {
 	var data T
@@ -0,0 +1,370 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/spyzhov/ajson"
+)
+
+var (
+	// ErrRecordNotObject is returned by JSONRecordDecoder for array elements which are not objects.
+	ErrRecordNotObject = errors.New("record is not a JSON object")
+	// ErrRecordsNotConsumed is returned when the envelope is requested before every record was read.
+	ErrRecordsNotConsumed = errors.New("records were not fully consumed")
+)
+
+type recordDecoderState int
+
+const (
+	recordDecoderNotStarted recordDecoderState = iota
+	recordDecoderStreaming
+	recordDecoderFinished
+)
+
+// JSONRecordDecoder reads records one by one from an array inside a JSON document,
+// without materializing the whole document. Values outside the records array,
+// ex: total size or next page link, are kept and available via Envelope.
+//
+//	decoder := common.NewJSONRecordDecoder(body, "records")
+//	for {
+//		record, err := decoder.Next()
+//		if errors.Is(err, io.EOF) {
+//			break
+//		}
+//		...
+//	}
+//	envelope, err := decoder.Envelope()
+type JSONRecordDecoder struct {
+	body    io.Reader
+	decoder *json.Decoder
+	path    []string
+	state   recordDecoderState
+	// levels hold values found outside the records array, from the root down to the array parent.
+	levels []map[string]json.RawMessage
+	// found is set when the records array exists.
+	found bool
+}
+
+// NewJSONRecordDecoder creates decoder of records located at the path of object keys,
+// ex: "records" for Salesforce, "data", "items" for nested arrays. Empty path means the document is an array.
+// Missing or null array is treated as empty.
+func NewJSONRecordDecoder(body io.Reader, recordsPath ...string) *JSONRecordDecoder {
+	return &JSONRecordDecoder{
+		body:    body,
+		decoder: json.NewDecoder(body),
+		path:    recordsPath,
+	}
+}
+
+// Next returns the next record, or io.EOF when there are no more records.
+func (d *JSONRecordDecoder) Next() (map[string]any, error) {
+	if d.state == recordDecoderNotStarted {
+		if err := d.seek(); err != nil {
+			return nil, err
+		}
+	}
+
+	if d.state == recordDecoderFinished {
+		return nil, io.EOF
+	}
+
+	if !d.decoder.More() {
+		// Closing bracket of the records array.
+		if _, err := d.decoder.Token(); err != nil {
+			return nil, d.syntaxError(err)
+		}
+
+		if err := d.finish(len(d.levels) - 1); err != nil {
+			return nil, err
+		}
+
+		return nil, io.EOF
+	}
+
+	var record map[string]any
+	if err := d.decoder.Decode(&record); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("%w: %w", ErrRecordNotObject, err)
+		}
+
+		return nil, d.syntaxError(err)
+	}
+
+	if record == nil {
+		return nil, ErrRecordNotObject
+	}
+
+	return record, nil
+}
+
+// Envelope returns the document without records, the records array is left empty.
+// It is available after Next has returned io.EOF.
+func (d *JSONRecordDecoder) Envelope() (*ajson.Node, error) {
+	if d.state != recordDecoderFinished {
+		return nil, ErrRecordsNotConsumed
+	}
+
+	var child json.RawMessage
+	if d.found {
+		child = json.RawMessage("[]")
+	}
+
+	for i := len(d.levels) - 1; i >= 0; i-- {
+		level := make(map[string]json.RawMessage, len(d.levels[i])+1)
+		for key, value := range d.levels[i] {
+			level[key] = value
+		}
+
+		if child != nil {
+			level[d.path[i]] = child
+		}
+
+		data, err := json.Marshal(level)
+		if err != nil {
+			return nil, err
+		}
+
+		child = data
+	}
+
+	return ajson.Unmarshal(child)
+}
+
+// Close closes the underlying body, if it can be closed.
+func (d *JSONRecordDecoder) Close() error {
+	if closer, ok := d.body.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// seek reads the document up to the beginning of the records array.
+func (d *JSONRecordDecoder) seek() error {
+	d.state = recordDecoderStreaming
+
+	for depth, target := range d.path {
+		if err := d.expectDelim('{', depth == 0); err != nil {
+			return err
+		}
+
+		d.levels = append(d.levels, make(map[string]json.RawMessage))
+
+		found, err := d.readObject(depth, target, true)
+		if err != nil {
+			return err
+		}
+
+		if !found {
+			// Closing brace of the object without records.
+			if _, err = d.decoder.Token(); err != nil {
+				return d.syntaxError(err)
+			}
+
+			return d.finish(depth - 1)
+		}
+	}
+
+	token, err := d.decoder.Token()
+	if err != nil {
+		if len(d.path) == 0 && errors.Is(err, io.EOF) {
+			return ErrEmptyJSONHTTPResponse
+		}
+
+		return d.syntaxError(err)
+	}
+
+	switch token {
+	case json.Delim('['):
+		d.found = true
+
+		return nil
+	case nil:
+		// Null array has no records.
+		d.found = true
+
+		return d.finish(len(d.levels) - 1)
+	default:
+		return fmt.Errorf("%w: records are not an array", ErrNotJSON)
+	}
+}
+
+// readObject stores values of the object at the depth, stopping at the target key if requested.
+// Reports whether the target was found.
+func (d *JSONRecordDecoder) readObject(depth int, target string, stopAtTarget bool) (bool, error) {
+	for d.decoder.More() {
+		token, err := d.decoder.Token()
+		if err != nil {
+			return false, d.syntaxError(err)
+		}
+
+		key, _ := token.(string)
+		if stopAtTarget && key == target {
+			return true, nil
+		}
+
+		var value json.RawMessage
+		if err = d.decoder.Decode(&value); err != nil {
+			return false, d.syntaxError(err)
+		}
+
+		d.levels[depth][key] = value
+	}
+
+	return false, nil
+}
+
+// finish reads the rest of the document, closing objects from the depth up to the root.
+func (d *JSONRecordDecoder) finish(depth int) error {
+	for ; depth >= 0; depth-- {
+		if _, err := d.readObject(depth, "", false); err != nil {
+			return err
+		}
+
+		// Closing brace.
+		if _, err := d.decoder.Token(); err != nil {
+			return d.syntaxError(err)
+		}
+	}
+
+	d.state = recordDecoderFinished
+
+	return nil
+}
+
+func (d *JSONRecordDecoder) expectDelim(delim json.Delim, root bool) error {
+	token, err := d.decoder.Token()
+	if err != nil {
+		if root && errors.Is(err, io.EOF) {
+			return ErrEmptyJSONHTTPResponse
+		}
+
+		return d.syntaxError(err)
+	}
+
+	if token != delim {
+		return fmt.Errorf("%w: expected %v, got %v", ErrNotJSON, delim, token)
+	}
+
+	return nil
+}
+
+func (d *JSONRecordDecoder) syntaxError(err error) error {
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return fmt.Errorf("%w: %w", ErrNotJSON, err)
+}
+
+// GetStream makes a GET request to the given URL and returns the unread JSON body,
+// which is meant to be consumed by JSONRecordDecoder. The caller must close the body.
+// Non-2xx responses are returned as errors, the same way as by Get.
+func (j *JSONHTTPClient) GetStream(ctx context.Context, url string, headers ...Header) (io.ReadCloser, error) {
+	fullURL, err := j.HTTPClient.getURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := MakeJSONGetRequest(ctx, fullURL, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := j.HTTPClient.Stream(req) //nolint:bodyclose
+	if err != nil {
+		return nil, j.ErrorPostProcessor.handleError(err)
+	}
+
+	if res == nil {
+		return http.NoBody, nil
+	}
+
+	if err = checkJSONContentType(res); err != nil {
+		_ = res.Body.Close()
+
+		return nil, err
+	}
+
+	return res.Body, nil
+}
+
+// StreamRows reads records from the decoder one at a time and passes each of them, structured by marshalFunc,
+// to yield. Only the record being processed is held, so a page of any size is read in constant memory,
+// unless yield keeps the rows. Returns the document without records, see JSONRecordDecoder.Envelope.
+func StreamRows(
+	decoder *JSONRecordDecoder,
+	marshalFunc func([]map[string]any, []string) ([]ReadResultRow, error),
+	fields []string,
+	yield func(row ReadResultRow) error,
+) (*ajson.Node, error) {
+	for {
+		record, err := decoder.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		rows, err := marshalFunc([]map[string]any{record}, fields)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			if err = yield(row); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return decoder.Envelope()
+}
+
+// ParseStreamResult is the streaming counterpart of ParseResult. Records are read from the decoder via StreamRows,
+// while sizeFunc and nextPageFunc receive the document without records, see JSONRecordDecoder.Envelope.
+// Every row of the page is still collected into ReadResult, what is saved is the JSON tree of the whole response
+// and the copy of records it holds. Use StreamRows to process rows without keeping the page in memory.
+func ParseStreamResult(
+	decoder *JSONRecordDecoder,
+	sizeFunc func(*ajson.Node) (int64, error),
+	nextPageFunc func(*ajson.Node) (string, error),
+	marshalFunc func([]map[string]any, []string) ([]ReadResultRow, error),
+	fields []string,
+) (*ReadResult, error) {
+	rows := make([]ReadResultRow, 0)
+
+	envelope, err := StreamRows(decoder, marshalFunc, fields, func(row ReadResultRow) error {
+		rows = append(rows, row)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	totalSize, err := sizeFunc(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	nextPage, err := nextPageFunc(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReadResult{
+		Rows:     totalSize,
+		Data:     rows,
+		NextPage: NextPageToken(nextPage),
+		Done:     nextPage == "",
+	}, nil
+}
@@ -0,0 +1,259 @@
+// Package jsonschema converts object metadata to JSON Schema (draft 2020-12) and back.
+// It is an interchange format for payload validation and form generation.
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/amp-labs/connectors/common"
+)
+
+// Draft is the JSON Schema dialect produced by this package.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	ErrNotObjectSchema = errors.New("schema doesn't describe an object")
+	ErrMissingDefs     = errors.New("schema has no $defs")
+)
+
+// Schema is a subset of JSON Schema keywords needed to describe provider objects.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	UniqueItems bool               `json:"uniqueItems,omitempty"`
+	ReadOnly    bool               `json:"readOnly,omitempty"`
+	Defs        map[string]*Schema `json:"$defs,omitempty"`
+}
+
+const (
+	typeString  = "string"
+	typeBoolean = "boolean"
+	typeInteger = "integer"
+	typeNumber  = "number"
+	typeObject  = "object"
+	typeArray   = "array"
+
+	formatDate     = "date"
+	formatDateTime = "date-time"
+)
+
+// FromObjectMetadata creates schema of a single object.
+// Fields without details are described only by their title. Salesforce, HubSpot and Dynamics CRM
+// describe field types, other connectors report only titles, so their schema doesn't constrain values.
+func FromObjectMetadata(metadata common.ObjectMetadata) *Schema {
+	schema := newObjectSchema(metadata)
+	schema.Schema = Draft
+
+	return schema
+}
+
+// FromListObjectMetadata creates one document where every object is defined under $defs by its name.
+func FromListObjectMetadata(list *common.ListObjectMetadataResult) *Schema {
+	defs := make(map[string]*Schema, len(list.Result))
+	for objectName, metadata := range list.Result {
+		defs[objectName] = newObjectSchema(metadata)
+	}
+
+	return &Schema{
+		Schema: Draft,
+		Defs:   defs,
+	}
+}
+
+// ToObjectMetadata reverses FromObjectMetadata.
+func ToObjectMetadata(schema *Schema) (*common.ObjectMetadata, error) {
+	if schema == nil || (schema.Type != typeObject && schema.Properties == nil) {
+		return nil, ErrNotObjectSchema
+	}
+
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	metadata := &common.ObjectMetadata{
+		DisplayName: schema.Title,
+		FieldsMap:   make(map[string]string, len(schema.Properties)),
+	}
+
+	for fieldName, property := range schema.Properties {
+		displayName := fieldName
+		if property != nil && len(property.Title) != 0 {
+			displayName = property.Title
+		}
+
+		metadata.FieldsMap[fieldName] = displayName
+
+		field, known := toFieldMetadata(property, required[fieldName])
+		if !known {
+			continue
+		}
+
+		if metadata.Fields == nil {
+			metadata.Fields = make(map[string]common.FieldMetadata)
+		}
+
+		metadata.Fields[fieldName] = field
+	}
+
+	return metadata, nil
+}
+
+// ToListObjectMetadata reverses FromListObjectMetadata.
+func ToListObjectMetadata(schema *Schema) (*common.ListObjectMetadataResult, error) {
+	if schema == nil || schema.Defs == nil {
+		return nil, ErrMissingDefs
+	}
+
+	list := &common.ListObjectMetadataResult{
+		Result: make(map[string]common.ObjectMetadata, len(schema.Defs)),
+		Errors: make(map[string]error),
+	}
+
+	for objectName, definition := range schema.Defs {
+		metadata, err := ToObjectMetadata(definition)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", err, objectName)
+		}
+
+		list.Result[objectName] = *metadata
+	}
+
+	return list, nil
+}
+
+func newObjectSchema(metadata common.ObjectMetadata) *Schema {
+	schema := &Schema{
+		Title:      metadata.DisplayName,
+		Type:       typeObject,
+		Properties: make(map[string]*Schema, len(metadata.FieldsMap)),
+	}
+
+	for fieldName, displayName := range metadata.FieldsMap {
+		property := &Schema{}
+
+		if field, ok := metadata.Fields[fieldName]; ok {
+			property = newPropertySchema(field)
+
+			if field.Required {
+				schema.Required = append(schema.Required, fieldName)
+			}
+		}
+
+		property.Title = displayName
+		schema.Properties[fieldName] = property
+	}
+
+	// Output must be stable.
+	sort.Strings(schema.Required)
+
+	return schema
+}
+
+func newPropertySchema(field common.FieldMetadata) *Schema { // nolint:cyclop
+	property := &Schema{
+		ReadOnly: field.ReadOnly,
+	}
+
+	switch field.ValueType {
+	case common.ValueTypeString:
+		property.Type = typeString
+	case common.ValueTypeBoolean:
+		property.Type = typeBoolean
+	case common.ValueTypeInt:
+		property.Type = typeInteger
+	case common.ValueTypeFloat:
+		property.Type = typeNumber
+	case common.ValueTypeDate:
+		property.Type = typeString
+		property.Format = formatDate
+	case common.ValueTypeDateTime:
+		property.Type = typeString
+		property.Format = formatDateTime
+	case common.ValueTypeSingleSelect:
+		property.Type = typeString
+		property.Enum = field.Values
+	case common.ValueTypeMultiSelect:
+		property.Type = typeArray
+		property.UniqueItems = true
+		property.Items = &Schema{
+			Type: typeString,
+			Enum: field.Values,
+		}
+	case common.ValueTypeObject:
+		property.Type = typeObject
+	case common.ValueTypeArray:
+		property.Type = typeArray
+	case common.ValueTypeOther:
+		// any value is allowed
+	}
+
+	return property
+}
+
+// toFieldMetadata infers field details. The second return value is false when the schema
+// doesn't say anything beyond the title, which is the case for the static schema files.
+func toFieldMetadata(property *Schema, required bool) (common.FieldMetadata, bool) {
+	field := common.FieldMetadata{
+		Required: required,
+	}
+
+	if property == nil {
+		field.ValueType = common.ValueTypeOther
+
+		return field, required
+	}
+
+	field.ReadOnly = property.ReadOnly
+
+	switch property.Type {
+	case typeString:
+		field.ValueType = stringValueType(property)
+		field.Values = property.Enum
+	case typeBoolean:
+		field.ValueType = common.ValueTypeBoolean
+	case typeInteger:
+		field.ValueType = common.ValueTypeInt
+	case typeNumber:
+		field.ValueType = common.ValueTypeFloat
+	case typeObject:
+		field.ValueType = common.ValueTypeObject
+	case typeArray:
+		field.ValueType = common.ValueTypeArray
+		if property.Items != nil && len(property.Items.Enum) != 0 {
+			field.ValueType = common.ValueTypeMultiSelect
+			field.Values = property.Items.Enum
+		}
+	default:
+		if !required && !property.ReadOnly {
+			return field, false
+		}
+
+		field.ValueType = common.ValueTypeOther
+	}
+
+	return field, true
+}
+
+func stringValueType(property *Schema) common.ValueType {
+	if len(property.Enum) != 0 {
+		return common.ValueTypeSingleSelect
+	}
+
+	switch property.Format {
+	case formatDate:
+		return common.ValueTypeDate
+	case formatDateTime:
+		return common.ValueTypeDateTime
+	default:
+		return common.ValueTypeString
+	}
+}
@@ -0,0 +1,107 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/go-test/deep"
+)
+
+func TestObjectMetadataRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	metadata := common.ObjectMetadata{
+		DisplayName: "Account",
+		FieldsMap: map[string]string{
+			"name":       "Account Name",
+			"createdat":  "Created Date",
+			"industry":   "Industry",
+			"tags":       "Tags",
+			"isdeleted":  "Deleted",
+			"employees":  "Employees",
+			"annualrate": "Annual Rate",
+			"notes":      "Notes",
+		},
+		Fields: map[string]common.FieldMetadata{
+			"name":      {ValueType: common.ValueTypeString, Required: true},
+			"createdat": {ValueType: common.ValueTypeDateTime, ReadOnly: true},
+			"industry": {
+				ValueType: common.ValueTypeSingleSelect,
+				Values:    []string{"Agriculture", "Banking"},
+			},
+			"tags": {
+				ValueType: common.ValueTypeMultiSelect,
+				Values:    []string{"hot", "cold"},
+			},
+			"isdeleted":  {ValueType: common.ValueTypeBoolean},
+			"employees":  {ValueType: common.ValueTypeInt},
+			"annualrate": {ValueType: common.ValueTypeFloat},
+		},
+	}
+
+	// Schema must survive serialization.
+	data, err := json.Marshal(FromObjectMetadata(metadata))
+	if err != nil {
+		t.Fatalf("failed to marshal schema: %v", err)
+	}
+
+	var schema *Schema
+	if err = json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("failed to unmarshal schema: %v", err)
+	}
+
+	if schema.Schema != Draft || !reflect.DeepEqual(schema.Required, []string{"name"}) {
+		t.Fatalf("unexpected schema header: %s", data)
+	}
+
+	if schema.Properties["tags"].Type != typeArray || !schema.Properties["tags"].UniqueItems {
+		t.Fatalf("multi select must be an array of unique items: %s", data)
+	}
+
+	output, err := ToObjectMetadata(schema)
+	if err != nil {
+		t.Fatalf("failed to convert schema: %v", err)
+	}
+
+	if !reflect.DeepEqual(*output, metadata) {
+		t.Fatalf("round trip mismatch, diff: (%v)", deep.Equal(*output, metadata))
+	}
+}
+
+func TestToListObjectMetadataErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       *Schema
+		expectedErr error
+	}{
+		{
+			name:        "Document without definitions",
+			input:       &Schema{Schema: Draft},
+			expectedErr: ErrMissingDefs,
+		},
+		{
+			name: "Definition is not an object",
+			input: &Schema{Defs: map[string]*Schema{
+				"contacts": {Type: typeString},
+			}},
+			expectedErr: ErrNotObjectSchema,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt // rebind, omit loop side effects for parallel goroutine
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ToListObjectMetadata(tt.input)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("%s: expected Error: (%v), got: (%v)", tt.name, tt.expectedErr, err)
+			}
+		})
+	}
+}
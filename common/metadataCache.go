@@ -0,0 +1,193 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// WildcardField in ReadParams.Fields requests every field known to object metadata.
+const WildcardField = "*"
+
+// ListObjectMetadataFunc has the signature of ObjectMetadataConnector.ListObjectMetadata.
+type ListObjectMetadataFunc func(ctx context.Context, objectNames []string) (*ListObjectMetadataResult, error)
+
+// MetadataCache remembers object metadata for the lifetime of a connection,
+// so that wildcard expansion and validation don't describe the same object on every call.
+// Failures are not cached. MetadataCache is safe for concurrent use.
+type MetadataCache struct {
+	mut     sync.RWMutex
+	list    ListObjectMetadataFunc
+	objects map[string]ObjectMetadata
+}
+
+// NewMetadataCache creates empty cache which loads objects using the connector's ListObjectMetadata.
+func NewMetadataCache(list ListObjectMetadataFunc) *MetadataCache {
+	return &MetadataCache{
+		list:    list,
+		objects: make(map[string]ObjectMetadata),
+	}
+}
+
+// GetObjectMetadata returns cached metadata, describing the object on the first call.
+func (c *MetadataCache) GetObjectMetadata(ctx context.Context, objectName string) (*ObjectMetadata, error) {
+	key := strings.ToLower(objectName)
+
+	c.mut.RLock()
+	metadata, ok := c.objects[key]
+	c.mut.RUnlock()
+
+	if ok {
+		return &metadata, nil
+	}
+
+	// Describing objects doesn't change provider data, even if the provider does it via POST.
+	result, err := c.list(WithoutDryRun(ctx), []string{objectName})
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err = findObjectMetadata(result, objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mut.Lock()
+	c.objects[key] = metadata
+	c.mut.Unlock()
+
+	return &metadata, nil
+}
+
+// Invalidate forgets given objects, or everything when called without arguments.
+// Use it after the provider schema was changed, ex: custom field was added.
+func (c *MetadataCache) Invalidate(objectNames ...string) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if len(objectNames) == 0 {
+		c.objects = make(map[string]ObjectMetadata)
+
+		return
+	}
+
+	for _, objectName := range objectNames {
+		delete(c.objects, strings.ToLower(objectName))
+	}
+}
+
+// ExpandFields replaces WildcardField with every field of the object.
+// Explicitly listed fields are kept, duplicates are removed. Output is sorted for stable queries.
+// Fields without a wildcard are returned as is and no metadata is loaded.
+func (c *MetadataCache) ExpandFields(ctx context.Context, objectName string, fields []string) ([]string, error) {
+	if !HasWildcardField(fields) {
+		return fields, nil
+	}
+
+	metadata, err := c.GetObjectMetadata(ctx, objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := make(map[string]bool, len(metadata.FieldsMap)+len(fields))
+	expanded := make([]string, 0, len(metadata.FieldsMap)+len(fields))
+
+	for name := range metadata.FieldsMap {
+		registry[strings.ToLower(name)] = true
+		expanded = append(expanded, name)
+	}
+
+	sort.Strings(expanded)
+
+	for _, field := range fields {
+		if field == WildcardField || registry[strings.ToLower(field)] {
+			continue
+		}
+
+		registry[strings.ToLower(field)] = true
+		expanded = append(expanded, field)
+	}
+
+	return expanded, nil
+}
+
+// ReadFields returns ReadParams.Fields ready to be requested from the provider.
+// Wildcard is expanded, and with ReadParams.PreserveFieldCase every field known to metadata
+// is renamed to the provider's canonical name, so that output keys don't depend on the case used by the caller.
+func (c *MetadataCache) ReadFields(ctx context.Context, config ReadParams) ([]string, error) {
+	fields, err := c.ExpandFields(ctx, config.ObjectName, config.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if !config.PreserveFieldCase {
+		return fields, nil
+	}
+
+	return c.CanonicalFields(ctx, config.ObjectName, fields), nil
+}
+
+// CanonicalFields renames fields to the names used by the provider API, ex: "billingcity" becomes "BillingCity".
+// Fields unknown to metadata, including nested paths, are returned as is.
+// Renaming is best effort, when metadata cannot be loaded the fields are returned unchanged
+// and the extractor falls back to the names found in the records.
+func (c *MetadataCache) CanonicalFields(ctx context.Context, objectName string, fields []string) []string {
+	metadata, err := c.GetObjectMetadata(ctx, objectName)
+	if err != nil {
+		return fields
+	}
+
+	canonical := make(map[string]string, len(metadata.FieldsMap))
+
+	for name := range metadata.FieldsMap {
+		canonical[strings.ToLower(name)] = name
+
+		if field, ok := metadata.Fields[name]; ok && field.Name != "" {
+			canonical[strings.ToLower(name)] = field.Name
+		}
+	}
+
+	renamed := make([]string, len(fields))
+
+	for index, field := range fields {
+		renamed[index] = field
+
+		if name, ok := canonical[strings.ToLower(field)]; ok {
+			renamed[index] = name
+		}
+	}
+
+	return renamed
+}
+
+// HasWildcardField reports whether every field is requested.
+func HasWildcardField(fields []string) bool {
+	for _, field := range fields {
+		if field == WildcardField {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findObjectMetadata picks the object from the result. Some connectors key objects in lowercase.
+func findObjectMetadata(result *ListObjectMetadataResult, objectName string) (ObjectMetadata, error) {
+	if result == nil {
+		return ObjectMetadata{}, fmt.Errorf("%w: %s", ErrMetadataLoadFailure, objectName)
+	}
+
+	for _, key := range []string{objectName, strings.ToLower(objectName)} {
+		if metadata, ok := result.Result[key]; ok {
+			return metadata, nil
+		}
+
+		if err, ok := result.Errors[key]; ok && err != nil {
+			return ObjectMetadata{}, fmt.Errorf("%w: %s: %w", ErrMetadataLoadFailure, objectName, err)
+		}
+	}
+
+	return ObjectMetadata{}, fmt.Errorf("%w: %s", ErrMetadataLoadFailure, objectName)
+}
@@ -1,13 +1,25 @@
 package common
 
 import (
+	"bytes"
 	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
-	"sync"
+	"time"
 
 	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/oauth2/jwt"
 )
 
+// tokenSaveTimeout bounds saving of refreshed token into TokenStore, so that a slow store cannot block requests.
+const tokenSaveTimeout = 30 * time.Second
+
 type OAuthOption func(*oauthClientParams)
 
 // NewOAuthHTTPClient returns a new http client, with automatic OAuth authentication. Specifically
@@ -25,17 +37,36 @@ func NewOAuthHTTPClient(ctx context.Context, opts ...OAuthOption) (Authenticated
 		return nil, err
 	}
 
+	if params.store != nil {
+		if err = params.useTokenStore(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	return newOAuthClient(ctx, params), nil
 }
 
 // oauthClientParams is the internal configuration for the oauth http client.
 type oauthClientParams struct {
-	client       *http.Client
-	token        *oauth2.Token
-	config       *oauth2.Config
-	tokenSource  oauth2.TokenSource
+	client      *http.Client
+	token       *oauth2.Token
+	config      *oauth2.Config
+	tokenSource oauth2.TokenSource
+	// grant obtains a new token without user interaction, ex: client credentials or JWT bearer grant.
+	grant func(ctx context.Context) (*oauth2.Token, error)
+	// checkGrant validates grant configuration, it is optional.
+	checkGrant   func() error
 	tokenUpdated func(oldToken, newToken *oauth2.Token) error
 	debug        func(req *http.Request, rsp *http.Response)
+	expirySkew   *time.Duration
+	// grantLifetime is the expiry of grant tokens issued without one.
+	grantLifetime time.Duration
+	refreshLock   TokenRefreshLock
+	reload        TokenReloadFunc
+	store         TokenStore
+	storeKey      string
+	// keepUnauthorized disables refresh and retry of requests rejected with 401.
+	keepUnauthorized bool
 }
 
 // WithOAuthClient sets the http client to use for the connector. Its usage is optional.
@@ -71,7 +102,7 @@ func WithOAuthDebug(f func(req *http.Request, rsp *http.Response)) OAuthOption {
 
 // WithTokenUpdated sets the function to call whenever the oauth token is updated.
 // This is useful for persisting the refreshed tokens somewhere, so that it can be
-// used later. It's optional.
+// used later. It's optional. Failure is logged, the refreshed token is used regardless.
 func WithTokenUpdated(onTokenUpdated func(oldToken, newToken *oauth2.Token) error) OAuthOption {
 	return func(params *oauthClientParams) {
 		params.tokenUpdated = onTokenUpdated
@@ -86,14 +117,113 @@ func WithTokenSource(tokenSource oauth2.TokenSource) OAuthOption {
 	}
 }
 
+// WithClientCredentials authenticates the client itself, rather than a user, using client credentials grant.
+// A new token is requested whenever the current one expires, WithOAuthToken is optional.
+func WithClientCredentials(config *clientcredentials.Config) OAuthOption {
+	return func(params *oauthClientParams) {
+		params.grant = func(ctx context.Context) (*oauth2.Token, error) {
+			return config.Token(ctx)
+		}
+		params.checkGrant = nil
+	}
+}
+
+// WithJWTBearer obtains tokens using JWT bearer grant (RFC 7523), where the assertion is signed with
+// RSA private key in PEM format, ex: Salesforce server-to-server integration or Docusign JWT grant.
+// A new assertion is signed whenever the current token expires, WithOAuthToken is optional.
+// Assertion is valid for DefaultJWTAssertionExpiry unless config.Expires is set.
+func WithJWTBearer(config *jwt.Config) OAuthOption {
+	return func(params *oauthClientParams) {
+		if config.Expires == 0 {
+			withExpiry := *config
+			withExpiry.Expires = DefaultJWTAssertionExpiry
+			config = &withExpiry
+		}
+
+		params.grant = func(ctx context.Context) (*oauth2.Token, error) {
+			// Source is created for every request, otherwise it would return its cached token.
+			return config.TokenSource(ctx).Token()
+		}
+		params.checkGrant = func() error {
+			return validatePrivateKey(config.PrivateKey)
+		}
+	}
+}
+
+// WithTokenExpirySkew sets how long before the expiry the token is refreshed, DefaultTokenExpirySkew is used
+// otherwise. Larger skew avoids requests failing because the token expired on the way to the provider.
+// Zero skew uses the token until it expires.
+func WithTokenExpirySkew(skew time.Duration) OAuthOption {
+	return func(params *oauthClientParams) {
+		params.expirySkew = &skew
+	}
+}
+
+// WithGrantTokenLifetime sets how long tokens obtained with client credentials or JWT bearer grant are used
+// when the provider doesn't report their expiry, ex: Salesforce JWT bearer grant. DefaultGrantTokenLifetime is
+// used otherwise. Tokens are refreshed earlier if the provider rejects them with 401.
+func WithGrantTokenLifetime(lifetime time.Duration) OAuthOption {
+	return func(params *oauthClientParams) {
+		params.grantLifetime = lifetime
+	}
+}
+
+// WithTokenRefreshLock makes refresh run under the lock shared by processes using the same connection.
+// Once the lock is acquired, reload is called to get the token another process may have refreshed meanwhile,
+// it should return what WithTokenUpdated has persisted. Reload is optional.
+func WithTokenRefreshLock(lock TokenRefreshLock, reload TokenReloadFunc) OAuthOption {
+	return func(params *oauthClientParams) {
+		params.refreshLock = lock
+		params.reload = reload
+	}
+}
+
+// WithTokenStore makes the client read the initial token from the store and save refreshed tokens back.
+// Stored token takes precedence over WithOAuthToken, which is saved when the store has none yet.
+// Store is also used to reload the token under WithTokenRefreshLock, unless reload is given explicitly.
+func WithTokenStore(store TokenStore, key string) OAuthOption {
+	return func(params *oauthClientParams) {
+		params.store = store
+		params.storeKey = key
+	}
+}
+
+// WithRefreshOnUnauthorized controls whether a request rejected with 401 is retried once with refreshed token.
+// It is enabled by default, a token can be revoked or rotated before it expires.
+func WithRefreshOnUnauthorized(enabled bool) OAuthOption {
+	return func(params *oauthClientParams) {
+		params.keepUnauthorized = !enabled
+	}
+}
+
 // prepare finalizes and validates the connector configuration, and returns an error if it's invalid.
 func (p *oauthClientParams) prepare() (*oauthClientParams, error) {
 	if p.client == nil {
 		p.client = http.DefaultClient
 	}
 
+	if p.expirySkew == nil {
+		skew := DefaultTokenExpirySkew
+		p.expirySkew = &skew
+	}
+
+	if p.grant != nil {
+		if p.grantLifetime <= 0 {
+			p.grantLifetime = DefaultGrantTokenLifetime
+		}
+
+		if p.checkGrant != nil {
+			if err := p.checkGrant(); err != nil {
+				return nil, err
+			}
+		}
+
+		return p, nil
+	}
+
 	if p.tokenSource == nil {
-		if p.token == nil {
+		// Token can come from the store, it is checked once the store is read.
+		if p.token == nil && p.store == nil {
 			return nil, ErrMissingRefreshToken
 		}
 
@@ -105,71 +235,174 @@ func (p *oauthClientParams) prepare() (*oauthClientParams, error) {
 	return p, nil
 }
 
+// validatePrivateKey checks the key early, instead of failing on the first request.
+func validatePrivateKey(data []byte) error {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return ErrInvalidPrivateKey
+	}
+
+	if _, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPrivateKey, err)
+	}
+
+	if _, ok := key.(*rsa.PrivateKey); !ok {
+		return fmt.Errorf("%w: got %T", ErrInvalidPrivateKey, key)
+	}
+
+	return nil
+}
+
+// useTokenStore loads the initial token from the store and chains saving into tokenUpdated.
+func (p *oauthClientParams) useTokenStore(ctx context.Context) error {
+	stored, err := p.store.Load(ctx, p.storeKey)
+
+	switch {
+	case err == nil:
+		p.token = stored
+	case !errors.Is(err, ErrTokenNotFound):
+		return err
+	case p.token != nil:
+		if err = p.store.Save(ctx, p.storeKey, p.token); err != nil {
+			return err
+		}
+	case p.tokenSource == nil && p.grant == nil:
+		return ErrMissingRefreshToken
+	}
+
+	onUpdated := p.tokenUpdated
+	p.tokenUpdated = func(oldToken, newToken *oauth2.Token) error {
+		// Refresh may run in the background of any request, so its context is not available here.
+		ctx, cancel := context.WithTimeout(context.Background(), tokenSaveTimeout)
+		defer cancel()
+
+		if err := p.store.Save(ctx, p.storeKey, newToken); err != nil {
+			return err
+		}
+
+		if onUpdated != nil {
+			return onUpdated(oldToken, newToken)
+		}
+
+		return nil
+	}
+
+	if p.reload == nil {
+		p.reload = func(ctx context.Context) (*oauth2.Token, error) {
+			token, err := p.store.Load(ctx, p.storeKey)
+			if errors.Is(err, ErrTokenNotFound) {
+				return nil, nil // nolint:nilnil
+			}
+
+			return token, err
+		}
+	}
+
+	return nil
+}
+
 // newHTTPClient returns a new http client for the connector, with automatic OAuth authentication.
 func newOAuthClient(ctx context.Context, params *oauthClientParams) AuthenticatedHTTPClient { //nolint:ireturn
 	// This is how the key refresher accepts a custom http client
 	ctx = context.WithValue(ctx, oauth2.HTTPClient, params.client)
 
-	tokenSource := getTokenSource(ctx, params)
-	if params.tokenUpdated != nil {
-		tokenSource = &observableTokenSource{
-			tokenUpdated: params.tokenUpdated,
-			lastKnown:    params.token,
-			tokenSource:  tokenSource,
-		}
-	}
-
 	// Returns a new client which automatically refreshes the access token
 	// whenever the current one expires.
 	return &http.Client{
 		Transport: &oauth2Transport{
-			Source: tokenSource,
-			Base:   params.client.Transport,
-			Debug:  params.debug,
+			Source:            newRefreshingTokenSource(ctx, params),
+			Base:              params.client.Transport,
+			Debug:             params.debug,
+			RetryUnauthorized: !params.keepUnauthorized,
 		},
 	}
 }
 
 type oauth2Transport struct {
-	Source oauth2.TokenSource
+	Source *refreshingTokenSource
 	Base   http.RoundTripper
 	Debug  func(req *http.Request, rsp *http.Response)
+	// RetryUnauthorized resends request rejected with 401 once, with refreshed token.
+	RetryUnauthorized bool
 }
 
 func (t *oauth2Transport) RoundTrip(req *http.Request) (*http.Response, error) {
-	reqBodyClosed := false
+	token, err := t.Source.tokenContext(req.Context())
+	if err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
 
-	if req.Body != nil {
-		defer func() {
-			if !reqBodyClosed {
-				_ = req.Body.Close()
-			}
-		}()
+		return nil, err
 	}
 
-	token, err := t.Source.Token()
-	if err != nil {
-		return nil, err
+	rsp, err := t.send(req, token)
+	if err != nil || rsp.StatusCode != http.StatusUnauthorized || !t.RetryUnauthorized {
+		return rsp, err
+	}
+
+	retry, ok := rewindRequest(req)
+	if !ok {
+		// Body was consumed and cannot be sent again.
+		return rsp, nil
 	}
 
+	refreshed, err := t.Source.refreshRejected(req.Context(), token)
+	if err != nil || refreshed.AccessToken == token.AccessToken {
+		// Provider rejects the token for other reasons, the original response is returned.
+		return rsp, nil //nolint:nilerr
+	}
+
+	_, _ = io.Copy(io.Discard, rsp.Body)
+	_ = rsp.Body.Close()
+
+	return t.send(retry, refreshed)
+}
+
+// send authorizes a copy of the request with the token. Request body is closed by the base RoundTripper.
+func (t *oauth2Transport) send(req *http.Request, token *oauth2.Token) (*http.Response, error) {
 	req2 := cloneRequest(req) // per RoundTripper contract
 	token.SetAuthHeader(req2)
 
-	// req.Body is assumed to be closed by the base RoundTripper.
-	reqBodyClosed = true
-
 	rsp, err := t.base().RoundTrip(req2)
 	if err != nil {
 		return rsp, err
 	}
 
 	if t.Debug != nil {
-		t.Debug(req2, cloneResponse(rsp))
+		t.Debug(req2, cloneResponseWithBody(rsp))
 	}
 
 	return rsp, nil
 }
 
+// rewindRequest returns a copy of the request with the body at the beginning, if the body can be read again.
+func rewindRequest(req *http.Request) (*http.Request, bool) {
+	retry := cloneRequest(req)
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return retry, true
+	}
+
+	if req.GetBody == nil {
+		return nil, false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+
+	retry.Body = body
+
+	return retry, true
+}
+
 func (t *oauth2Transport) base() http.RoundTripper {
 	if t.Base != nil {
 		return t.Base
@@ -206,48 +439,53 @@ func cloneResponse(r *http.Response) *http.Response {
 	return r2
 }
 
-func getTokenSource(ctx context.Context, params *oauthClientParams) oauth2.TokenSource { //nolint:ireturn
-	if params.tokenSource != nil {
-		return params.tokenSource
+// cloneResponseWithBody copies the response for debug callbacks. At most DebugBodyLimit bytes of the body
+// are buffered, so that both the callback and the caller can read it. The caller gets the whole body,
+// the callback gets the buffered part, followed by ErrDebugBodyTruncated if the body is longer.
+func cloneResponseWithBody(r *http.Response) *http.Response {
+	r2 := cloneResponse(r)
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return r2
 	}
 
-	return params.config.TokenSource(ctx, params.token)
-}
+	data, err := io.ReadAll(io.LimitReader(r.Body, DebugBodyLimit+1))
+	if err == nil && len(data) > DebugBodyLimit {
+		err = ErrDebugBodyTruncated
+	}
 
-type observableTokenSource struct {
-	mut          sync.Mutex
-	tokenUpdated func(oldToken, newToken *oauth2.Token) error
-	lastKnown    *oauth2.Token
-	tokenSource  oauth2.TokenSource
-}
+	r.Body = prependBody(data, r.Body)
+	r2.Body = &bufferedBody{reader: bytes.NewReader(data[:min(len(data), DebugBodyLimit)]), err: err}
 
-func (w *observableTokenSource) Token() (*oauth2.Token, error) {
-	w.mut.Lock()
-	defer w.mut.Unlock()
+	return r2
+}
 
-	tok, err := w.tokenSource.Token()
-	if err != nil {
-		return nil, err
+// prependBody returns body which reads the data first and then the rest of the original body.
+func prependBody(data []byte, body io.ReadCloser) io.ReadCloser {
+	return struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: io.MultiReader(bytes.NewReader(data), body),
+		Closer: body,
 	}
+}
 
-	if w.HasChanged(tok) {
-		if err := w.tokenUpdated(w.lastKnown, tok); err != nil {
-			return nil, err
-		}
+// bufferedBody replays body which was read in advance, including the error that interrupted reading.
+type bufferedBody struct {
+	reader *bytes.Reader
+	err    error
+}
 
-		w.lastKnown = tok
+func (b *bufferedBody) Read(p []byte) (int, error) {
+	n, err := b.reader.Read(p)
+	if errors.Is(err, io.EOF) && b.err != nil {
+		return n, b.err
 	}
 
-	return tok, nil
+	return n, err
 }
 
-func (w *observableTokenSource) HasChanged(tok *oauth2.Token) bool {
-	if w.lastKnown == nil {
-		return true
-	}
-
-	return w.lastKnown.AccessToken == tok.AccessToken ||
-		w.lastKnown.RefreshToken == tok.RefreshToken ||
-		w.lastKnown.TokenType == tok.TokenType ||
-		w.lastKnown.Expiry.Equal(tok.Expiry)
+func (b *bufferedBody) Close() error {
+	return nil
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/amp-labs/connectors/common"
 	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 // ParamAssurance checks that param data is valid
@@ -54,6 +55,25 @@ func (p *Client) WithClient(
 	p.WithAuthenticatedClient(oauthClient)
 }
 
+// WithClientCredentialsClient sets up client authenticated with client credentials grant.
+func (p *Client) WithClientCredentialsClient(
+	ctx context.Context, client *http.Client,
+	config *clientcredentials.Config,
+	opts ...common.OAuthOption,
+) {
+	options := []common.OAuthOption{
+		common.WithOAuthClient(client),
+		common.WithClientCredentials(config),
+	}
+
+	oauthClient, err := common.NewOAuthHTTPClient(ctx, append(options, opts...)...)
+	if err != nil {
+		panic(err) // caught in NewConnector
+	}
+
+	p.WithAuthenticatedClient(oauthClient)
+}
+
 func (p *Client) WithAuthenticatedClient(client common.AuthenticatedHTTPClient) {
 	p.Caller = &common.HTTPClient{
 		Client:       client,
@@ -106,3 +126,57 @@ func (a APIModule) String() string {
 
 	return fmt.Sprintf("%s/%s", a.Label, a.Version)
 }
+
+// Idempotency params sets up the store which emulates idempotency keys for providers without native support.
+// The store is optional, without it WriteParams.IdempotencyKey is refused.
+type Idempotency struct {
+	store common.IdempotencyStore
+}
+
+func (p *Idempotency) ValidateParams() error {
+	// store may be omitted
+	return nil
+}
+
+// WithIdempotencyStore sets the store used to remember records created with WriteParams.IdempotencyKey.
+// It is required to create records with the key. Share the store between connectors of the same connection.
+func (p *Idempotency) WithIdempotencyStore(store common.IdempotencyStore) {
+	p.store = store
+}
+
+// IdempotencyStore returns configured store, it is nil when none was set.
+func (p *Idempotency) IdempotencyStore() common.IdempotencyStore {
+	return p.store
+}
+
+// Interceptors params sets up middleware around every request of the connector.
+// Interceptors and circuit breaker are optional, they are installed on the client once connector is created.
+type Interceptors struct {
+	List           []common.Interceptor
+	CircuitBreaker *common.CircuitBreaker
+}
+
+func (p *Interceptors) ValidateParams() error {
+	// interceptors may be omitted
+	return nil
+}
+
+// WithInterceptors adds middleware around every request made by the connector.
+func (p *Interceptors) WithInterceptors(interceptors ...common.Interceptor) {
+	p.List = append(p.List, interceptors...)
+}
+
+// WithCircuitBreaker stops requests while the provider is failing.
+// Use common.CircuitBreakerRegistry to share the breaker between connectors of the provider or connection.
+func (p *Interceptors) WithCircuitBreaker(breaker *common.CircuitBreaker) {
+	p.CircuitBreaker = breaker
+}
+
+// ApplyInterceptors installs interceptors on the client, after any it already has, and the circuit breaker.
+func (p *Interceptors) ApplyInterceptors(client *common.HTTPClient) {
+	client.Interceptors = append(client.Interceptors, p.List...)
+
+	if p.CircuitBreaker != nil {
+		client.CircuitBreaker = p.CircuitBreaker
+	}
+}
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/spyzhov/ajson"
@@ -8,6 +9,9 @@ import (
 
 type NextPageFunc func(*ajson.Node) (string, error)
 
+// MarshalFunc structures provider records into ReadResultRows, fields are the ones requested by ReadParams.
+type MarshalFunc func(records []map[string]any, fields []string) ([]ReadResultRow, error)
+
 // ParseResult parses the response from a provider into a ReadResult. A 2xx return type is assumed.
 // The sizeFunc, recordsFunc, nextPageFunc, and marshalFunc are used to extract the relevant data from the response.
 // The sizeFunc returns the total number of records in the response.
@@ -57,8 +61,26 @@ func ParseResult(
 	}, nil
 }
 
+// FieldsExtractor returns requested fields of a record, keyed according to the chosen naming mode.
+type FieldsExtractor func(fields []string, record map[string]interface{}) map[string]interface{}
+
+// FieldsExtractorFor picks the extractor matching ReadParams.PreserveFieldCase.
+func FieldsExtractorFor(params ReadParams) FieldsExtractor {
+	if params.PreserveFieldCase {
+		return ExtractFieldsFromRaw
+	}
+
+	return ExtractLowercaseFieldsFromRaw
+}
+
 // ExtractLowercaseFieldsFromRaw returns a map of fields from a record.
 // The fields are all returned in lowercase.
+//
+// Field can be a path into nested objects and arrays using dots and brackets,
+// ex: "attributes.name", "Account.Name", "emails[0].address", "custom['first.name']".
+// Keys are matched ignoring the case at every level. A top level key that literally matches
+// the whole path takes precedence. The output key is always the requested field in lowercase.
+// WildcardField, when it wasn't expanded by the connector, selects every top level field.
 func ExtractLowercaseFieldsFromRaw(fields []string, record map[string]interface{}) map[string]interface{} {
 	out := make(map[string]interface{}, len(fields))
 
@@ -69,13 +91,189 @@ func ExtractLowercaseFieldsFromRaw(fields []string, record map[string]interface{
 	}
 
 	for _, field := range fields {
+		if field == WildcardField {
+			// Connector couldn't expand the wildcard, every field of the record is returned.
+			for key, value := range lowercaseRecord {
+				out[key] = value
+			}
+
+			continue
+		}
+
 		// Lowercase the field name to make lookup case-insensitive.
 		lowercaseField := strings.ToLower(field)
 
 		if value, ok := lowercaseRecord[lowercaseField]; ok {
 			out[lowercaseField] = value
+
+			continue
+		}
+
+		if value, _, ok := lookupFieldPath(record, parseFieldPath(field)); ok {
+			out[lowercaseField] = value
+		}
+	}
+
+	return out
+}
+
+// ExtractFieldsFromRaw returns a map of fields from a record.
+// Unlike ExtractLowercaseFieldsFromRaw, the fields are returned under the names used by the provider,
+// which are the canonical API names, ex: requested "billingcity" is returned as "BillingCity".
+// Keys are matched ignoring the case, but exact match is preferred, so fields differing only in case stay distinct.
+// Nested paths are returned with every segment in provider case, ex: "account.name" becomes "Account.Name".
+func ExtractFieldsFromRaw(fields []string, record map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(fields))
+
+	for _, field := range fields {
+		if field == WildcardField {
+			for key, value := range record {
+				out[key] = value
+			}
+
+			continue
+		}
+
+		if name, value, ok := lookupKey(record, field); ok {
+			out[name] = value
+
+			continue
+		}
+
+		if value, path, ok := lookupFieldPath(record, parseFieldPath(field)); ok {
+			out[path] = value
 		}
 	}
 
 	return out
 }
+
+// fieldPathSegment is either an object key or an array index.
+type fieldPathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseFieldPath splits field into segments. Field without dots and brackets is a single segment.
+// Brackets may hold an array index, or a quoted key which itself may contain dots.
+func parseFieldPath(field string) []fieldPathSegment { // nolint:cyclop
+	segments := make([]fieldPathSegment, 0)
+	current := strings.Builder{}
+
+	flush := func() {
+		if current.Len() != 0 {
+			segments = append(segments, fieldPathSegment{key: current.String()})
+			current.Reset()
+		}
+	}
+
+	for pos := 0; pos < len(field); pos++ {
+		switch field[pos] {
+		case '.':
+			flush()
+		case '[':
+			end := strings.IndexByte(field[pos:], ']')
+			if end == -1 {
+				// Not a bracket expression, treat the rest literally.
+				current.WriteString(field[pos:])
+				pos = len(field)
+
+				continue
+			}
+
+			flush()
+
+			inner := field[pos+1 : pos+end]
+			pos += end
+
+			if index, err := strconv.Atoi(inner); err == nil {
+				segments = append(segments, fieldPathSegment{index: index, isIndex: true})
+
+				continue
+			}
+
+			segments = append(segments, fieldPathSegment{key: strings.Trim(inner, `"'`)})
+		default:
+			current.WriteByte(field[pos])
+		}
+	}
+
+	flush()
+
+	return segments
+}
+
+// lookupFieldPath resolves segments and returns the value together with the path written in provider case.
+func lookupFieldPath(value any, segments []fieldPathSegment) (any, string, bool) {
+	if len(segments) == 0 {
+		return nil, "", false
+	}
+
+	path := strings.Builder{}
+
+	for _, segment := range segments {
+		var ok bool
+
+		if segment.isIndex {
+			value, ok = lookupIndex(value, segment.index)
+			path.WriteString("[" + strconv.Itoa(segment.index) + "]")
+		} else {
+			var name string
+
+			name, value, ok = lookupKey(value, segment.key)
+			if path.Len() != 0 {
+				path.WriteString(".")
+			}
+
+			path.WriteString(name)
+		}
+
+		if !ok {
+			return nil, "", false
+		}
+	}
+
+	return value, path.String(), true
+}
+
+func lookupIndex(value any, index int) (any, bool) {
+	array, ok := value.([]any)
+	if !ok || index < 0 || index >= len(array) {
+		return nil, false
+	}
+
+	return array[index], true
+}
+
+// lookupKey finds key in the object and returns it as named by the object.
+// Exact match is preferred over case-insensitive one. When several keys differ from the requested one
+// only by case, the lexicographically smallest is chosen, so the result doesn't depend on map iteration order.
+func lookupKey(value any, key string) (string, any, bool) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return "", nil, false
+	}
+
+	if result, ok := object[key]; ok {
+		return key, result, true
+	}
+
+	var (
+		match string
+		found bool
+	)
+
+	for name := range object {
+		if strings.EqualFold(name, key) && (!found || name < match) {
+			match = name
+			found = true
+		}
+	}
+
+	if !found {
+		return "", nil, false
+	}
+
+	return match, object[match], true
+}
@@ -0,0 +1,245 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/subchen/go-xmldom"
+)
+
+const (
+	// SOAPEnvelopeNamespace is the SOAP 1.1 envelope namespace.
+	SOAPEnvelopeNamespace = "http://schemas.xmlsoap.org/soap/envelope/"
+
+	soapPrefix = "soapenv"
+)
+
+var (
+	// ErrSOAPFault is matched by every SOAPFault, use errors.As to get the details.
+	ErrSOAPFault = errors.New("SOAP fault")
+	// ErrNoSOAPBody is returned when the response is not a SOAP envelope.
+	ErrNoSOAPBody = errors.New("SOAP envelope has no body")
+)
+
+// SOAPFault is the error reported by SOAP API inside the envelope body.
+// Both SOAP 1.1 and SOAP 1.2 faults are recognized.
+type SOAPFault struct {
+	// Code identifies the fault, ex: "soapenv:Client", "sf:INVALID_SESSION_ID".
+	Code string
+	// Message is human-readable explanation.
+	Message string
+	// Actor is the node that caused the fault, it is usually empty.
+	Actor string
+	// Detail holds application specific information, if any.
+	Detail *xmldom.Node
+}
+
+func (f *SOAPFault) Error() string {
+	if len(f.Code) == 0 {
+		return fmt.Sprintf("%v: %s", ErrSOAPFault, f.Message)
+	}
+
+	return fmt.Sprintf("%v %s: %s", ErrSOAPFault, f.Code, f.Message)
+}
+
+func (f *SOAPFault) Is(target error) bool {
+	return target == ErrSOAPFault //nolint:errorlint,goerr113
+}
+
+// HasCode reports whether the fault code matches ignoring the namespace prefix,
+// ex: "INVALID_SESSION_ID" matches "sf:INVALID_SESSION_ID".
+func (f *SOAPFault) HasCode(code string) bool {
+	_, local, found := strings.Cut(f.Code, ":")
+	if !found {
+		local = f.Code
+	}
+
+	return local == code
+}
+
+// ParseSOAPFault returns the fault found in SOAP response, or nil if the document is not a fault.
+func ParseSOAPFault(body []byte) *SOAPFault {
+	doc, err := xmldom.Parse(bytes.NewReader(body))
+	if err != nil || doc.Root == nil {
+		return nil
+	}
+
+	return findSOAPFault(doc.Root)
+}
+
+func findSOAPFault(envelope *xmldom.Node) *SOAPFault {
+	if envelope.Name != "Envelope" {
+		return nil
+	}
+
+	soapBody := envelope.GetChild("Body")
+	if soapBody == nil {
+		return nil
+	}
+
+	node := soapBody.GetChild("Fault")
+	if node == nil {
+		return nil
+	}
+
+	fault := &SOAPFault{}
+
+	if code := node.GetChild("faultcode"); code != nil {
+		// SOAP 1.1
+		fault.Code = code.Text
+		fault.Message = childText(node, "faultstring")
+		fault.Actor = childText(node, "faultactor")
+		fault.Detail = node.GetChild("detail")
+
+		return fault
+	}
+
+	// SOAP 1.2
+	if code := node.GetChild("Code"); code != nil {
+		fault.Code = childText(code, "Value")
+	}
+
+	if reason := node.GetChild("Reason"); reason != nil {
+		fault.Message = childText(reason, "Text")
+	}
+
+	fault.Actor = childText(node, "Role")
+	fault.Detail = node.GetChild("Detail")
+
+	return fault
+}
+
+func childText(node *xmldom.Node, name string) string {
+	if child := node.GetChild(name); child != nil {
+		return child.Text
+	}
+
+	return ""
+}
+
+// InterpretSOAPError is an ErrorHandler for SOAP APIs. Faults are returned as SOAPFault,
+// wrapped into the error matching HTTP status, as by InterpretError. Other responses are passed to InterpretError.
+func InterpretSOAPError(res *http.Response, body []byte) error {
+	fault := ParseSOAPFault(body)
+	if fault == nil {
+		return InterpretError(res, body)
+	}
+
+	return NewHTTPStatusError(res.StatusCode, fmt.Errorf("%w: %w", statusCategory(res.StatusCode), fault))
+}
+
+// SOAPEnvelope is a SOAP 1.1 request, ex:
+//
+//	<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/">
+//		<soapenv:Header xmlns="urn:example">...</soapenv:Header>
+//		<soapenv:Body xmlns="urn:example">...</soapenv:Body>
+//	</soapenv:Envelope>
+type SOAPEnvelope struct {
+	// Namespace is the default namespace of header and body entries. Optional.
+	Namespace string
+	// Headers are entries of the Header element, it is omitted when empty.
+	Headers []*xmldom.Node
+	// Body holds the operation.
+	Body []*xmldom.Node
+}
+
+// Document returns the envelope as XML document.
+func (e SOAPEnvelope) Document() *xmldom.Document {
+	doc := xmldom.NewDocument(soapPrefix + ":Envelope")
+	doc.Root.SetAttributeValue("xmlns:"+soapPrefix, SOAPEnvelopeNamespace)
+	doc.Root.SetAttributeValue("xmlns:xsd", "http://www.w3.org/2001/XMLSchema")
+	doc.Root.SetAttributeValue("xmlns:xsi", "http://www.w3.org/2001/XMLSchema-instance")
+
+	if len(e.Headers) != 0 {
+		doc.Root.AppendChild(e.element("Header", e.Headers))
+	}
+
+	doc.Root.AppendChild(e.element("Body", e.Body))
+
+	return doc
+}
+
+func (e SOAPEnvelope) element(name string, children []*xmldom.Node) *xmldom.Node {
+	node := &xmldom.Node{
+		Name:     soapPrefix + ":" + name,
+		Children: children,
+	}
+
+	if len(e.Namespace) != 0 {
+		node.SetAttributeValue("xmlns", e.Namespace)
+	}
+
+	return node
+}
+
+// SOAPHeaderFunc creates SOAP header entry for every call,
+// ex: session header with the current access token, which can change between calls.
+type SOAPHeaderFunc func(ctx context.Context) (*xmldom.Node, error)
+
+// SOAPClient calls operations of SOAP 1.1 API. Use InterpretSOAPError as ErrorHandler
+// of the underlying HTTPClient to get faults as SOAPFault.
+type SOAPClient struct {
+	XMLClient *XMLHTTPClient // Required.
+	// Namespace is the default namespace of header and body entries. Optional.
+	Namespace string
+	// Headers are added to every envelope, in order. Optional.
+	Headers []SOAPHeaderFunc
+}
+
+// Call posts the envelope with the operation to the endpoint and returns the content of response Body element.
+// Action is sent as SOAPAction header. Faults returned with 2xx status are reported as SOAPFault as well.
+func (c *SOAPClient) Call(ctx context.Context,
+	url string, action string, operation ...*xmldom.Node,
+) (*xmldom.Node, error) {
+	headers := make([]*xmldom.Node, 0, len(c.Headers))
+
+	for _, headerFunc := range c.Headers {
+		header, err := headerFunc(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if header != nil {
+			headers = append(headers, header)
+		}
+	}
+
+	envelope := SOAPEnvelope{
+		Namespace: c.Namespace,
+		Headers:   headers,
+		Body:      operation,
+	}
+
+	res, err := c.XMLClient.Post(ctx, url, envelope.Document(),
+		Header{Key: "Content-Type", Value: "text/xml; charset=utf-8"},
+		Header{Key: "Accept", Value: "text/xml"},
+		Header{Key: "SOAPAction", Value: action},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, ErrNoSOAPBody
+	}
+
+	root, err := res.GetRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	if fault := findSOAPFault(root); fault != nil {
+		return nil, fault
+	}
+
+	soapBody := root.GetChild("Body")
+	if root.Name != "Envelope" || soapBody == nil {
+		return nil, ErrNoSOAPBody
+	}
+
+	return soapBody, nil
+}
@@ -0,0 +1,247 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) { // nolint:funlen
+	t.Parallel()
+
+	var (
+		received atomic.Int32
+		healthy  atomic.Bool
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var (
+		mut sync.Mutex
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	clock := func() time.Time {
+		mut.Lock()
+		defer mut.Unlock()
+
+		return now
+	}
+
+	registry := common.NewCircuitBreakerRegistry(
+		common.WithFailureThreshold(3),
+		common.WithCooldown(time.Minute),
+		common.WithCircuitClock(clock),
+	)
+	key := common.CircuitKey{Provider: "salesforce", Connection: "acme"}
+	breaker := registry.Get(key)
+	require.Same(t, breaker, registry.Get(key))
+
+	client := &common.HTTPClient{
+		Base:           server.URL,
+		Client:         http.DefaultClient,
+		CircuitBreaker: breaker,
+	}
+	ctx := context.Background()
+
+	// Client errors don't count, they reset failures.
+	for i := 0; i < 2; i++ {
+		_, _, err := client.Get(ctx, "/accounts")
+		require.ErrorIs(t, err, common.ErrServer)
+	}
+
+	_, _, err := client.Get(ctx, "/missing")
+	require.Error(t, err)
+	require.Equal(t, 0, breaker.Snapshot().ConsecutiveFailures)
+
+	// Consecutive server errors open the circuit.
+	for i := 0; i < 3; i++ {
+		_, _, err = client.Get(ctx, "/accounts")
+		require.ErrorIs(t, err, common.ErrServer)
+	}
+
+	require.Equal(t, common.CircuitOpen, breaker.State())
+	require.Equal(t, int32(6), received.Load())
+
+	_, _, err = client.Get(ctx, "/accounts")
+	require.ErrorIs(t, err, common.ErrCircuitOpen)
+	require.Equal(t, int32(6), received.Load())
+
+	// After the cooldown failed probe reopens the circuit.
+	mut.Lock()
+	now = now.Add(time.Minute)
+	mut.Unlock()
+
+	require.Equal(t, common.CircuitHalfOpen, breaker.State())
+
+	_, _, err = client.Get(ctx, "/accounts")
+	require.ErrorIs(t, err, common.ErrServer)
+	require.Equal(t, common.CircuitOpen, breaker.State())
+	require.Equal(t, int32(7), received.Load())
+
+	// Successful probe closes it.
+	mut.Lock()
+	now = now.Add(time.Minute)
+	mut.Unlock()
+	healthy.Store(true)
+
+	_, _, err = client.Get(ctx, "/accounts")
+	require.NoError(t, err)
+
+	snapshot := registry.Snapshot()
+	require.Equal(t, common.CircuitClosed, snapshot[key].State)
+	require.Equal(t, 0, snapshot[key].ConsecutiveFailures)
+	require.Equal(t, "closed", snapshot[key].State.String())
+}
+
+func TestCircuitBreakerTimeouts(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	breaker := common.NewCircuitBreaker(common.WithFailureThreshold(2))
+	client := &common.HTTPClient{
+		Base:           server.URL,
+		Client:         &http.Client{Timeout: 10 * time.Millisecond},
+		CircuitBreaker: breaker,
+	}
+
+	// Canceled requests say nothing about provider health.
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := client.Get(canceled, "/accounts")
+	require.Error(t, err)
+	require.Equal(t, 0, breaker.Snapshot().ConsecutiveFailures)
+
+	for i := 0; i < 2; i++ {
+		_, _, err = client.Get(context.Background(), "/accounts")
+		require.Error(t, err)
+	}
+
+	require.Equal(t, common.CircuitOpen, breaker.State())
+
+	_, _, err = client.Get(context.Background(), "/accounts")
+	require.ErrorIs(t, err, common.ErrCircuitOpen)
+
+	breaker.Reset()
+	require.Equal(t, common.CircuitClosed, breaker.State())
+}
+
+func TestCircuitBreakerSlowRequest(t *testing.T) { // nolint:funlen
+	t.Parallel()
+
+	arrived := map[string]chan struct{}{
+		"/slow":  make(chan struct{}),
+		"/probe": make(chan struct{}),
+	}
+	release := map[string]chan struct{}{
+		"/slow":  make(chan struct{}),
+		"/probe": make(chan struct{}),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		if wait, ok := release[r.URL.Path]; ok {
+			close(arrived[r.URL.Path])
+			<-wait
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var (
+		mut sync.Mutex
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	breaker := common.NewCircuitBreaker(
+		common.WithFailureThreshold(1),
+		common.WithCooldown(time.Minute),
+		common.WithCircuitClock(func() time.Time {
+			mut.Lock()
+			defer mut.Unlock()
+
+			return now
+		}),
+	)
+	client := &common.HTTPClient{
+		Base:           server.URL,
+		Client:         http.DefaultClient,
+		CircuitBreaker: breaker,
+	}
+	ctx := context.Background()
+
+	get := func(path string) chan error {
+		errs := make(chan error, 1)
+
+		go func() {
+			_, _, err := client.Get(ctx, path)
+			errs <- err
+		}()
+
+		return errs
+	}
+
+	// Request sent before the circuit opens is still in flight when the probe starts.
+	slow := get("/slow")
+	<-arrived["/slow"]
+
+	_, _, err := client.Get(ctx, "/fail")
+	require.ErrorIs(t, err, common.ErrServer)
+	require.Equal(t, common.CircuitOpen, breaker.State())
+
+	mut.Lock()
+	now = now.Add(time.Minute)
+	mut.Unlock()
+
+	probe := get("/probe")
+	<-arrived["/probe"]
+
+	// Slow request finishing is not mistaken for the probe.
+	close(release["/slow"])
+	require.NoError(t, <-slow)
+	require.Equal(t, common.CircuitHalfOpen, breaker.State())
+
+	_, _, err = client.Get(ctx, "/accounts")
+	require.ErrorIs(t, err, common.ErrCircuitOpen)
+
+	close(release["/probe"])
+	require.NoError(t, <-probe)
+	require.Equal(t, common.CircuitClosed, breaker.State())
+}
@@ -0,0 +1,157 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVRecordReader(t *testing.T) { // nolint:funlen
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		format  common.CSVFormat
+		records []map[string]string
+		err     error
+	}{
+		{
+			name:   "Comma separated with LF",
+			input:  "Id,Name\n1,Acme\n2,\"Smith, Jones\"\n",
+			format: common.CSVFormat{},
+			records: []map[string]string{
+				{"Id": "1", "Name": "Acme"},
+				{"Id": "2", "Name": "Smith, Jones"},
+			},
+		},
+		{
+			name:    "Pipe separated with CRLF",
+			input:   "Id|Name\r\n1|Acme\r\n",
+			format:  common.CSVFormat{Delimiter: '|', UseCRLF: true},
+			records: []map[string]string{{"Id": "1", "Name": "Acme"}},
+		},
+		{
+			name:    "Header only",
+			input:   "Id,Name\n",
+			records: []map[string]string{},
+		},
+		{
+			name:  "Duplicate column",
+			input: "Id,Id\n1,2\n",
+			err:   common.ErrCSVHeader,
+		},
+		{
+			name:  "Wrong number of fields",
+			input: "Id,Name\n1\n",
+			err:   common.ErrNotCSV,
+		},
+	}
+
+	for _, tt := range tests { // nolint:varnamelen
+		tt := tt // rebind, omit loop side effects for parallel goroutine
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reader := common.NewCSVRecordReader(strings.NewReader(tt.input), tt.format)
+			records := make([]map[string]string, 0)
+
+			var err error
+
+			for {
+				var record map[string]string
+
+				record, err = reader.Next()
+				if err != nil {
+					break
+				}
+
+				records = append(records, record)
+			}
+
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+
+				return
+			}
+
+			require.ErrorIs(t, err, io.EOF)
+			require.Equal(t, tt.records, records)
+		})
+	}
+}
+
+func TestCSVRecordReaderBodyError(t *testing.T) {
+	t.Parallel()
+
+	errConnection := errors.New("connection reset") // nolint:goerr113
+	body := io.MultiReader(strings.NewReader("Id,Name\n1,Acme\n"), iotest.ErrReader(errConnection))
+	reader := common.NewCSVRecordReader(body, common.CSVFormat{})
+
+	_, err := reader.Next()
+	require.NoError(t, err)
+
+	// Failure to read is not a malformed CSV.
+	_, err = reader.Next()
+	require.ErrorIs(t, err, errConnection)
+	require.NotErrorIs(t, err, common.ErrNotCSV)
+}
+
+func TestCSVHTTPClientGet(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv; charset=UTF-8")
+		_, _ = w.Write([]byte("Id;Name\n1;Acme\n"))
+	}))
+	defer server.Close()
+
+	client := &common.CSVHTTPClient{
+		HTTPClient: &common.HTTPClient{
+			Base:   server.URL,
+			Client: http.DefaultClient,
+		},
+	}
+
+	_, err := client.Get(context.Background(), "/broken", common.CSVFormat{})
+	require.ErrorIs(t, err, common.ErrCaller)
+
+	reader, err := client.Get(context.Background(), "/accounts", common.CSVFormat{Delimiter: ';'})
+	require.NoError(t, err)
+
+	defer reader.Close()
+
+	record, err := reader.Next()
+	require.NoError(t, err)
+
+	row := common.CSVRecordToReadResultRow(record, []string{"name"}, common.ExtractLowercaseFieldsFromRaw)
+	require.Equal(t, map[string]any{"name": "Acme"}, row.Fields)
+	require.Equal(t, map[string]any{"Id": "1", "Name": "Acme"}, row.Raw)
+
+	_, err = reader.Next()
+	require.True(t, errors.Is(err, io.EOF))
+}
+
+func TestMarshalCSV(t *testing.T) {
+	t.Parallel()
+
+	data, err := common.MarshalCSV([]string{"Id", "Name"}, [][]string{{"1", "Acme"}},
+		common.CSVFormat{Delimiter: '\t', UseCRLF: true})
+	require.NoError(t, err)
+	require.Equal(t, "Id\tName\r\n1\tAcme\r\n", string(data))
+}
@@ -0,0 +1,146 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugLogger(t *testing.T) { // nolint:funlen
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		_, _ = w.Write([]byte(`{"contacts": [{"name": "Alice", "email": "alice@example.com"}], "notes": "` +
+			strings.Repeat("x", 100) + `"}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	authClient, err := common.NewHeaderAuthHTTPClient(context.Background(),
+		common.WithHeaders(
+			common.Header{Key: "Authorization", Value: "Bearer secret"},
+			common.Header{Key: "X-Custom-Key", Value: "secret"},
+		),
+		common.WithHeaderDebug(common.NewDebugLogger(
+			common.WithDebugSlogLogger(logger),
+			common.WithRedactedHeaders("x-custom-key"),
+			common.WithRedactedBodyPaths("password", "contacts.email", "token"),
+			common.WithMaxBodySize(120),
+		)),
+	)
+	require.NoError(t, err)
+
+	client := &common.JSONHTTPClient{
+		HTTPClient: &common.HTTPClient{
+			Base:   server.URL,
+			Client: authClient,
+		},
+	}
+
+	rsp, err := client.Post(context.Background(), server.URL+"/contacts?token=secret&limit=1", map[string]any{
+		"name":     "Bob",
+		"password": "secret",
+	})
+	require.NoError(t, err)
+
+	// Caller still receives the complete body.
+	body, err := common.UnmarshalJSON[map[string]any](rsp)
+	require.NoError(t, err)
+	require.Equal(t, "alice@example.com", (*body)["contacts"].([]any)[0].(map[string]any)["email"])
+
+	require.NotContains(t, logs.String(), "secret")
+	require.NotContains(t, logs.String(), "alice@example.com")
+
+	var record struct {
+		Msg     string `json:"msg"`
+		Request struct {
+			Method  string            `json:"method"`
+			URL     string            `json:"url"`
+			Headers map[string]string `json:"headers"`
+			Body    string            `json:"body"`
+		} `json:"request"`
+		Response struct {
+			Status  int               `json:"status"`
+			Headers map[string]string `json:"headers"`
+			Body    string            `json:"body"`
+		} `json:"response"`
+	}
+
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+	require.Equal(t, http.MethodPost, record.Request.Method)
+	require.Equal(t, server.URL+"/contacts?limit=1&token=REDACTED", record.Request.URL)
+	require.Equal(t, common.RedactedHeaderValue, record.Request.Headers["Authorization"])
+	require.Equal(t, common.RedactedHeaderValue, record.Request.Headers["X-Custom-Key"])
+	require.JSONEq(t, `{"name": "Bob", "password": "REDACTED"}`, record.Request.Body)
+	require.Equal(t, http.StatusOK, record.Response.Status)
+	require.Equal(t, common.RedactedHeaderValue, record.Response.Headers["Set-Cookie"])
+	require.True(t, strings.HasPrefix(record.Response.Body,
+		`{"contacts":[{"email":"REDACTED","name":"Alice"}],"notes":"xxx`))
+	require.True(t, strings.HasSuffix(record.Response.Body, "bytes truncated)"))
+}
+
+func TestDebugLoggerDefaultsAndLargeBodies(t *testing.T) {
+	t.Parallel()
+
+	large := `{"items": ["` + strings.Repeat("x", 2*common.DebugBodyLimit) + `"], "access_token": "hidden-value"}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/large" {
+			_, _ = w.Write([]byte(large))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"access_token": "hidden-value", "refresh_token": "hidden-value",
+			"id_token": "hidden-value"}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	authClient, err := common.NewHeaderAuthHTTPClient(context.Background(),
+		common.WithHeaderDebug(common.NewDebugLogger(common.WithDebugSlogLogger(logger))),
+	)
+	require.NoError(t, err)
+
+	// OAuth credentials are hidden without configuration.
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL+"/token",
+		strings.NewReader("grant_type=refresh_token&client_secret=hidden-value"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	rsp, err := authClient.Do(req)
+	require.NoError(t, err)
+	_ = rsp.Body.Close()
+
+	// Large body reaches the caller intact, while the log doesn't show JSON it couldn't redact.
+	req, _ = http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/large", nil)
+
+	rsp, err = authClient.Do(req)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(rsp.Body)
+	require.NoError(t, err)
+	require.NoError(t, rsp.Body.Close())
+	require.Equal(t, large, string(data))
+
+	require.NotContains(t, logs.String(), "hidden-value")
+	require.Contains(t, logs.String(), "is not shown")
+}
@@ -0,0 +1,94 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/stretchr/testify/require"
+)
+
+type decodedContact struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"firstName"`
+	Employees int       `json:"numberOfEmployees"`
+	Revenue   *float64  `json:"annualRevenue"`
+	Active    bool      `json:"isActive"`
+	CreatedAt time.Time `json:"createdDate"`
+	Tags      []string  `json:"tags"`
+	Ignored   string    `json:"-"`
+}
+
+func TestDecodeRows(t *testing.T) { // nolint:funlen
+	t.Parallel()
+
+	result := &common.ReadResult{
+		Data: []common.ReadResultRow{
+			{
+				// Hubspot style, numbers and booleans are strings.
+				Fields: map[string]any{
+					"firstname":         "Alice",
+					"numberofemployees": "120",
+					"annualrevenue":     "1500.5",
+					"isactive":          "true",
+					"createddate":       "2024-05-01T10:00:00.000Z",
+				},
+				Raw: map[string]any{
+					"id":   "101",
+					"tags": []any{"vip", "eu"},
+				},
+			},
+			{
+				// Salesforce style, zone offset without colon.
+				Fields: map[string]any{
+					"firstname":         "Bob",
+					"numberofemployees": float64(7),
+					"isactive":          false,
+					"createddate":       "2024-05-01T10:00:00.000+0000",
+				},
+				Raw: map[string]any{"Id": float64(102)},
+			},
+			{
+				Fields: map[string]any{"numberofemployees": "many"},
+			},
+		},
+	}
+
+	contacts, err := common.DecodeRows[decodedContact](result)
+	require.ErrorIs(t, err, common.ErrDecodeRow)
+	require.ErrorIs(t, err, common.ErrDecodeValue)
+
+	var rowErr *common.RowDecodeError
+	require.ErrorAs(t, err, &rowErr)
+	require.Equal(t, 2, rowErr.Index)
+
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	revenue := 1500.5
+
+	require.Len(t, contacts, 3)
+	require.Equal(t, decodedContact{
+		ID:        "101",
+		Name:      "Alice",
+		Employees: 120,
+		Revenue:   &revenue,
+		Active:    true,
+		CreatedAt: createdAt,
+		Tags:      []string{"vip", "eu"},
+	}, contacts[0])
+
+	require.Equal(t, "102", contacts[1].ID)
+	require.Equal(t, 7, contacts[1].Employees)
+	require.Nil(t, contacts[1].Revenue)
+	require.True(t, contacts[1].CreatedAt.Equal(createdAt))
+	require.Equal(t, decodedContact{}, contacts[2])
+}
+
+func TestDecodeRowTarget(t *testing.T) {
+	t.Parallel()
+
+	_, err := common.DecodeRow[map[string]any](common.ReadResultRow{})
+	if !errors.Is(err, common.ErrDecodeTarget) {
+		t.Fatalf("expected Error: (%v), got: (%v)", common.ErrDecodeTarget, err)
+	}
+}
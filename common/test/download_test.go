@@ -0,0 +1,64 @@
+package test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownload(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files/report":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Header().Set("Content-Disposition", `attachment; filename="report.pdf"`)
+			_, _ = w.Write([]byte("%PDF-1.4 content"))
+		case "/files/resume":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("Content-Disposition", `attachment; filename*=UTF-8''r%C3%A9sum%C3%A9.txt`)
+			w.Header().Set("Transfer-Encoding", "chunked")
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte("text"))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": "invalid file id"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := &common.JSONHTTPClient{
+		HTTPClient: &common.HTTPClient{
+			Base:   server.URL,
+			Client: http.DefaultClient,
+		},
+	}
+
+	download, err := client.Download(context.Background(), "/files/report")
+	require.NoError(t, err)
+	require.Equal(t, "application/pdf", download.ContentType)
+	require.Equal(t, int64(len("%PDF-1.4 content")), download.Size)
+	require.Equal(t, "report.pdf", download.FileName)
+
+	data, err := io.ReadAll(download.Body)
+	require.NoError(t, err)
+	require.Equal(t, "%PDF-1.4 content", string(data))
+	require.NoError(t, download.Body.Close())
+
+	download, err = client.Download(context.Background(), "/files/resume")
+	require.NoError(t, err)
+	require.Equal(t, int64(common.UnknownSize), download.Size)
+	require.Equal(t, "résumé.txt", download.FileName)
+	require.NoError(t, download.Body.Close())
+
+	_, err = client.Download(context.Background(), "/files/missing")
+	require.ErrorIs(t, err, common.ErrCaller)
+}
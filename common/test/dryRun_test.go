@@ -0,0 +1,82 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+
+	var received atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer server.Close()
+
+	client := &common.JSONHTTPClient{
+		HTTPClient: &common.HTTPClient{
+			Base:   server.URL,
+			Client: http.DefaultClient,
+		},
+	}
+	ctx := common.WithDryRun(context.Background())
+
+	// Reads are sent.
+	_, err := client.Get(ctx, "/contacts/1")
+	require.NoError(t, err)
+	require.Equal(t, int32(1), received.Load())
+
+	// Writes are not.
+	_, err = client.Post(ctx, "/contacts", map[string]any{"name": "Alice"}, common.Header{
+		Key:   "Authorization",
+		Value: "Bearer secret",
+	})
+	require.ErrorIs(t, err, common.ErrDryRun)
+	require.Equal(t, int32(1), received.Load())
+
+	var dryRun *common.DryRunError
+	require.True(t, errors.As(err, &dryRun))
+	require.Equal(t, http.MethodPost, dryRun.Request.Method)
+	require.Equal(t, server.URL+"/contacts", dryRun.Request.URL)
+	require.Equal(t, common.RedactedHeaderValue, dryRun.Request.Header.Get("Authorization"))
+	require.Equal(t, "application/json", dryRun.Request.Header.Get("Content-Type"))
+	require.JSONEq(t, `{"name": "Alice"}`, string(dryRun.Request.Body))
+
+	// Without dry run request is sent.
+	_, err = client.Delete(common.WithoutDryRun(ctx), "/contacts/1")
+	require.NoError(t, err)
+	require.Equal(t, int32(2), received.Load())
+}
+
+func TestDryRunRedactedHeaders(t *testing.T) {
+	t.Parallel()
+
+	client := &common.JSONHTTPClient{
+		HTTPClient: &common.HTTPClient{
+			Base:            "https://example.com",
+			Client:          http.DefaultClient,
+			RedactedHeaders: []string{"X-Custom-Key"},
+		},
+	}
+
+	_, err := client.Post(common.WithDryRun(context.Background()), "/contacts", map[string]any{},
+		common.Header{Key: "x-custom-key", Value: "secret"},
+		common.Header{Key: "X-Trace-Id", Value: "trace"},
+	)
+
+	var dryRun *common.DryRunError
+	require.True(t, errors.As(err, &dryRun))
+	require.Equal(t, common.RedactedHeaderValue, dryRun.Request.Header.Get("X-Custom-Key"))
+	require.Equal(t, "trace", dryRun.Request.Header.Get("X-Trace-Id"))
+}
@@ -0,0 +1,204 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLQuery(t *testing.T) { // nolint:funlen
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request common.GraphQLRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch request.OperationName {
+		case "Limited":
+			_, _ = w.Write([]byte(`{"data": null, "errors": [{"type": "RATE_LIMITED", "message": "API rate limit exceeded"}]}`))
+		case "Partial":
+			_, _ = w.Write([]byte(`{"data": {"viewer": {"login": "octocat", "email": null}},
+				"errors": [{"message": "Forbidden", "path": ["viewer", "email"], "extensions": {"code": "FORBIDDEN"}}]}`))
+		default:
+			if request.Variables["after"] == "Y3Vyc29yOjI=" {
+				_, _ = w.Write([]byte(`{"data": {"repository": {"issues": {"totalCount": 3,
+					"edges": [{"node": {"id": "3", "title": "Third"}}],
+					"pageInfo": {"hasNextPage": false, "endCursor": "Y3Vyc29yOjM="}}}}}`))
+
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"data": {"repository": {"issues": {"totalCount": 3,
+				"edges": [{"node": {"id": "1", "title": "First"}}, {"node": {"id": "2", "title": "Second"}}],
+				"pageInfo": {"hasNextPage": true, "endCursor": "Y3Vyc29yOjI="}}}},
+				"extensions": {"cost": {"requestedQueryCost": 12, "actualQueryCost": 10,
+				"throttleStatus": {"maximumAvailable": 1000, "currentlyAvailable": 990, "restoreRate": 50}}}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := &common.GraphQLHTTPClient{
+		JSONClient: &common.JSONHTTPClient{
+			HTTPClient: &common.HTTPClient{
+				Base:   server.URL,
+				Client: http.DefaultClient,
+			},
+		},
+		URL: "/graphql",
+	}
+
+	issues := common.GraphQLConnection{Path: []string{"repository", "issues"}}
+	request := common.GraphQLRequest{
+		Query: `query($after: String) { repository(owner: "o", name: "r") { issues(first: 2, after: $after) {
+			totalCount edges { node { id title } } pageInfo { hasNextPage endCursor } } } }`,
+	}
+
+	rsp, err := client.Query(context.Background(), request.WithCursor("after", ""))
+	require.NoError(t, err)
+
+	result, err := common.ParseResult(rsp.JSONResponse(), issues.Size, issues.Records, issues.NextPage,
+		func(records []map[string]any, fields []string) ([]common.ReadResultRow, error) {
+			rows := make([]common.ReadResultRow, len(records))
+			for i, record := range records {
+				rows[i] = common.ReadResultRow{
+					Fields: common.ExtractLowercaseFieldsFromRaw(fields, record),
+					Raw:    record,
+				}
+			}
+
+			return rows, nil
+		}, []string{"title"})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), result.Rows)
+	require.Len(t, result.Data, 2)
+	require.Equal(t, map[string]any{"title": "Second"}, result.Data[1].Fields)
+	require.Equal(t, common.NextPageToken("Y3Vyc29yOjI="), result.NextPage)
+	require.False(t, result.Done)
+
+	cost, ok := rsp.Cost()
+	require.True(t, ok)
+	require.Equal(t, common.GraphQLCost{
+		Requested: 12, Actual: 10, Available: 990, Maximum: 1000, RestoreRate: 50,
+	}, *cost)
+
+	rsp, err = client.Query(context.Background(), request.WithCursor("after", result.NextPage))
+	require.NoError(t, err)
+
+	nextPage, err := issues.NextPage(rsp.Data)
+	require.NoError(t, err)
+	require.Empty(t, nextPage)
+
+	// Partial data comes with errors.
+	rsp, err = client.Query(context.Background(), common.GraphQLRequest{
+		Query: "query Partial { viewer { login email } }", OperationName: "Partial",
+	})
+	require.NoError(t, err)
+	require.Len(t, rsp.Errors, 1)
+	require.Equal(t, "FORBIDDEN", rsp.Errors[0].Code())
+	require.Equal(t, []any{"viewer", "email"}, rsp.Errors[0].Path)
+
+	// Errors without data fail the query.
+	_, err = client.Query(context.Background(), common.GraphQLRequest{
+		Query: "query Limited { viewer { login } }", OperationName: "Limited",
+	})
+	require.ErrorIs(t, err, common.ErrLimitExceeded)
+	require.ErrorIs(t, err, common.ErrGraphQL)
+
+	var graphErrs common.GraphQLErrors
+	require.True(t, errors.As(err, &graphErrs))
+	require.Equal(t, "RATE_LIMITED", graphErrs[0].Code())
+
+	_, err = client.Query(context.Background(), common.GraphQLRequest{})
+	require.ErrorIs(t, err, common.ErrMissingGraphQLQuery)
+}
+
+func TestGraphQLDryRun(t *testing.T) {
+	t.Parallel()
+
+	var received atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": {}}`))
+	}))
+	defer server.Close()
+
+	client := &common.GraphQLHTTPClient{
+		JSONClient: &common.JSONHTTPClient{
+			HTTPClient: &common.HTTPClient{
+				Base:   server.URL,
+				Client: http.DefaultClient,
+			},
+		},
+		URL: "/graphql",
+	}
+
+	fragment := `fragment Issue on Issue { id title }`
+
+	tests := []struct {
+		name    string
+		request common.GraphQLRequest
+		sent    bool
+	}{
+		{
+			name:    "Shorthand query",
+			request: common.GraphQLRequest{Query: `{ viewer { login } }`},
+			sent:    true,
+		},
+		{
+			name: "Query after comment and fragment",
+			request: common.GraphQLRequest{Query: "# mutation is not executed here\n" + fragment +
+				` query Issues { issues { ...Issue } }`},
+			sent: true,
+		},
+		{
+			name: "Mutation after comment",
+			request: common.GraphQLRequest{Query: "# create issue\n" +
+				`mutation { createIssue(input: {title: "query { x }"}) { issue { id } } }`},
+		},
+		{
+			name: "Selected operation is a mutation",
+			request: common.GraphQLRequest{
+				Query:         `query Issues { issues { id } } mutation Close { closeIssue(id: 1) { id } }`,
+				OperationName: "Close",
+			},
+		},
+		{
+			name: "Selected operation is a query",
+			request: common.GraphQLRequest{
+				Query:         `mutation Close { closeIssue(id: 1) { id } } query Issues @cached { issues { id } }`,
+				OperationName: "Issues",
+			},
+			sent: true,
+		},
+		{
+			name: "Operation cannot be determined",
+			request: common.GraphQLRequest{
+				Query: `query Issues { issues { id } } mutation Close { closeIssue(id: 1) { id } }`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		before := received.Load()
+
+		_, err := client.Query(common.WithDryRun(context.Background()), tt.request)
+		if tt.sent {
+			require.NoError(t, err, tt.name)
+			require.Equal(t, before+1, received.Load(), tt.name)
+		} else {
+			require.ErrorIs(t, err, common.ErrDryRun, tt.name)
+			require.Equal(t, before, received.Load(), tt.name)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/stretchr/testify/require"
+)
+
+var errTimeout = errors.New("timeout")
+
+func TestIdempotentWrite(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := common.NewInMemoryIdempotencyStore(0, 0)
+	calls := 0
+
+	write := func(ctx context.Context, params common.WriteParams) (*common.WriteResult, error) {
+		calls++
+		if calls == 1 {
+			// provider rejects the first attempt, nothing was created
+			return nil, common.NewHTTPStatusError(http.StatusBadRequest, common.ErrBadRequest)
+		}
+
+		return &common.WriteResult{Success: true, RecordId: strconv.Itoa(calls)}, nil
+	}
+
+	params := common.WriteParams{ObjectName: "contacts", IdempotencyKey: "key-1"}
+
+	_, err := common.IdempotentWrite(ctx, store, params, write)
+	require.ErrorIs(t, err, common.ErrBadRequest)
+
+	// Rejected create released the key, retries after success return the same record without calling the provider.
+	for i := 0; i < 2; i++ {
+		result, err := common.IdempotentWrite(ctx, store, params, write)
+		require.NoError(t, err)
+		require.Equal(t, "2", result.RecordId)
+	}
+
+	require.Equal(t, 2, calls)
+
+	// Key is scoped by object.
+	result, err := common.IdempotentWrite(ctx, store, common.WriteParams{
+		ObjectName:     "deals",
+		IdempotencyKey: "key-1",
+	}, write)
+	require.NoError(t, err)
+	require.Equal(t, "3", result.RecordId)
+
+	// Updates are never deduplicated.
+	_, err = common.IdempotentWrite(ctx, store, common.WriteParams{
+		ObjectName:     "contacts",
+		RecordId:       "2",
+		IdempotencyKey: "key-1",
+	}, write)
+	require.NoError(t, err)
+	require.Equal(t, 4, calls)
+
+	// Key cannot be honoured without a store.
+	_, err = common.IdempotentWrite(ctx, nil, params, write)
+	require.ErrorIs(t, err, common.ErrIdempotencyStoreRequired)
+	require.Equal(t, 4, calls)
+}
+
+func TestIdempotentWriteUnknownOutcome(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := common.NewInMemoryIdempotencyStore(0, 50*time.Millisecond)
+	calls := 0
+
+	write := func(ctx context.Context, params common.WriteParams) (*common.WriteResult, error) {
+		calls++
+		if calls == 1 {
+			// provider has created the record, but the response never arrived
+			return nil, errTimeout
+		}
+
+		return &common.WriteResult{Success: true, RecordId: strconv.Itoa(calls)}, nil
+	}
+
+	params := common.WriteParams{ObjectName: "contacts", IdempotencyKey: "key-1"}
+
+	_, err := common.IdempotentWrite(ctx, store, params, write)
+	require.ErrorIs(t, err, errTimeout)
+
+	// Retry doesn't reach the provider while the outcome is unknown.
+	_, err = common.IdempotentWrite(ctx, store, params, write)
+	require.ErrorIs(t, err, common.ErrIdempotencyKeyInUse)
+	require.Equal(t, 1, calls)
+
+	// Reservation lapses, the guarantee is best-effort.
+	time.Sleep(100 * time.Millisecond)
+
+	result, err := common.IdempotentWrite(ctx, store, params, write)
+	require.NoError(t, err)
+	require.Equal(t, "2", result.RecordId)
+}
+
+func TestIdempotentWriteConcurrent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := common.NewInMemoryIdempotencyStore(0, 0)
+	release := make(chan struct{})
+
+	var calls atomic.Int32
+
+	write := func(ctx context.Context, params common.WriteParams) (*common.WriteResult, error) {
+		calls.Add(1)
+		<-release
+
+		return &common.WriteResult{Success: true, RecordId: "1"}, nil
+	}
+
+	params := common.WriteParams{ObjectName: "contacts", IdempotencyKey: "key-1"}
+
+	const attempts = 10
+
+	var wg sync.WaitGroup
+
+	errs := make([]error, attempts)
+
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			_, errs[i] = common.IdempotentWrite(ctx, store, params, write)
+		}(i)
+	}
+
+	require.Eventually(t, func() bool {
+		return calls.Load() == 1
+	}, time.Second, time.Millisecond)
+
+	close(release)
+	wg.Wait()
+
+	// Attempts made while the create was in flight are refused, later ones get the created record.
+	for _, err := range errs {
+		if err != nil {
+			require.ErrorIs(t, err, common.ErrIdempotencyKeyInUse)
+		}
+	}
+
+	require.Equal(t, int32(1), calls.Load())
+
+	result, err := common.IdempotentWrite(ctx, store, params, write)
+	require.NoError(t, err)
+	require.Equal(t, "1", result.RecordId)
+}
+
+func TestIdempotentWriteDryRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := common.NewInMemoryIdempotencyStore(0, 0)
+	calls := 0
+
+	write := func(ctx context.Context, params common.WriteParams) (*common.WriteResult, error) {
+		if common.IsDryRun(ctx) {
+			return nil, &common.DryRunError{Request: &common.PreparedRequest{Method: http.MethodPost}}
+		}
+
+		calls++
+
+		return &common.WriteResult{Success: true, RecordId: strconv.Itoa(calls)}, nil
+	}
+
+	params := common.WriteParams{ObjectName: "contacts", IdempotencyKey: "key-1"}
+
+	// Dry run neither reserves the key nor needs a store.
+	_, err := common.IdempotentWrite(common.WithDryRun(ctx), store, params, write)
+	require.ErrorIs(t, err, common.ErrDryRun)
+
+	_, err = common.IdempotentWrite(common.WithDryRun(ctx), nil, params, write)
+	require.ErrorIs(t, err, common.ErrDryRun)
+
+	result, err := common.IdempotentWrite(ctx, store, params, write)
+	require.NoError(t, err)
+	require.Equal(t, "1", result.RecordId)
+}
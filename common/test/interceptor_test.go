@@ -0,0 +1,114 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterceptors(t *testing.T) { // nolint:funlen
+	t.Parallel()
+
+	var received atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Tenant", r.Header.Get("X-Tenant"))
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	calls := make([]string, 0)
+	trace := func(name string) common.Interceptor {
+		return func(req *http.Request, next common.RoundTripFunc) (*http.Response, error) {
+			calls = append(calls, name+" request")
+			rsp, err := next(req)
+			calls = append(calls, name+" response")
+
+			return rsp, err
+		}
+	}
+
+	// Rewrites request body and the response which comes back.
+	rewrite := func(req *http.Request, next common.RoundTripFunc) (*http.Response, error) {
+		if req.Body != nil {
+			data, err := io.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+
+			data = bytes.ReplaceAll(data, []byte("Alice"), []byte("Bob"))
+			req.Body = io.NopCloser(bytes.NewReader(data))
+			req.ContentLength = int64(len(data))
+		}
+
+		rsp, err := next(req)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := io.ReadAll(rsp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		_ = rsp.Body.Close()
+		rsp.Body = io.NopCloser(strings.NewReader(strings.ToUpper(string(data))))
+
+		return rsp, nil
+	}
+
+	client := &common.JSONHTTPClient{
+		HTTPClient: &common.HTTPClient{
+			Base:   server.URL,
+			Client: http.DefaultClient,
+			Interceptors: []common.Interceptor{
+				common.ChainInterceptors(trace("outer"), trace("inner")),
+				common.HeaderInterceptor(common.Header{Key: "X-Tenant", Value: "acme"}),
+				rewrite,
+			},
+		},
+	}
+
+	rsp, err := client.Post(context.Background(), "/contacts", map[string]any{"name": "Alice"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"outer request", "inner request", "inner response", "outer response"}, calls)
+	require.Equal(t, "acme", rsp.Headers.Get("X-Tenant"))
+
+	body, err := common.UnmarshalJSON[map[string]any](rsp)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"NAME": "BOB"}, *body)
+
+	// Interceptor can answer without calling the provider.
+	cached := &common.HTTPClient{
+		Base:   server.URL,
+		Client: http.DefaultClient,
+		Interceptors: []common.Interceptor{
+			func(req *http.Request, next common.RoundTripFunc) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(`{"cached": true}`)),
+					Request:    req,
+				}, nil
+			},
+		},
+	}
+
+	_, data, err := cached.Get(context.Background(), "/contacts/1")
+	require.NoError(t, err)
+	require.JSONEq(t, `{"cached": true}`, string(data))
+	require.Equal(t, int32(1), received.Load())
+}
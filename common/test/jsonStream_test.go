@@ -0,0 +1,225 @@
+package test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/common/jsonquery"
+	"github.com/spyzhov/ajson"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONRecordDecoder(t *testing.T) { // nolint:funlen
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		path     []string
+		records  []map[string]any
+		envelope string
+		err      error
+	}{
+		{
+			name:     "Records between other values",
+			input:    `{"totalSize": 2, "records": [{"Id": "1"}, {"Id": "2", "Tags": ["a"]}], "nextRecordsUrl": "/next"}`,
+			path:     []string{"records"},
+			records:  []map[string]any{{"Id": "1"}, {"Id": "2", "Tags": []any{"a"}}},
+			envelope: `{"totalSize": 2, "records": [], "nextRecordsUrl": "/next"}`,
+		},
+		{
+			name:     "Nested records",
+			input:    `{"meta": {"count": 1}, "data": {"page": 3, "items": [{"id": 1}], "more": false}, "ok": true}`,
+			path:     []string{"data", "items"},
+			records:  []map[string]any{{"id": float64(1)}},
+			envelope: `{"meta": {"count": 1}, "data": {"page": 3, "items": [], "more": false}, "ok": true}`,
+		},
+		{
+			name:     "Document is an array",
+			input:    `[{"id": 1}, {"id": 2}]`,
+			records:  []map[string]any{{"id": float64(1)}, {"id": float64(2)}},
+			envelope: `[]`,
+		},
+		{
+			name:     "Missing records",
+			input:    `{"data": {"page": 1}, "done": true}`,
+			path:     []string{"data", "items"},
+			records:  []map[string]any{},
+			envelope: `{"data": {"page": 1}, "done": true}`,
+		},
+		{
+			name:     "Null records",
+			input:    `{"records": null, "done": true}`,
+			path:     []string{"records"},
+			records:  []map[string]any{},
+			envelope: `{"records": [], "done": true}`,
+		},
+		{
+			name:  "Record is not an object",
+			input: `{"records": [{"id": 1}, 2]}`,
+			path:  []string{"records"},
+			err:   common.ErrRecordNotObject,
+		},
+		{
+			name:  "Records are not an array",
+			input: `{"records": {"id": 1}}`,
+			path:  []string{"records"},
+			err:   common.ErrNotJSON,
+		},
+		{
+			name:  "Truncated document",
+			input: `{"records": [{"id": 1}`,
+			path:  []string{"records"},
+			err:   common.ErrNotJSON,
+		},
+		{
+			name:  "Empty document",
+			input: ``,
+			path:  []string{"records"},
+			err:   common.ErrEmptyJSONHTTPResponse,
+		},
+	}
+
+	for _, tt := range tests { // nolint:varnamelen
+		tt := tt // rebind, omit loop side effects for parallel goroutine
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			decoder := common.NewJSONRecordDecoder(strings.NewReader(tt.input), tt.path...)
+			records := make([]map[string]any, 0)
+
+			var err error
+
+			for {
+				var record map[string]any
+
+				record, err = decoder.Next()
+				if err != nil {
+					break
+				}
+
+				records = append(records, record)
+			}
+
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+
+				return
+			}
+
+			require.ErrorIs(t, err, io.EOF)
+			require.Equal(t, tt.records, records)
+
+			envelope, err := decoder.Envelope()
+			require.NoError(t, err)
+			require.JSONEq(t, tt.envelope, envelope.String())
+		})
+	}
+}
+
+func TestJSONRecordDecoderEnvelopeBeforeRecords(t *testing.T) {
+	t.Parallel()
+
+	decoder := common.NewJSONRecordDecoder(strings.NewReader(`{"records": [{"id": 1}]}`), "records")
+
+	_, err := decoder.Next()
+	require.NoError(t, err)
+
+	_, err = decoder.Envelope()
+	require.ErrorIs(t, err, common.ErrRecordsNotConsumed)
+}
+
+func TestGetStream(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"message": "unavailable"}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"total": 2, "items": [{"Id": "1", "Name": "A"}, {"Id": "2", "Name": "B"}], "next": ""}`))
+	}))
+	defer server.Close()
+
+	client := &common.JSONHTTPClient{
+		HTTPClient: &common.HTTPClient{
+			Base:   server.URL,
+			Client: http.DefaultClient,
+		},
+	}
+
+	_, err := client.GetStream(context.Background(), "/broken")
+	require.ErrorIs(t, err, common.ErrServer)
+
+	body, err := client.GetStream(context.Background(), "/accounts")
+	require.NoError(t, err)
+
+	decoder := common.NewJSONRecordDecoder(body, "items")
+
+	result, err := common.ParseStreamResult(decoder,
+		func(node *ajson.Node) (int64, error) {
+			return jsonquery.New(node).IntegerWithDefault("total", 0)
+		},
+		func(node *ajson.Node) (string, error) {
+			return jsonquery.New(node).StrWithDefault("next", "")
+		},
+		func(records []map[string]any, fields []string) ([]common.ReadResultRow, error) {
+			rows := make([]common.ReadResultRow, len(records))
+			for i, record := range records {
+				rows[i] = common.ReadResultRow{
+					Fields: common.ExtractLowercaseFieldsFromRaw(fields, record),
+					Raw:    record,
+				}
+			}
+
+			return rows, nil
+		},
+		[]string{"name"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), result.Rows)
+	require.True(t, result.Done)
+	require.Equal(t, map[string]any{"name": "B"}, result.Data[1].Fields)
+	require.NoError(t, decoder.Close())
+}
+
+func TestStreamRows(t *testing.T) {
+	t.Parallel()
+
+	decoder := common.NewJSONRecordDecoder(strings.NewReader(
+		`{"items": [{"Id": "1"}, {"Id": "2"}, {"Id": "3"}], "next": "page-2"}`), "items")
+
+	// Rows are handed over one by one, as they are decoded.
+	seen := make([]string, 0)
+
+	envelope, err := common.StreamRows(decoder,
+		func(records []map[string]any, fields []string) ([]common.ReadResultRow, error) {
+			require.Len(t, records, 1)
+
+			return []common.ReadResultRow{{Raw: records[0]}}, nil
+		},
+		nil,
+		func(row common.ReadResultRow) error {
+			seen = append(seen, row.Raw["Id"].(string))
+
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1", "2", "3"}, seen)
+
+	next, err := jsonquery.New(envelope).StrWithDefault("next", "")
+	require.NoError(t, err)
+	require.Equal(t, "page-2", next)
+}
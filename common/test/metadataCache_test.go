@@ -0,0 +1,92 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataCacheExpandFields(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	cache := common.NewMetadataCache(
+		func(ctx context.Context, objectNames []string) (*common.ListObjectMetadataResult, error) {
+			calls++
+
+			return &common.ListObjectMetadataResult{
+				Result: map[string]common.ObjectMetadata{
+					"contacts": {FieldsMap: map[string]string{"name": "Name", "email": "Email", "id": "ID"}},
+				},
+				Errors: map[string]error{
+					"deals": common.ErrForbidden,
+				},
+			}, nil
+		},
+	)
+
+	// Fields without wildcard don't need metadata.
+	fields, err := cache.ExpandFields(context.Background(), "Contacts", []string{"name"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"name"}, fields)
+	require.Equal(t, 0, calls)
+
+	fields, err = cache.ExpandFields(context.Background(), "Contacts", []string{"*", "Name", "owner.email"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"email", "id", "name", "owner.email"}, fields)
+
+	_, err = cache.ExpandFields(context.Background(), "contacts", []string{"*"})
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+
+	cache.Invalidate("contacts")
+
+	_, err = cache.GetObjectMetadata(context.Background(), "contacts")
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+
+	_, err = cache.GetObjectMetadata(context.Background(), "deals")
+	if !errors.Is(err, common.ErrMetadataLoadFailure) || !errors.Is(err, common.ErrForbidden) {
+		t.Fatalf("expected Error: (%v), got: (%v)", common.ErrMetadataLoadFailure, err)
+	}
+}
+
+func TestMetadataCacheReadFields(t *testing.T) {
+	t.Parallel()
+
+	cache := common.NewMetadataCache(
+		func(ctx context.Context, objectNames []string) (*common.ListObjectMetadataResult, error) {
+			return &common.ListObjectMetadataResult{
+				Result: map[string]common.ObjectMetadata{
+					"account": {
+						FieldsMap: map[string]string{"billingcity": "Billing City", "Id": "Account ID"},
+						Fields:    map[string]common.FieldMetadata{"billingcity": {Name: "BillingCity"}},
+					},
+				},
+			}, nil
+		},
+	)
+
+	// Lowercase mode keeps requested names.
+	fields, err := cache.ReadFields(context.Background(), common.ReadParams{
+		ObjectName: "Account",
+		Fields:     []string{"billingcity", "id"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"billingcity", "id"}, fields)
+
+	// Canonical names come from metadata, unknown fields and paths are kept.
+	fields, err = cache.ReadFields(context.Background(), common.ReadParams{
+		ObjectName:        "Account",
+		Fields:            []string{"BILLINGCITY", "id", "Owner.Email"},
+		PreserveFieldCase: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"BillingCity", "Id", "Owner.Email"}, fields)
+
+	// Metadata failure leaves the names to the extractor.
+	require.Equal(t, []string{"name"}, cache.CanonicalFields(context.Background(), "Lead", []string{"name"}))
+}
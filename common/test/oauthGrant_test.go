@@ -0,0 +1,205 @@
+package test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/oauth2/jwt"
+)
+
+// newGrantServer issues "access-N" tokens for the grant type, which are checked by the check function.
+// API endpoint accepts only the latest access token.
+func newGrantServer(t *testing.T, grantType string, check func(r *http.Request) bool) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var issued atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.FormValue("grant_type") != grantType || !check(r) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error": "invalid_grant"}`))
+
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"access_token": "access-%d", "token_type": "Bearer", "expires_in": 3600}`,
+				issued.Add(1))
+
+			return
+		}
+
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer access-%d", issued.Load()) {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server, &issued
+}
+
+func doGet(t *testing.T, client common.AuthenticatedHTTPClient, url string) int {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	rsp, err := client.Do(req)
+	require.NoError(t, err)
+
+	_ = rsp.Body.Close()
+
+	return rsp.StatusCode
+}
+
+func TestClientCredentialsGrant(t *testing.T) {
+	t.Parallel()
+
+	server, issued := newGrantServer(t, "client_credentials", func(r *http.Request) bool {
+		id, secret, ok := r.BasicAuth()
+
+		return ok && id == "client" && secret == "secret"
+	})
+
+	client, err := common.NewOAuthHTTPClient(context.Background(),
+		common.WithClientCredentials(&clientcredentials.Config{
+			ClientID:     "client",
+			ClientSecret: "secret",
+			TokenURL:     server.URL + "/token",
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, doGet(t, client, server.URL+"/api"))
+	assert.Equal(t, http.StatusOK, doGet(t, client, server.URL+"/api"))
+	assert.Equal(t, int32(1), issued.Load())
+
+	// Token is revoked, a new one is requested and the call is retried.
+	issued.Add(1)
+	assert.Equal(t, http.StatusOK, doGet(t, client, server.URL+"/api"))
+	assert.Equal(t, int32(3), issued.Load())
+}
+
+func TestJWTBearerGrant(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	server, issued := newGrantServer(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", func(r *http.Request) bool {
+		parts := strings.Split(r.FormValue("assertion"), ".")
+		if len(parts) != 3 { // nolint:gomnd
+			return false
+		}
+
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			return false
+		}
+
+		var claims map[string]any
+		if err = json.Unmarshal(payload, &claims); err != nil {
+			return false
+		}
+
+		return claims["iss"] == "consumer-key" && claims["sub"] == "user@example.com" &&
+			claims["aud"] == "https://login.example.com"
+	})
+
+	client, err := common.NewOAuthHTTPClient(context.Background(),
+		common.WithJWTBearer(&jwt.Config{
+			Email:      "consumer-key",
+			Subject:    "user@example.com",
+			Audience:   "https://login.example.com",
+			PrivateKey: privatePEM,
+			TokenURL:   server.URL + "/token",
+		}),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, doGet(t, client, server.URL+"/api"))
+	assert.Equal(t, int32(1), issued.Load())
+
+	issued.Add(1)
+	assert.Equal(t, http.StatusOK, doGet(t, client, server.URL+"/api"))
+	assert.Equal(t, int32(3), issued.Load())
+}
+
+func TestJWTBearerInvalidKey(t *testing.T) {
+	t.Parallel()
+
+	for name, privateKey := range map[string][]byte{
+		"missing": nil,
+		"not PEM": []byte("secret"),
+		"not key": pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("garbage")}),
+	} {
+		_, err := common.NewOAuthHTTPClient(context.Background(),
+			common.WithJWTBearer(&jwt.Config{PrivateKey: privateKey, TokenURL: "https://example.com/token"}),
+		)
+		require.ErrorIs(t, err, common.ErrInvalidPrivateKey, name)
+	}
+}
+
+func TestGrantTokenLifetime(t *testing.T) {
+	t.Parallel()
+
+	var issued atomic.Int32
+
+	// Token response has no expires_in, as Salesforce JWT bearer grant.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"access_token": "access-%d", "token_type": "Bearer"}`, issued.Add(1))
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := common.NewOAuthHTTPClient(context.Background(),
+		common.WithClientCredentials(&clientcredentials.Config{
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+			TokenURL:     server.URL + "/token",
+		}),
+		common.WithGrantTokenLifetime(50*time.Millisecond),
+		common.WithTokenExpirySkew(0),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, doGet(t, client, server.URL+"/api"))
+	assert.Equal(t, http.StatusOK, doGet(t, client, server.URL+"/api"))
+	assert.Equal(t, int32(1), issued.Load())
+
+	// Token is renewed once its assumed lifetime is over, even though the provider still accepts it.
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Equal(t, http.StatusOK, doGet(t, client, server.URL+"/api"))
+	assert.Equal(t, int32(2), issued.Load())
+}
@@ -0,0 +1,111 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractLowercaseFieldsFromRaw(t *testing.T) { // nolint:funlen
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		fields   []string
+		record   map[string]any
+		expected map[string]any
+	}{
+		{
+			name:     "Top level fields are matched ignoring case",
+			fields:   []string{"Id", "NAME", "missing"},
+			record:   map[string]any{"id": "1", "Name": "Acme"},
+			expected: map[string]any{"id": "1", "name": "Acme"},
+		},
+		{
+			name:   "JSON:API attributes",
+			fields: []string{"attributes.firstName", "relationships.account.data.id"},
+			record: map[string]any{
+				"attributes": map[string]any{"firstName": "Alice"},
+				"relationships": map[string]any{
+					"account": map[string]any{"data": map[string]any{"id": float64(7)}},
+				},
+			},
+			expected: map[string]any{
+				"attributes.firstname":          "Alice",
+				"relationships.account.data.id": float64(7),
+			},
+		},
+		{
+			name:   "Salesforce relationship field",
+			fields: []string{"Account.Name", "Owner.Manager.Email"},
+			record: map[string]any{
+				"Account": map[string]any{"Name": "Acme"},
+				"Owner":   map[string]any{"Manager": nil},
+			},
+			expected: map[string]any{"account.name": "Acme"},
+		},
+		{
+			name:   "Array index and quoted key",
+			fields: []string{"emails[1].address", "custom['first.name']", "emails[5].address"},
+			record: map[string]any{
+				"emails": []any{
+					map[string]any{"address": "a@example.com"},
+					map[string]any{"address": "b@example.com"},
+				},
+				"custom": map[string]any{"first.name": "Bob"},
+			},
+			expected: map[string]any{
+				"emails[1].address":    "b@example.com",
+				"custom['first.name']": "Bob",
+			},
+		},
+		{
+			name:     "Literal top level key takes precedence over path",
+			fields:   []string{"hs.id"},
+			record:   map[string]any{"hs.id": "literal", "hs": map[string]any{"id": "nested"}},
+			expected: map[string]any{"hs.id": "literal"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt // rebind, omit loop side effects for parallel goroutine
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			output := common.ExtractLowercaseFieldsFromRaw(tt.fields, tt.record)
+			require.Equal(t, tt.expected, output)
+		})
+	}
+}
+
+func TestExtractFieldsFromRaw(t *testing.T) {
+	t.Parallel()
+
+	record := map[string]any{
+		"Id":          "001",
+		"BillingCity": "Paris",
+		"Name":        "Acme",
+		"name":        "acme-slug",
+		"Account":     map[string]any{"Owner": map[string]any{"Email": "a@example.com"}},
+	}
+
+	output := common.ExtractFieldsFromRaw([]string{"id", "billingcity", "Name", "name", "account.owner.email"}, record)
+	require.Equal(t, map[string]any{
+		"Id":                  "001",
+		"BillingCity":         "Paris",
+		"Name":                "Acme",
+		"name":                "acme-slug",
+		"Account.Owner.Email": "a@example.com",
+	}, output)
+
+	// Keys differing only by case resolve to the same one on every call.
+	ambiguous := map[string]any{"NAME": "upper", "Name": "title", "name": "lower"}
+	for i := 0; i < 20; i++ {
+		require.Equal(t, map[string]any{"NAME": "upper"}, common.ExtractFieldsFromRaw([]string{"nAmE"}, ambiguous))
+	}
+
+	// Lowercase stays the default.
+	extract := common.FieldsExtractorFor(common.ReadParams{})
+	require.Equal(t, map[string]any{"billingcity": "Paris"}, extract([]string{"BillingCity"}, record))
+}
@@ -0,0 +1,135 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/stretchr/testify/require"
+	"github.com/subchen/go-xmldom"
+)
+
+func TestParseSOAPFault(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		fault *common.SOAPFault
+	}{
+		{
+			name: "SOAP 1.1 fault",
+			input: `<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/">` +
+				`<soapenv:Body><soapenv:Fault><faultcode>sf:INVALID_SESSION_ID</faultcode>` +
+				`<faultstring>Invalid Session ID found</faultstring></soapenv:Fault></soapenv:Body></soapenv:Envelope>`,
+			fault: &common.SOAPFault{Code: "sf:INVALID_SESSION_ID", Message: "Invalid Session ID found"},
+		},
+		{
+			name: "SOAP 1.2 fault",
+			input: `<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope"><env:Body><env:Fault>` +
+				`<env:Code><env:Value>env:Sender</env:Value></env:Code>` +
+				`<env:Reason><env:Text xml:lang="en">Missing argument</env:Text></env:Reason>` +
+				`</env:Fault></env:Body></env:Envelope>`,
+			fault: &common.SOAPFault{Code: "env:Sender", Message: "Missing argument"},
+		},
+		{
+			name: "Not a fault",
+			input: `<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/">` +
+				`<soapenv:Body><result/></soapenv:Body></soapenv:Envelope>`,
+		},
+		{
+			name:  "Not XML",
+			input: `{"message": "unavailable"}`,
+		},
+	}
+
+	for _, tt := range tests { // nolint:varnamelen
+		tt := tt // rebind, omit loop side effects for parallel goroutine
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.fault, common.ParseSOAPFault([]byte(tt.input)))
+		})
+	}
+}
+
+func TestSOAPClientCall(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		request := string(data)
+
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+
+		if !strings.Contains(request, "<sessionId>valid</sessionId>") {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/">` +
+				`<soapenv:Body><soapenv:Fault><faultcode>sf:INVALID_SESSION_ID</faultcode>` +
+				`<faultstring>Invalid Session ID found</faultstring></soapenv:Fault></soapenv:Body></soapenv:Envelope>`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/">` +
+			`<soapenv:Body><echoResponse><action>` + r.Header.Get("SOAPAction") + `</action></echoResponse>` +
+			`</soapenv:Body></soapenv:Envelope>`))
+	}))
+	defer server.Close()
+
+	session := "expired"
+
+	client := &common.SOAPClient{
+		XMLClient: &common.XMLHTTPClient{
+			HTTPClient: &common.HTTPClient{
+				Base:         server.URL,
+				Client:       http.DefaultClient,
+				ErrorHandler: common.InterpretSOAPError,
+			},
+		},
+		Namespace: "urn:example",
+		Headers: []common.SOAPHeaderFunc{
+			func(context.Context) (*xmldom.Node, error) {
+				return &xmldom.Node{Name: "SessionHeader", Children: []*xmldom.Node{
+					{Name: "sessionId", Text: session},
+				}}, nil
+			},
+		},
+	}
+
+	_, err := client.Call(context.Background(), "/soap", "echo", &xmldom.Node{Name: "echo"})
+	require.ErrorIs(t, err, common.ErrServer)
+	require.ErrorIs(t, err, common.ErrSOAPFault)
+
+	var fault *common.SOAPFault
+	require.True(t, errors.As(err, &fault))
+	require.True(t, fault.HasCode("INVALID_SESSION_ID"))
+
+	// Headers are created for every call.
+	session = "valid"
+
+	body, err := client.Call(context.Background(), "/soap", "echo", &xmldom.Node{Name: "echo"})
+	require.NoError(t, err)
+	require.Equal(t, "echo", body.GetChild("echoResponse").GetChild("action").Text)
+}
+
+func TestSOAPEnvelope(t *testing.T) {
+	t.Parallel()
+
+	envelope := common.SOAPEnvelope{
+		Namespace: "urn:example",
+		Body:      []*xmldom.Node{{Name: "ping"}},
+	}
+
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+
+		`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" `+
+		`xmlns:xsd="http://www.w3.org/2001/XMLSchema" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">`+
+		`<soapenv:Body xmlns="urn:example"><ping /></soapenv:Body></soapenv:Envelope>`,
+		envelope.Document().XML())
+}
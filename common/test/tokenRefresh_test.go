@@ -0,0 +1,364 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// newTokenServer issues access tokens "access-N" with rotating refresh tokens "refresh-N".
+// API endpoint accepts only the latest access token. Refresh token can be used once.
+func newTokenServer(t *testing.T, refreshes *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	var (
+		mut    sync.Mutex
+		latest = "refresh-0"
+	)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		defer mut.Unlock()
+
+		if r.URL.Path == "/token" {
+			if r.FormValue("refresh_token") != latest {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error": "invalid_grant"}`))
+
+				return
+			}
+
+			count := refreshes.Add(1)
+			latest = fmt.Sprintf("refresh-%d", count)
+
+			// Give concurrent callers time to pile up.
+			time.Sleep(20 * time.Millisecond)
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"access_token": "access-%d", "refresh_token": "%s", "expires_in": 3600}`,
+				count, latest)
+
+			return
+		}
+
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer access-%d", refreshes.Load()) {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		if data, _ := io.ReadAll(r.Body); r.Method == http.MethodPost && string(data) != "payload" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func newTokenTestClient(t *testing.T, serverURL string, token *oauth2.Token,
+	opts ...common.OAuthOption,
+) common.AuthenticatedHTTPClient {
+	t.Helper()
+
+	client, err := common.NewOAuthHTTPClient(context.Background(), append([]common.OAuthOption{
+		common.WithOAuthConfig(&oauth2.Config{
+			Endpoint: oauth2.Endpoint{TokenURL: serverURL + "/token", AuthStyle: oauth2.AuthStyleInParams},
+		}),
+		common.WithOAuthToken(token),
+	}, opts...)...)
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestTokenRefreshSingleFlight(t *testing.T) {
+	t.Parallel()
+
+	var refreshes atomic.Int32
+
+	server := newTokenServer(t, &refreshes)
+	defer server.Close()
+
+	var updates atomic.Int32
+
+	client := newTokenTestClient(t, server.URL,
+		&oauth2.Token{AccessToken: "expired", RefreshToken: "refresh-0", Expiry: time.Now().Add(-time.Minute)},
+		common.WithTokenUpdated(func(oldToken, newToken *oauth2.Token) error {
+			updates.Add(1)
+
+			return nil
+		}),
+	)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/api", nil)
+			rsp, err := client.Do(req)
+			if assert.NoError(t, err) {
+				assert.Equal(t, http.StatusOK, rsp.StatusCode)
+				_ = rsp.Body.Close()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	require.Equal(t, int32(1), refreshes.Load())
+	require.Equal(t, int32(1), updates.Load())
+}
+
+func TestTokenRefreshSkew(t *testing.T) {
+	t.Parallel()
+
+	var refreshes atomic.Int32
+
+	server := newTokenServer(t, &refreshes)
+	defer server.Close()
+
+	// Token is still valid, but expires within the skew.
+	client := newTokenTestClient(t, server.URL,
+		&oauth2.Token{AccessToken: "access-0", RefreshToken: "refresh-0", Expiry: time.Now().Add(time.Minute)},
+		common.WithTokenExpirySkew(5*time.Minute),
+	)
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/api", nil)
+	rsp, err := client.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rsp.StatusCode)
+	_ = rsp.Body.Close()
+
+	require.Equal(t, int32(1), refreshes.Load())
+}
+
+func TestTokenRefreshZeroSkew(t *testing.T) {
+	t.Parallel()
+
+	var refreshes atomic.Int32
+
+	server := newTokenServer(t, &refreshes)
+	defer server.Close()
+
+	// Token expires within the default skew, but skew is disabled.
+	client := newTokenTestClient(t, server.URL,
+		&oauth2.Token{AccessToken: "access-0", RefreshToken: "refresh-0", Expiry: time.Now().Add(5 * time.Second)},
+		common.WithTokenExpirySkew(0),
+	)
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/api", nil)
+	rsp, err := client.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rsp.StatusCode)
+	_ = rsp.Body.Close()
+
+	require.Equal(t, int32(0), refreshes.Load())
+}
+
+func TestTokenRefreshPersistenceFailure(t *testing.T) {
+	t.Parallel()
+
+	var refreshes atomic.Int32
+
+	server := newTokenServer(t, &refreshes)
+	defer server.Close()
+
+	client := newTokenTestClient(t, server.URL,
+		&oauth2.Token{AccessToken: "expired", RefreshToken: "refresh-0", Expiry: time.Now().Add(-time.Minute)},
+		common.WithTokenUpdated(func(oldToken, newToken *oauth2.Token) error {
+			return errors.New("database is down") // nolint:goerr113
+		}),
+	)
+
+	// Refresh token was spent, the new token is kept even though it wasn't persisted.
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/api", nil)
+		rsp, err := client.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, rsp.StatusCode)
+		_ = rsp.Body.Close()
+	}
+
+	require.Equal(t, int32(1), refreshes.Load())
+}
+
+func TestTokenRefreshSlowPersistence(t *testing.T) {
+	t.Parallel()
+
+	var refreshes atomic.Int32
+
+	server := newTokenServer(t, &refreshes)
+	defer server.Close()
+
+	saving := make(chan struct{})
+	release := make(chan struct{})
+
+	client := newTokenTestClient(t, server.URL,
+		&oauth2.Token{AccessToken: "expired", RefreshToken: "refresh-0", Expiry: time.Now().Add(-time.Minute)},
+		common.WithTokenUpdated(func(oldToken, newToken *oauth2.Token) error {
+			close(saving)
+			<-release
+
+			return nil
+		}),
+	)
+
+	send := func() error {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/api", nil)
+
+		rsp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+
+		_ = rsp.Body.Close()
+
+		if rsp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status %v", rsp.StatusCode) // nolint:goerr113
+		}
+
+		return nil
+	}
+
+	refreshed := make(chan error, 1)
+
+	go func() { refreshed <- send() }()
+
+	<-saving
+
+	// Refreshed token is used by other requests while it is still being saved.
+	done := make(chan error, 1)
+
+	go func() { done <- send() }()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Error("request waited for the token to be saved")
+	}
+
+	close(release)
+	require.NoError(t, <-refreshed)
+	require.Equal(t, int32(1), refreshes.Load())
+}
+
+func TestTokenRefreshOnUnauthorized(t *testing.T) {
+	t.Parallel()
+
+	var refreshes atomic.Int32
+
+	server := newTokenServer(t, &refreshes)
+	defer server.Close()
+
+	// Token looks valid, but it was revoked.
+	token := &oauth2.Token{AccessToken: "revoked", RefreshToken: "refresh-0", Expiry: time.Now().Add(time.Hour)}
+
+	client := newTokenTestClient(t, server.URL, token, common.WithRefreshOnUnauthorized(false))
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/api", nil)
+	rsp, err := client.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, rsp.StatusCode)
+	_ = rsp.Body.Close()
+
+	client = newTokenTestClient(t, server.URL, token)
+
+	// Body is sent again with the retry.
+	req, _ = http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL+"/api",
+		strings.NewReader("payload"))
+	rsp, err = client.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rsp.StatusCode)
+	_ = rsp.Body.Close()
+
+	require.Equal(t, int32(1), refreshes.Load())
+}
+
+type memoryRefreshLock struct {
+	mut sync.Mutex
+}
+
+func (l *memoryRefreshLock) Lock(ctx context.Context) (func(), error) {
+	l.mut.Lock()
+
+	return l.mut.Unlock, nil
+}
+
+func TestTokenRefreshLock(t *testing.T) {
+	t.Parallel()
+
+	var refreshes atomic.Int32
+
+	server := newTokenServer(t, &refreshes)
+	defer server.Close()
+
+	// Token store shared by two processes.
+	var (
+		storeMut sync.Mutex
+		stored   *oauth2.Token
+	)
+
+	lock := &memoryRefreshLock{}
+	options := []common.OAuthOption{
+		common.WithTokenRefreshLock(lock, func(ctx context.Context) (*oauth2.Token, error) {
+			storeMut.Lock()
+			defer storeMut.Unlock()
+
+			return stored, nil
+		}),
+		common.WithTokenUpdated(func(oldToken, newToken *oauth2.Token) error {
+			storeMut.Lock()
+			defer storeMut.Unlock()
+
+			stored = newToken
+
+			return nil
+		}),
+	}
+
+	expired := &oauth2.Token{AccessToken: "expired", RefreshToken: "refresh-0", Expiry: time.Now().Add(-time.Minute)}
+	first := newTokenTestClient(t, server.URL, expired, options...)
+	second := newTokenTestClient(t, server.URL, expired, options...)
+
+	var wg sync.WaitGroup
+
+	for _, client := range []common.AuthenticatedHTTPClient{first, second} {
+		wg.Add(1)
+
+		go func(client common.AuthenticatedHTTPClient) {
+			defer wg.Done()
+
+			req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/api", nil)
+			rsp, err := client.Do(req)
+			if assert.NoError(t, err) {
+				assert.Equal(t, http.StatusOK, rsp.StatusCode)
+				_ = rsp.Body.Close()
+			}
+		}(client)
+	}
+
+	wg.Wait()
+
+	// Second process reused the token, refresh token rotation didn't fail.
+	require.Equal(t, int32(1), refreshes.Load())
+}
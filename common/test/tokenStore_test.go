@@ -0,0 +1,201 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+var testTokenKey = common.StaticTokenKey(bytes.Repeat([]byte{7}, 32)) // nolint:gochecknoglobals
+
+func TestTokenStores(t *testing.T) { // nolint:funlen
+	t.Parallel()
+
+	newFileStore := func(keys common.TokenKeyProvider) func(t *testing.T) common.TokenStore {
+		return func(t *testing.T) common.TokenStore {
+			t.Helper()
+
+			store, err := common.NewFileTokenStore(filepath.Join(t.TempDir(), "tokens"), keys)
+			require.NoError(t, err)
+
+			return store
+		}
+	}
+
+	stores := map[string]func(t *testing.T) common.TokenStore{
+		"memory": func(t *testing.T) common.TokenStore {
+			t.Helper()
+
+			return common.NewInMemoryTokenStore()
+		},
+		"file":      newFileStore(nil),
+		"encrypted": newFileStore(testTokenKey),
+	}
+
+	for name, create := range stores {
+		create := create
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			store := create(t)
+
+			_, err := store.Load(ctx, "tenant/hubspot")
+			require.ErrorIs(t, err, common.ErrTokenNotFound)
+
+			token := &oauth2.Token{
+				AccessToken:  "access",
+				RefreshToken: "refresh",
+				TokenType:    "Bearer",
+				Expiry:       time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+			}
+			require.NoError(t, store.Save(ctx, "tenant/hubspot", token))
+			require.NoError(t, store.Save(ctx, "tenant/gong", &oauth2.Token{AccessToken: "other"}))
+
+			loaded, err := store.Load(ctx, "tenant/hubspot")
+			require.NoError(t, err)
+			assert.Equal(t, token.AccessToken, loaded.AccessToken)
+			assert.Equal(t, token.RefreshToken, loaded.RefreshToken)
+			assert.Equal(t, token.TokenType, loaded.TokenType)
+			assert.True(t, token.Expiry.Equal(loaded.Expiry))
+
+			require.NoError(t, store.Delete(ctx, "tenant/hubspot"))
+			require.NoError(t, store.Delete(ctx, "tenant/hubspot"))
+
+			_, err = store.Load(ctx, "tenant/hubspot")
+			require.ErrorIs(t, err, common.ErrTokenNotFound)
+
+			loaded, err = store.Load(ctx, "tenant/gong")
+			require.NoError(t, err)
+			assert.Equal(t, "other", loaded.AccessToken)
+		})
+	}
+}
+
+func TestFileTokenStoreEncryption(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := common.NewFileTokenStore(dir, testTokenKey)
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, "key", &oauth2.Token{AccessToken: "secret-access"}))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	info, err := files[0].Info()
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	data, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret-access")
+
+	// Token copied to the file of another key doesn't decrypt.
+	require.NoError(t, store.Save(ctx, "other", &oauth2.Token{AccessToken: "other-access"}))
+
+	files, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	for _, file := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file.Name()), data, 0o600))
+	}
+
+	_, err = store.Load(ctx, "other")
+	require.ErrorIs(t, err, common.ErrTokenDecryption)
+
+	wrongKey, err := common.NewFileTokenStore(dir, common.StaticTokenKey(bytes.Repeat([]byte{8}, 32)))
+	require.NoError(t, err)
+
+	_, err = wrongKey.Load(ctx, "key")
+	require.ErrorIs(t, err, common.ErrTokenDecryption)
+
+	shortKey, err := common.NewFileTokenStore(dir, common.StaticTokenKey("short"))
+	require.NoError(t, err)
+
+	_, err = shortKey.Load(ctx, "key")
+	require.ErrorIs(t, err, common.ErrInvalidEncryptionKey)
+}
+
+func TestOAuthClientWithTokenStore(t *testing.T) {
+	t.Parallel()
+
+	var refreshes atomic.Int32
+
+	server := newTokenServer(t, &refreshes)
+	defer server.Close()
+
+	ctx := context.Background()
+	store := common.NewInMemoryTokenStore()
+
+	// Stored token wins over the one passed to the constructor, which is stale.
+	require.NoError(t, store.Save(ctx, "conn", &oauth2.Token{
+		AccessToken: "expired", RefreshToken: "refresh-0", Expiry: time.Now().Add(-time.Minute),
+	}))
+
+	var updates atomic.Int32
+
+	client := newTokenTestClient(t, server.URL, &oauth2.Token{AccessToken: "stale", RefreshToken: "revoked"},
+		common.WithTokenStore(store, "conn"),
+		common.WithTokenUpdated(func(oldToken, newToken *oauth2.Token) error {
+			updates.Add(1)
+
+			return nil
+		}),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api", nil)
+	require.NoError(t, err)
+
+	rsp, err := client.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rsp.StatusCode)
+	_ = rsp.Body.Close()
+
+	saved, err := store.Load(ctx, "conn")
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", saved.AccessToken)
+	assert.Equal(t, "refresh-1", saved.RefreshToken)
+	assert.Equal(t, int32(1), updates.Load())
+}
+
+func TestOAuthClientSavesInitialToken(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := common.NewInMemoryTokenStore()
+	token := &oauth2.Token{AccessToken: "initial", RefreshToken: "refresh"}
+
+	_, err := common.NewOAuthHTTPClient(ctx,
+		common.WithOAuthConfig(&oauth2.Config{}),
+		common.WithOAuthToken(token),
+		common.WithTokenStore(store, "conn"),
+	)
+	require.NoError(t, err)
+
+	saved, err := store.Load(ctx, "conn")
+	require.NoError(t, err)
+	assert.Equal(t, "initial", saved.AccessToken)
+
+	// Without token neither in the store nor in options the client cannot be created.
+	_, err = common.NewOAuthHTTPClient(ctx,
+		common.WithOAuthConfig(&oauth2.Config{}),
+		common.WithTokenStore(store, "missing"),
+	)
+	require.ErrorIs(t, err, common.ErrMissingRefreshToken)
+}
@@ -0,0 +1,164 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/stretchr/testify/require"
+)
+
+type uploadReceived struct {
+	Method        string            `json:"method"`
+	ContentType   string            `json:"contentType"`
+	ContentLength int64             `json:"contentLength"`
+	Fields        map[string]string `json:"fields"`
+	Files         map[string]string `json:"files"`
+	FileNames     map[string]string `json:"fileNames"`
+	Body          string            `json:"body"`
+}
+
+func newUploadServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			// Body must be sent again to the new location.
+			http.Redirect(w, r, "/files", http.StatusTemporaryRedirect)
+
+			return
+		}
+
+		received := uploadReceived{
+			Method:        r.Method,
+			ContentType:   r.Header.Get("Content-Type"),
+			ContentLength: r.ContentLength,
+			Fields:        map[string]string{},
+			Files:         map[string]string{},
+			FileNames:     map[string]string{},
+		}
+
+		if strings.HasPrefix(received.ContentType, "multipart/form-data") {
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			for name, values := range r.MultipartForm.Value {
+				received.Fields[name] = values[0]
+			}
+
+			for name, files := range r.MultipartForm.File {
+				file, _ := files[0].Open()
+				data, _ := io.ReadAll(file)
+				received.Files[name] = string(data)
+				received.FileNames[name] = files[0].Filename
+			}
+		} else {
+			data, _ := io.ReadAll(r.Body)
+			received.Body = string(data)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(received)
+	}))
+}
+
+func TestUploadMultipart(t *testing.T) {
+	t.Parallel()
+
+	server := newUploadServer(t)
+	defer server.Close()
+
+	client := &common.JSONHTTPClient{
+		HTTPClient: &common.HTTPClient{
+			Base:   server.URL,
+			Client: http.DefaultClient,
+		},
+	}
+
+	rsp, err := client.Upload(context.Background(), http.MethodPost, "/files", common.MultipartBody{
+		Fields: []common.FormField{{Name: "folderId", Value: "42"}},
+		Files: []common.FormFile{{
+			FieldName:   "file",
+			FileName:    `report "Q1".pdf`,
+			ContentType: "application/pdf",
+			Reader:      bytes.NewReader([]byte("%PDF-1.4 content")),
+		}},
+	})
+	require.NoError(t, err)
+
+	received, err := common.UnmarshalJSON[uploadReceived](rsp)
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPost, received.Method)
+	require.True(t, received.ContentLength > 0, "content length must be known")
+	require.Equal(t, map[string]string{"folderId": "42"}, received.Fields)
+	require.Equal(t, map[string]string{"file": "%PDF-1.4 content"}, received.Files)
+	require.Equal(t, map[string]string{"file": `report "Q1".pdf`}, received.FileNames)
+
+	// Seekable parts are replayed on redirect.
+	rsp, err = client.Upload(context.Background(), http.MethodPost, "/moved", common.MultipartBody{
+		Fields: []common.FormField{{Name: "folderId", Value: "42"}},
+		Files: []common.FormFile{{
+			FieldName: "file",
+			FileName:  "notes.txt",
+			Reader:    strings.NewReader("notes"),
+		}},
+	})
+	require.NoError(t, err)
+
+	received, err = common.UnmarshalJSON[uploadReceived](rsp)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"folderId": "42"}, received.Fields)
+	require.Equal(t, map[string]string{"file": "notes"}, received.Files)
+}
+
+func TestUploadBinary(t *testing.T) {
+	t.Parallel()
+
+	server := newUploadServer(t)
+	defer server.Close()
+
+	client := &common.JSONHTTPClient{
+		HTTPClient: &common.HTTPClient{
+			Base:   server.URL,
+			Client: http.DefaultClient,
+		},
+	}
+
+	// Size of in-memory readers is detected.
+	rsp, err := client.Upload(context.Background(), http.MethodPut, "/files/1", common.BinaryBody{
+		Reader: strings.NewReader("binary data"),
+	})
+	require.NoError(t, err)
+
+	received, err := common.UnmarshalJSON[uploadReceived](rsp)
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPut, received.Method)
+	require.Equal(t, "application/octet-stream", received.ContentType)
+	require.Equal(t, int64(len("binary data")), received.ContentLength)
+	require.Equal(t, "binary data", received.Body)
+
+	// Stream of unknown size is chunked.
+	rsp, err = client.Upload(context.Background(), http.MethodPost, "/files", common.BinaryBody{
+		Reader:      io.LimitReader(strings.NewReader("streamed data"), 100),
+		ContentType: "text/plain",
+	})
+	require.NoError(t, err)
+
+	received, err = common.UnmarshalJSON[uploadReceived](rsp)
+	require.NoError(t, err)
+	require.Equal(t, "text/plain", received.ContentType)
+	require.Equal(t, int64(-1), received.ContentLength)
+	require.Equal(t, "streamed data", received.Body)
+
+	_, err = client.Upload(context.Background(), http.MethodPost, "/files", common.BinaryBody{})
+	require.ErrorIs(t, err, common.ErrMissingUploadBody)
+}
@@ -0,0 +1,127 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRecord(t *testing.T) { // nolint:funlen
+	t.Parallel()
+
+	metadata := &common.ObjectMetadata{
+		FieldsMap: map[string]string{
+			"id":        "ID",
+			"name":      "Name",
+			"employees": "Employees",
+			"closedate": "Close Date",
+			"owner":     "Owner",
+			"notes":     "Notes",
+		},
+		Fields: map[string]common.FieldMetadata{
+			"id":        {ValueType: common.ValueTypeString, ReadOnly: true},
+			"name":      {ValueType: common.ValueTypeString, Required: true},
+			"employees": {ValueType: common.ValueTypeInt},
+			"closedate": {ValueType: common.ValueTypeDate},
+			"owner":     {ValueType: common.ValueTypeString, CreateOnly: true},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		record   map[string]any
+		create   bool
+		expected []common.FieldProblem
+	}{
+		{
+			name:   "Valid record",
+			record: map[string]any{"Name": "Acme", "Employees": 12, "CloseDate": "2024-05-01", "notes": []int{1}},
+			create: true,
+		},
+		{
+			name:   "Every problem is reported",
+			record: map[string]any{"Id": "1", "Employees": 1.5, "CloseDate": "tomorrow", "age": 3},
+			create: true,
+			expected: []common.FieldProblem{
+				{Field: "CloseDate", Err: common.ErrFieldType},
+				{Field: "Employees", Err: common.ErrFieldType},
+				{Field: "Id", Err: common.ErrReadOnlyField},
+				{Field: "age", Err: common.ErrUnknownField},
+				{Field: "name", Err: common.ErrRequiredField},
+			},
+		},
+		{
+			name:   "Update doesn't require fields, but respects create only fields",
+			record: map[string]any{"owner": "Bob", "employees": float64(3)},
+			expected: []common.FieldProblem{
+				{Field: "owner", Err: common.ErrNotUpdatableField},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt // rebind, omit loop side effects for parallel goroutine
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := common.ValidateRecord("accounts", metadata, tt.record, tt.create)
+			if len(tt.expected) == 0 {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.ErrorIs(t, err, common.ErrInvalidRecord)
+
+			var validationErr *common.ValidationError
+			require.True(t, errors.As(err, &validationErr))
+			require.Len(t, validationErr.Problems, len(tt.expected))
+
+			for index, expected := range tt.expected {
+				problem := validationErr.Problems[index]
+				require.Equal(t, expected.Field, problem.Field)
+				require.ErrorIs(t, problem.Err, expected.Err)
+			}
+		})
+	}
+}
+
+func TestValidateTextRecords(t *testing.T) {
+	t.Parallel()
+
+	metadata := &common.ObjectMetadata{
+		FieldsMap: map[string]string{"name": "Name", "employees": "Employees"},
+		Fields: map[string]common.FieldMetadata{
+			"employees": {ValueType: common.ValueTypeInt},
+		},
+	}
+
+	err := common.ValidateTextRecords("accounts", metadata, []map[string]string{
+		{"Name": "Acme", "Employees": "12"},
+		{"Name": "Globex", "Employees": ""},
+		{"Name": "Initech", "Employees": "many"},
+	}, true)
+
+	var validationErr *common.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Equal(t, []common.FieldProblem{{Row: 3, Field: "Employees", Err: validationErr.Problems[0].Err}},
+		validationErr.Problems)
+	require.ErrorIs(t, err, common.ErrFieldType)
+}
+
+func TestValidateTextRecordsCreateOnly(t *testing.T) {
+	t.Parallel()
+
+	metadata := &common.ObjectMetadata{
+		FieldsMap: map[string]string{"name": "Name", "externalid": "External ID"},
+		Fields: map[string]common.FieldMetadata{
+			"externalid": {ValueType: common.ValueTypeString, CreateOnly: true},
+		},
+	}
+	records := []map[string]string{{"Name": "Acme", "ExternalId": "A-1"}}
+
+	require.NoError(t, common.ValidateTextRecords("accounts", metadata, records, true))
+	require.ErrorIs(t, common.ValidateTextRecords("accounts", metadata, records, false), common.ErrNotUpdatableField)
+}
@@ -1,11 +1,16 @@
 package test
 
 import (
+	"context"
 	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/amp-labs/connectors/common"
 	"github.com/stretchr/testify/require"
+	"github.com/subchen/go-xmldom"
 	"gotest.tools/v3/assert"
 )
 
@@ -212,3 +217,58 @@ func TestXMLValidation(t *testing.T) {
 		}
 	}
 }
+
+func TestXMLHTTPClientVerbs(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+
+			return
+		}
+
+		data, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		_, _ = w.Write([]byte(`<echo method="` + r.Method + `" type="` + r.Header.Get("Content-Type") + `">` +
+			string(data) + `</echo>`))
+	}))
+	defer server.Close()
+
+	client := &common.XMLHTTPClient{
+		HTTPClient: &common.HTTPClient{
+			Base:   server.URL,
+			Client: http.DefaultClient,
+		},
+	}
+
+	rsp, err := client.Post(context.Background(), "/items", &common.XMLData{
+		XMLName:  "item",
+		Children: []common.XMLSchema{common.XMLString("first")},
+	})
+	require.NoError(t, err)
+
+	root, err := rsp.GetRoot()
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPost, root.GetAttributeValue("method"))
+	require.Equal(t, "application/xml", root.GetAttributeValue("type"))
+	require.Equal(t, "first", root.GetChild("item").Text)
+
+	node := &xmldom.Node{Name: "item", Text: "second"}
+	rsp, err = client.Put(context.Background(), "/items/1", node, common.Header{Key: "Content-Type", Value: "text/xml"})
+	require.NoError(t, err)
+
+	root, err = rsp.GetRoot()
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPut, root.GetAttributeValue("method"))
+	require.Equal(t, "text/xml", root.GetAttributeValue("type"))
+	require.Equal(t, "second", root.GetChild("item").Text)
+
+	rsp, err = client.Delete(context.Background(), "/items/1")
+	require.NoError(t, err)
+	require.Nil(t, rsp)
+
+	_, err = client.Post(context.Background(), "/items", map[string]any{"item": "json"})
+	require.ErrorIs(t, err, common.ErrUnsupportedXMLBody)
+}
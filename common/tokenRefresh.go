@@ -0,0 +1,250 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// DefaultTokenExpirySkew is how long before the expiry the token is refreshed, the same as oauth2 library does.
+	DefaultTokenExpirySkew = 10 * time.Second
+
+	// DefaultGrantTokenLifetime is the expiry of grant tokens issued without expires_in, see WithGrantTokenLifetime.
+	DefaultGrantTokenLifetime = 15 * time.Minute
+
+	// DefaultJWTAssertionExpiry is how long JWT bearer assertions are valid, see WithJWTBearer.
+	// It is the longest validity Salesforce accepts, the oauth2 library would use an hour.
+	DefaultJWTAssertionExpiry = 3 * time.Minute
+)
+
+// ErrTokenRefreshUnsupported is returned by RefreshToken for clients not created by NewOAuthHTTPClient.
+var ErrTokenRefreshUnsupported = errors.New("client cannot refresh oauth token")
+
+// TokenRefreshLock lets several processes sharing the same connection refresh its token one at a time.
+// It is required with rotating refresh tokens, ex: Salesloft, Outreach, where every refresh token
+// can be used only once, and concurrent refreshes fail with ErrInvalidGrant.
+// Implementations are usually backed by a database or Redis.
+type TokenRefreshLock interface {
+	// Lock blocks until the lock is acquired or the context is done. The returned function releases the lock.
+	Lock(ctx context.Context) (unlock func(), err error)
+}
+
+// TokenReloadFunc returns the latest token persisted by any of the processes, or nil if there is none.
+type TokenReloadFunc func(ctx context.Context) (*oauth2.Token, error)
+
+// refreshingTokenSource returns the current token and refreshes it when it is about to expire
+// or was rejected by the provider. Concurrent callers wait for a single refresh.
+type refreshingTokenSource struct {
+	mut sync.Mutex
+	// ctx carries the HTTP client used for refresh requests.
+	ctx   context.Context // nolint:containedctx
+	token *oauth2.Token
+	// refresh obtains a new token, given the current one.
+	refresh      func(ctx context.Context, token *oauth2.Token) (*oauth2.Token, error)
+	skew         time.Duration
+	lock         TokenRefreshLock
+	reload       TokenReloadFunc
+	tokenUpdated func(oldToken, newToken *oauth2.Token) error
+}
+
+func newRefreshingTokenSource(ctx context.Context, params *oauthClientParams) *refreshingTokenSource {
+	source := &refreshingTokenSource{
+		ctx:          ctx,
+		token:        params.token,
+		skew:         *params.expirySkew,
+		lock:         params.refreshLock,
+		reload:       params.reload,
+		tokenUpdated: params.tokenUpdated,
+	}
+
+	switch {
+	case params.grant != nil:
+		// Grant doesn't use refresh token, it obtains a new token from scratch.
+		source.refresh = func(ctx context.Context, _ *oauth2.Token) (*oauth2.Token, error) {
+			token, err := params.grant(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			// Token without expiry would be used until the provider rejects it.
+			if token.Expiry.IsZero() {
+				token.Expiry = time.Now().Add(params.grantLifetime)
+			}
+
+			return token, nil
+		}
+	case params.tokenSource != nil:
+		// Custom source decides itself when to refresh, it is called whenever the token isn't valid.
+		source.refresh = func(context.Context, *oauth2.Token) (*oauth2.Token, error) {
+			return params.tokenSource.Token()
+		}
+	default:
+		source.refresh = func(ctx context.Context, token *oauth2.Token) (*oauth2.Token, error) {
+			// Token without access token is refreshed unconditionally.
+			// Refresh token is kept by oauth2 library if the provider doesn't return a new one.
+			return params.config.TokenSource(ctx, &oauth2.Token{RefreshToken: token.RefreshToken}).Token()
+		}
+	}
+
+	return source
+}
+
+// Token returns valid token, refreshing it if needed.
+func (s *refreshingTokenSource) Token() (*oauth2.Token, error) {
+	return s.tokenContext(s.ctx)
+}
+
+func (s *refreshingTokenSource) tokenContext(ctx context.Context) (*oauth2.Token, error) {
+	s.mut.Lock()
+
+	if s.isValid(s.token) {
+		tok := s.token
+		s.mut.Unlock()
+
+		return tok, nil
+	}
+
+	tok, persist, err := s.refreshLocked(ctx, s.token)
+	s.mut.Unlock()
+	persist()
+
+	return tok, err
+}
+
+// refreshRejected refreshes the token the provider has rejected. If it was already replaced,
+// ex: by a concurrent request which got rejected as well, the current token is returned without refresh.
+func (s *refreshingTokenSource) refreshRejected(ctx context.Context, rejected *oauth2.Token) (*oauth2.Token, error) {
+	s.mut.Lock()
+
+	if s.token != nil && s.token.AccessToken != rejected.AccessToken && s.isValid(s.token) {
+		tok := s.token
+		s.mut.Unlock()
+
+		return tok, nil
+	}
+
+	tok, persist, err := s.refreshLocked(ctx, rejected)
+	s.mut.Unlock()
+	persist()
+
+	return tok, err
+}
+
+// RefreshToken makes the client created by NewOAuthHTTPClient replace the token the provider has rejected.
+// It is meant for APIs taking the token outside the Authorization header, ex: SOAP session header,
+// where the client cannot see the rejection itself. If the token was already replaced, the current one is returned.
+// Token updated callbacks are called as for any other refresh.
+func RefreshToken(ctx context.Context, client AuthenticatedHTTPClient,
+	rejected *oauth2.Token,
+) (*oauth2.Token, error) {
+	httpClient, ok := client.(*http.Client)
+	if !ok {
+		return nil, ErrTokenRefreshUnsupported
+	}
+
+	transport, ok := httpClient.Transport.(*oauth2Transport)
+	if !ok {
+		return nil, ErrTokenRefreshUnsupported
+	}
+
+	return transport.Source.refreshRejected(ctx, rejected)
+}
+
+// refreshLocked must be called with s.mut held. The token is swapped right away, while the returned persist
+// function, to be called once s.mut is released, runs the token updated callback and then releases
+// the distributed lock, so that other processes reload the token only after it was saved.
+func (s *refreshingTokenSource) refreshLocked(
+	ctx context.Context, stale *oauth2.Token,
+) (tok *oauth2.Token, persist func(), err error) {
+	unlock := func() {}
+
+	if s.lock != nil {
+		unlock, err = s.lock.Lock(ctx)
+		if err != nil {
+			return nil, func() {}, err
+		}
+
+		// Another process may have refreshed the token while we were waiting.
+		if latest, err := s.reloadToken(ctx); err != nil {
+			return nil, unlock, err
+		} else if latest != nil && !sameToken(latest, stale) && s.isValid(latest) {
+			s.token = latest
+
+			return latest, unlock, nil
+		}
+	}
+
+	current := stale
+	if current == nil {
+		current = &oauth2.Token{}
+	}
+
+	// Refresh requests use the HTTP client from the constructor context.
+	tok, err = s.refresh(contextWithHTTPClient(ctx, s.ctx), current)
+	if err != nil {
+		return nil, unlock, err
+	}
+
+	previous := s.token
+	s.token = tok
+
+	if s.tokenUpdated == nil || sameToken(previous, tok) {
+		return tok, unlock, nil
+	}
+
+	return tok, func() {
+		defer unlock()
+
+		// The old refresh token may be already spent, so the new token is used even if it wasn't persisted.
+		if err := s.tokenUpdated(previous, tok); err != nil {
+			slog.Error("Failed to persist refreshed oauth token", "error", err)
+		}
+	}, nil
+}
+
+func (s *refreshingTokenSource) reloadToken(ctx context.Context) (*oauth2.Token, error) {
+	if s.reload == nil {
+		return nil, nil // nolint:nilnil
+	}
+
+	return s.reload(ctx)
+}
+
+// isValid reports whether the token has access token which doesn't expire within the skew.
+func (s *refreshingTokenSource) isValid(token *oauth2.Token) bool {
+	if token == nil || len(token.AccessToken) == 0 {
+		return false
+	}
+
+	if token.Expiry.IsZero() {
+		return true
+	}
+
+	return time.Now().Add(s.skew).Before(token.Expiry)
+}
+
+func sameToken(left, right *oauth2.Token) bool {
+	if left == nil || right == nil {
+		return left == right
+	}
+
+	return left.AccessToken == right.AccessToken &&
+		left.RefreshToken == right.RefreshToken &&
+		left.TokenType == right.TokenType &&
+		left.Expiry.Equal(right.Expiry)
+}
+
+// contextWithHTTPClient makes the request context carry the HTTP client used by oauth2 library.
+func contextWithHTTPClient(ctx context.Context, clientCtx context.Context) context.Context {
+	if client := clientCtx.Value(oauth2.HTTPClient); client != nil {
+		return context.WithValue(ctx, oauth2.HTTPClient, client)
+	}
+
+	return ctx
+}
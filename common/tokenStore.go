@@ -0,0 +1,236 @@
+package common
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+var (
+	// ErrTokenNotFound is returned by TokenStore when there is no token saved under the key.
+	ErrTokenNotFound = errors.New("token not found")
+	// ErrTokenDecryption is returned when stored token cannot be decrypted, ex: the key has changed.
+	ErrTokenDecryption = errors.New("failed to decrypt token")
+	// ErrInvalidEncryptionKey is returned when the key is not 32 bytes long, as required by AES-256.
+	ErrInvalidEncryptionKey = errors.New("encryption key must be 32 bytes")
+)
+
+// TokenStore persists OAuth tokens between process restarts. Key identifies the connection,
+// ex: customer and provider, the format is up to the caller.
+// Use WithTokenStore to make OAuth client load the token from the store and save refreshed ones back.
+type TokenStore interface {
+	// Load returns saved token, or ErrTokenNotFound.
+	Load(ctx context.Context, key string) (*oauth2.Token, error)
+	// Save replaces the token under the key.
+	Save(ctx context.Context, key string, token *oauth2.Token) error
+	// Delete removes the token, ex: when the connection is removed. Deleting missing token is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// TokenKeyProvider supplies the key used to encrypt tokens at rest, usually fetched from KMS or a secret manager.
+// The key must be 32 bytes long.
+type TokenKeyProvider interface {
+	EncryptionKey(ctx context.Context) ([]byte, error)
+}
+
+// StaticTokenKey is TokenKeyProvider with a fixed key.
+type StaticTokenKey []byte
+
+func (k StaticTokenKey) EncryptionKey(context.Context) ([]byte, error) {
+	return k, nil
+}
+
+// InMemoryTokenStore keeps tokens in process memory, they are lost on restart.
+// It is useful for tests and short-lived processes.
+type InMemoryTokenStore struct {
+	mut    sync.Mutex
+	tokens map[string]oauth2.Token
+}
+
+func NewInMemoryTokenStore() *InMemoryTokenStore {
+	return &InMemoryTokenStore{
+		tokens: make(map[string]oauth2.Token),
+	}
+}
+
+func (s *InMemoryTokenStore) Load(_ context.Context, key string) (*oauth2.Token, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	token, ok := s.tokens[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTokenNotFound, key)
+	}
+
+	// Copy is returned, so that the caller cannot modify the stored token.
+	return &token, nil
+}
+
+func (s *InMemoryTokenStore) Save(_ context.Context, key string, token *oauth2.Token) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.tokens[key] = *token
+
+	return nil
+}
+
+func (s *InMemoryTokenStore) Delete(_ context.Context, key string) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	delete(s.tokens, key)
+
+	return nil
+}
+
+// FileTokenStore keeps every token in a separate file of the directory. File names are hashed keys,
+// so keys can contain any characters. Files are replaced atomically and readable only by the owner.
+type FileTokenStore struct {
+	mut  sync.Mutex
+	dir  string
+	keys TokenKeyProvider
+}
+
+// NewFileTokenStore creates store in the directory, creating it if needed.
+// When keys is not nil tokens are encrypted with AES-256-GCM, otherwise they are stored as plain JSON.
+func NewFileTokenStore(dir string, keys TokenKeyProvider) (*FileTokenStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create token directory: %w", err)
+	}
+
+	return &FileTokenStore{
+		dir:  dir,
+		keys: keys,
+	}, nil
+}
+
+func (s *FileTokenStore) Load(ctx context.Context, key string) (*oauth2.Token, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrTokenNotFound, key)
+		}
+
+		return nil, err
+	}
+
+	if s.keys != nil {
+		data, err = s.decrypt(ctx, key, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	token := &oauth2.Token{}
+	if err = json.Unmarshal(data, token); err != nil {
+		return nil, fmt.Errorf("failed to parse stored token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (s *FileTokenStore) Save(ctx context.Context, key string, token *oauth2.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	if s.keys != nil {
+		data, err = s.encrypt(ctx, key, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	return WriteFileAtomic(s.path(key), data, 0o600)
+}
+
+func (s *FileTokenStore) Delete(_ context.Context, key string) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *FileTokenStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".token")
+}
+
+// encrypt seals the token, binding it to the store key. Token copied under another key fails to decrypt.
+func (s *FileTokenStore) encrypt(ctx context.Context, key string, plain []byte) ([]byte, error) {
+	aead, err := s.aead(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	// Nonce is stored in front of the ciphertext.
+	return aead.Seal(nonce, nonce, plain, []byte(key)), nil
+}
+
+func (s *FileTokenStore) decrypt(ctx context.Context, key string, data []byte) ([]byte, error) {
+	aead, err := s.aead(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < aead.NonceSize() {
+		return nil, ErrTokenDecryption
+	}
+
+	nonce, sealed := data[:aead.NonceSize()], data[aead.NonceSize():]
+
+	plain, err := aead.Open(nil, nonce, sealed, []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenDecryption, err)
+	}
+
+	return plain, nil
+}
+
+func (s *FileTokenStore) aead(ctx context.Context) (cipher.AEAD, error) { //nolint:ireturn
+	key, err := s.keys.EncryptionKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(key) != 32 { //nolint:gomnd
+		return nil, ErrInvalidEncryptionKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
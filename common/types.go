@@ -53,6 +53,9 @@ var (
 	// ErrMissingRefreshToken is returned when the refresh token is missing.
 	ErrMissingRefreshToken = errors.New("missing refresh token")
 
+	// ErrInvalidPrivateKey is returned when JWT bearer grant is given a key which is not RSA private key in PEM format.
+	ErrInvalidPrivateKey = errors.New("private key must be RSA key in PEM format")
+
 	// ErrEmptyBaseURL is returned when the URL is relative, and the base URL is empty.
 	ErrEmptyBaseURL = errors.New("empty base URL")
 
@@ -92,7 +95,12 @@ type ReadParams struct {
 	// The name of the object we are reading, e.g. "Account"
 	ObjectName string // required
 	// The fields we are reading from the object, e.g. ["Id", "Name", "BillingCity"]
+	// Nested fields are addressed by paths, e.g. ["Account.Name", "attributes.emails[0]"]
+	// Wildcard ["*"] is expanded into every field known to object metadata.
 	Fields []string // required, at least one field needed
+	// PreserveFieldCase returns ReadResultRow.Fields under provider API names instead of lowercase,
+	// e.g. "BillingCity" rather than "billingcity". Useful when records are written back to the provider.
+	PreserveFieldCase bool // optional, defaults to false
 	// NextPage is an opaque token that can be used to get the next page of results.
 	NextPage NextPageToken // optional, only set this if you want to read the next page of results
 	// Since is a timestamp that can be used to get only records that have changed since that time.
@@ -112,6 +120,14 @@ type WriteParams struct {
 	// RecordData is a JSON node representing the record of data we want to insert in the case of CREATE
 	// or fields of data we want to modify in case of an update
 	RecordData any // required
+
+	// IdempotencyKey makes create safer to retry. It is sent natively to providers that support it,
+	// otherwise connector emulates it via its IdempotencyStore on a best-effort basis, see IdempotentWrite.
+	IdempotencyKey string // optional
+
+	// Validate checks RecordData against object metadata before any request is sent.
+	// Unknown and read-only fields, mismatched types and missing required fields are reported via ValidationError.
+	Validate bool // optional, defaults to false
 }
 
 // DeleteParams defines how we are deleting data in SaaS API.
@@ -150,6 +166,8 @@ type ReadResult struct {
 type ReadResultRow struct {
 	// Fields is a map of requested provider field names to values.
 	// All field names are in lowercase (eg: accountid, name, billingcityid)
+	// Nested fields are keyed by the requested path in lowercase (eg: account.name, attributes.email)
+	// With ReadParams.PreserveFieldCase names keep provider case instead (eg: AccountId, Account.Name)
 	Fields map[string]interface{} `json:"fields"`
 	// Raw is the raw JSON response from the provider.
 	Raw map[string]interface{} `json:"raw"`
@@ -224,8 +242,52 @@ type ObjectMetadata struct {
 
 	// FieldsMap is a map of field names to field display names
 	FieldsMap map[string]string
+
+	// Fields is a map of field names to field details. It is optional and is populated
+	// only for the fields where the connector knows more than the display name.
+	Fields map[string]FieldMetadata
 }
 
+// FieldMetadata describes the value a field holds.
+type FieldMetadata struct {
+	// Name is the provider's canonical API name of the field, ex: "BillingCity".
+	// It is set when the FieldsMap key doesn't keep the provider case.
+	Name string
+
+	// ValueType is a provider agnostic type of the field value.
+	ValueType ValueType
+
+	// Required fields must be provided when a record is created.
+	Required bool
+
+	// ReadOnly fields are populated by the provider and cannot be written.
+	ReadOnly bool
+
+	// CreateOnly fields can be set when a record is created, but cannot be updated later.
+	CreateOnly bool
+
+	// Values lists allowed values of enumeration fields (select and multiselect).
+	Values []string
+}
+
+// ValueType is a provider agnostic type of the field value.
+type ValueType string
+
+const (
+	ValueTypeString       ValueType = "string"
+	ValueTypeBoolean      ValueType = "boolean"
+	ValueTypeInt          ValueType = "int"
+	ValueTypeFloat        ValueType = "float"
+	ValueTypeDate         ValueType = "date"
+	ValueTypeDateTime     ValueType = "datetime"
+	ValueTypeSingleSelect ValueType = "singleSelect"
+	ValueTypeMultiSelect  ValueType = "multiSelect"
+	ValueTypeObject       ValueType = "object"
+	ValueTypeArray        ValueType = "array"
+	// ValueTypeOther is used when provider type has no equivalent.
+	ValueTypeOther ValueType = "other"
+)
+
 type PostAuthInfo struct {
 	CatalogVars *map[string]string
 	RawResponse *JSONHTTPResponse
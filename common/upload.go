@@ -0,0 +1,280 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"strings"
+)
+
+// ErrMissingUploadBody is returned when upload has nothing to read from.
+var ErrMissingUploadBody = errors.New("upload body reader is not set")
+
+// UnknownSize marks a body whose length isn't known in advance, it is sent using chunked encoding.
+const UnknownSize = -1
+
+// UploadBody is a non-JSON request body, it is implemented by BinaryBody and MultipartBody.
+// The body can be sent again, ex: on redirect or retry after token refresh, only if every reader is an io.Seeker.
+type UploadBody interface {
+	// open returns the parts of the content to send in order, its type and length, or UnknownSize.
+	open() ([]io.Reader, string, int64, error)
+}
+
+// BinaryBody is a raw request body streamed from the Reader, ex: file content.
+type BinaryBody struct {
+	Reader io.Reader
+	// ContentType defaults to application/octet-stream.
+	ContentType string
+	// Size in bytes. Leave zero to detect it for in-memory readers and files,
+	// otherwise the body is sent in chunks, which some providers don't accept.
+	Size int64
+}
+
+func (b BinaryBody) open() ([]io.Reader, string, int64, error) {
+	if b.Reader == nil {
+		return nil, "", 0, ErrMissingUploadBody
+	}
+
+	contentType := b.ContentType
+	if len(contentType) == 0 {
+		contentType = "application/octet-stream"
+	}
+
+	size := b.Size
+	if size == 0 {
+		size = readerSize(b.Reader)
+	}
+
+	return []io.Reader{b.Reader}, contentType, size, nil
+}
+
+// FormFile is a file part of MultipartBody.
+type FormFile struct {
+	// FieldName is the form field, ex: "file".
+	FieldName string
+	FileName  string
+	// ContentType defaults to application/octet-stream.
+	ContentType string
+	Reader      io.Reader
+	// Size in bytes, leave zero to detect it, see BinaryBody.Size.
+	Size int64
+}
+
+// FormField is a text part of MultipartBody.
+type FormField struct {
+	Name  string
+	Value string
+}
+
+// MultipartBody is a multipart/form-data request body. Files are streamed, not buffered.
+// Parts are sent in order, fields first. Some providers expect metadata fields before the file.
+type MultipartBody struct {
+	Fields []FormField
+	Files  []FormFile
+	// Boundary is generated randomly when empty.
+	Boundary string
+}
+
+func (b MultipartBody) open() ([]io.Reader, string, int64, error) {
+	var frame bytes.Buffer
+
+	writer := multipart.NewWriter(&frame)
+
+	if len(b.Boundary) != 0 {
+		if err := writer.SetBoundary(b.Boundary); err != nil {
+			return nil, "", 0, err
+		}
+	}
+
+	// Multipart framing is kept in memory, while files are read from their readers as the request is sent.
+	readers := make([]io.Reader, 0, 2*len(b.Files)+1) // nolint:gomnd
+	size := int64(0)
+
+	flush := func() {
+		data := bytes.Clone(frame.Bytes())
+		frame.Reset()
+
+		readers = append(readers, bytes.NewReader(data))
+
+		if size != UnknownSize {
+			size += int64(len(data))
+		}
+	}
+
+	for _, field := range b.Fields {
+		if err := writer.WriteField(field.Name, field.Value); err != nil {
+			return nil, "", 0, err
+		}
+	}
+
+	for _, file := range b.Files {
+		if file.Reader == nil {
+			return nil, "", 0, fmt.Errorf("%w: %s", ErrMissingUploadBody, file.FieldName)
+		}
+
+		if _, err := writer.CreatePart(fileHeader(file)); err != nil {
+			return nil, "", 0, err
+		}
+
+		flush()
+
+		fileSize := file.Size
+		if fileSize == 0 {
+			fileSize = readerSize(file.Reader)
+		}
+
+		readers = append(readers, file.Reader)
+
+		if fileSize == UnknownSize {
+			size = UnknownSize
+		} else if size != UnknownSize {
+			size += fileSize
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", 0, err
+	}
+
+	flush()
+
+	return readers, writer.FormDataContentType(), size, nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"") // nolint:gochecknoglobals
+
+func fileHeader(file FormFile) textproto.MIMEHeader {
+	contentType := file.ContentType
+	if len(contentType) == 0 {
+		contentType = "application/octet-stream"
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace(file.FieldName), quoteEscaper.Replace(file.FileName)))
+	header.Set("Content-Type", contentType)
+
+	return header
+}
+
+// readerSize returns the number of bytes left in readers of known size, or UnknownSize.
+func readerSize(reader io.Reader) int64 {
+	switch typed := reader.(type) {
+	case *bytes.Buffer:
+		return int64(typed.Len())
+	case *bytes.Reader:
+		return int64(typed.Len())
+	case *strings.Reader:
+		return int64(typed.Len())
+	case *os.File:
+		info, err := typed.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return UnknownSize
+		}
+
+		offset, err := typed.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return UnknownSize
+		}
+
+		return info.Size() - offset
+	default:
+		return UnknownSize
+	}
+}
+
+// Upload sends a binary or multipart body with the given method, usually POST or PUT.
+// The body is streamed from its readers. If the response is not a 2xx, an error is returned.
+func (h *HTTPClient) Upload(ctx context.Context,
+	method string, url string, body UploadBody, headers ...Header,
+) (*http.Response, []byte, error) {
+	fullURL, err := h.getURL(url)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := makeUploadRequest(ctx, method, fullURL, headers, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return h.sendRequest(req)
+}
+
+// Upload sends a binary or multipart body and returns the response body as a JSON object.
+// Empty responses, ex: 204 No Content, return nil response.
+func (j *JSONHTTPClient) Upload(ctx context.Context,
+	method string, url string, body UploadBody, headers ...Header,
+) (*JSONHTTPResponse, error) {
+	res, data, err := j.HTTPClient.Upload(ctx, method, url, body, addAcceptJSONHeader(headers)...) //nolint:bodyclose
+	if err != nil {
+		return nil, j.ErrorPostProcessor.handleError(err)
+	}
+
+	return parseJSONResponse(res, data)
+}
+
+// makeUploadRequest creates a request streaming the body. Content-Type is set unless present in headers.
+func makeUploadRequest(ctx context.Context,
+	method string, url string, headers []Header, body UploadBody,
+) (*http.Request, error) {
+	if body == nil {
+		return nil, ErrMissingUploadBody
+	}
+
+	readers, contentType, size, err := body.open()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, io.MultiReader(readers...))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.ContentLength = size
+	req.GetBody = rewindableBody(readers)
+
+	req = addHeaders(req, headers)
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	return req, nil
+}
+
+// rewindableBody returns GetBody function which seeks every reader back to where it was before the request.
+// Body with a reader which cannot seek is sent only once, nil is returned.
+func rewindableBody(readers []io.Reader) func() (io.ReadCloser, error) {
+	offsets := make([]int64, len(readers))
+
+	for index, reader := range readers {
+		seeker, ok := reader.(io.Seeker)
+		if !ok {
+			return nil
+		}
+
+		offset, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil
+		}
+
+		offsets[index] = offset
+	}
+
+	return func() (io.ReadCloser, error) {
+		for index, reader := range readers {
+			if _, err := reader.(io.Seeker).Seek(offsets[index], io.SeekStart); err != nil {
+				return nil, err
+			}
+		}
+
+		return io.NopCloser(io.MultiReader(readers...)), nil
+	}
+}
@@ -0,0 +1,396 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidRecord is returned when record data doesn't match object metadata.
+	ErrInvalidRecord = errors.New("record doesn't match object metadata")
+	// ErrUnknownField is reported for fields absent from object metadata.
+	ErrUnknownField = errors.New("unknown field")
+	// ErrReadOnlyField is reported for fields that cannot be written.
+	ErrReadOnlyField = errors.New("field is read-only")
+	// ErrNotUpdatableField is reported for fields that can be set only when a record is created.
+	ErrNotUpdatableField = errors.New("field cannot be updated")
+	// ErrFieldType is reported when value doesn't match the field type.
+	ErrFieldType = errors.New("unexpected value type")
+	// ErrRequiredField is reported when a required field is missing on create.
+	ErrRequiredField = errors.New("required field is missing")
+	// ErrRecordDataNotObject is returned when RecordData cannot be represented as JSON object.
+	ErrRecordDataNotObject = errors.New("record data is not a JSON object")
+)
+
+// FieldProblem is a single reason why a record is invalid.
+type FieldProblem struct {
+	// Row is a 1-based position of the record in a batch, zero for a single record.
+	Row   int
+	Field string
+	Err   error
+}
+
+func (p FieldProblem) String() string {
+	if p.Row != 0 {
+		return fmt.Sprintf("row %v: %v: %v", p.Row, p.Field, p.Err)
+	}
+
+	return fmt.Sprintf("%v: %v", p.Field, p.Err)
+}
+
+// ValidationError lists every problem found in the record data, so that the caller can fix them at once.
+// It matches ErrInvalidRecord and each problem cause via errors.Is.
+type ValidationError struct {
+	ObjectName string
+	Problems   []FieldProblem
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		problems[i] = problem.String()
+	}
+
+	return fmt.Sprintf("%v: %v: %v", ErrInvalidRecord, e.ObjectName, strings.Join(problems, "; "))
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := []error{ErrInvalidRecord}
+	for _, problem := range e.Problems {
+		errs = append(errs, problem.Err)
+	}
+
+	return errs
+}
+
+// ValidateWrite checks WriteParams.RecordData against cached object metadata.
+// It does nothing unless WriteParams.Validate is set.
+func (c *MetadataCache) ValidateWrite(ctx context.Context, params WriteParams) error {
+	if !params.Validate {
+		return nil
+	}
+
+	record, err := RecordDataToMap(params.RecordData)
+	if err != nil {
+		return err
+	}
+
+	metadata, err := c.GetObjectMetadata(ctx, params.ObjectName)
+	if err != nil {
+		return err
+	}
+
+	return ValidateRecord(params.ObjectName, metadata, record, len(params.RecordId) == 0)
+}
+
+// ValidateRecord checks record against metadata. Field names are matched ignoring the case.
+// Required fields are checked only on create. Fields without FieldMetadata are checked only for existence.
+// Returns *ValidationError or nil.
+func ValidateRecord(objectName string, metadata *ObjectMetadata, record map[string]any, create bool) error {
+	validator := newRecordValidator(metadata)
+	problems := make([]FieldProblem, 0)
+
+	for _, name := range sortedKeys(record) {
+		field, err := validator.lookup(name, create)
+		if err == nil {
+			err = checkValue(field, record[name])
+		}
+
+		if err != nil {
+			problems = append(problems, FieldProblem{Field: name, Err: err})
+		}
+	}
+
+	if create {
+		problems = append(problems, validator.missingRequired(record)...)
+	}
+
+	return newValidationError(objectName, problems)
+}
+
+// ValidateTextRecords checks batch of records where every value is text, ex: rows of a CSV file.
+// Empty text stands for null. Required fields are not checked, create tells whether
+// create-only fields may be set, see TextRecordsValidator.
+// Returns *ValidationError or nil.
+func ValidateTextRecords(objectName string, metadata *ObjectMetadata, records []map[string]string, create bool) error {
+	validator := NewTextRecordsValidator(objectName, metadata, create)
+
+	for _, record := range records {
+		validator.Check(record)
+	}
+
+	return validator.Err()
+}
+
+// TextRecordsValidator checks text records one at a time, so that large batches don't have to be held in memory.
+// Records may create or update data, so required fields are not checked.
+// Create-only fields are reported unless every record creates data, upserts should be checked as updates.
+type TextRecordsValidator struct {
+	objectName string
+	create     bool
+	validator  *recordValidator
+	problems   []FieldProblem
+	rows       int
+}
+
+// NewTextRecordsValidator creates validator of records written to the object.
+func NewTextRecordsValidator(objectName string, metadata *ObjectMetadata, create bool) *TextRecordsValidator {
+	return &TextRecordsValidator{
+		objectName: objectName,
+		create:     create,
+		validator:  newRecordValidator(metadata),
+		problems:   make([]FieldProblem, 0),
+	}
+}
+
+// Check validates the next record. Empty text stands for null.
+func (v *TextRecordsValidator) Check(record map[string]string) {
+	v.rows++
+
+	for _, name := range sortedKeys(record) {
+		field, err := v.validator.lookup(name, v.create)
+		if err == nil {
+			err = checkText(field, record[name])
+		}
+
+		if err != nil {
+			v.problems = append(v.problems, FieldProblem{Row: v.rows, Field: name, Err: err})
+		}
+	}
+}
+
+// Err returns *ValidationError describing every problem found so far, or nil.
+func (v *TextRecordsValidator) Err() error {
+	return newValidationError(v.objectName, v.problems)
+}
+
+// RecordDataToMap converts any JSON serializable record data into a map.
+func RecordDataToMap(recordData any) (map[string]any, error) {
+	if data, ok := recordData.(map[string]any); ok {
+		return data, nil
+	}
+
+	bytes, err := json.Marshal(recordData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRecordDataNotObject, err)
+	}
+
+	var data map[string]any
+	if err = json.Unmarshal(bytes, &data); err != nil || data == nil {
+		return nil, ErrRecordDataNotObject
+	}
+
+	return data, nil
+}
+
+func newValidationError(objectName string, problems []FieldProblem) error {
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return &ValidationError{
+		ObjectName: objectName,
+		Problems:   problems,
+	}
+}
+
+type recordValidator struct {
+	// names holds every known field in lowercase.
+	names  map[string]bool
+	fields map[string]FieldMetadata
+}
+
+func newRecordValidator(metadata *ObjectMetadata) *recordValidator {
+	validator := &recordValidator{
+		names:  make(map[string]bool, len(metadata.FieldsMap)),
+		fields: make(map[string]FieldMetadata, len(metadata.Fields)),
+	}
+
+	for name := range metadata.FieldsMap {
+		validator.names[strings.ToLower(name)] = true
+	}
+
+	for name, field := range metadata.Fields {
+		validator.names[strings.ToLower(name)] = true
+		validator.fields[strings.ToLower(name)] = field
+	}
+
+	return validator
+}
+
+// lookup returns field details, or error if the field cannot be written.
+// Nil field without error means metadata knows the field only by name.
+func (v *recordValidator) lookup(name string, create bool) (*FieldMetadata, error) {
+	key := strings.ToLower(name)
+
+	if len(v.names) != 0 && !v.names[key] {
+		return nil, ErrUnknownField
+	}
+
+	field, ok := v.fields[key]
+	if !ok {
+		return nil, nil // nolint:nilnil
+	}
+
+	if field.ReadOnly {
+		return nil, ErrReadOnlyField
+	}
+
+	if field.CreateOnly && !create {
+		return nil, ErrNotUpdatableField
+	}
+
+	return &field, nil
+}
+
+func (v *recordValidator) missingRequired(record map[string]any) []FieldProblem {
+	provided := make(map[string]bool, len(record))
+
+	for name, value := range record {
+		if value != nil {
+			provided[strings.ToLower(name)] = true
+		}
+	}
+
+	problems := make([]FieldProblem, 0)
+
+	for _, name := range sortedKeys(v.fields) {
+		if v.fields[name].Required && !provided[name] {
+			problems = append(problems, FieldProblem{Field: name, Err: ErrRequiredField})
+		}
+	}
+
+	return problems
+}
+
+func checkValue(field *FieldMetadata, value any) error { // nolint:cyclop
+	if field == nil || value == nil {
+		return nil
+	}
+
+	kind := reflect.TypeOf(value).Kind()
+
+	var valid bool
+
+	switch field.ValueType {
+	case ValueTypeString, ValueTypeSingleSelect:
+		valid = kind == reflect.String
+	case ValueTypeBoolean:
+		valid = kind == reflect.Bool
+	case ValueTypeInt:
+		valid = isInteger(value)
+	case ValueTypeFloat:
+		valid = isNumber(value)
+	case ValueTypeDate, ValueTypeDateTime:
+		valid = isTime(value)
+	case ValueTypeMultiSelect:
+		// Some providers expect a list, others a delimited string, ex: Salesforce "A;B".
+		valid = kind == reflect.String || kind == reflect.Slice || kind == reflect.Array
+	case ValueTypeObject:
+		valid = kind == reflect.Map || kind == reflect.Struct
+	case ValueTypeArray:
+		valid = kind == reflect.Slice || kind == reflect.Array
+	case ValueTypeOther:
+		valid = true
+	}
+
+	if !valid {
+		return fmt.Errorf("%w: %T is not %v", ErrFieldType, value, field.ValueType)
+	}
+
+	return nil
+}
+
+func checkText(field *FieldMetadata, text string) error {
+	if field == nil || len(text) == 0 {
+		return nil
+	}
+
+	var err error
+
+	switch field.ValueType { // nolint:exhaustive
+	case ValueTypeBoolean:
+		_, err = strconv.ParseBool(text)
+	case ValueTypeInt:
+		_, err = strconv.ParseInt(text, 10, 64)
+	case ValueTypeFloat:
+		_, err = strconv.ParseFloat(text, 64)
+	case ValueTypeDate, ValueTypeDateTime:
+		if !isTime(text) {
+			err = ErrFieldType
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %q is not %v", ErrFieldType, text, field.ValueType)
+	}
+
+	return nil
+}
+
+func isInteger(value any) bool {
+	switch typed := value.(type) {
+	case float64:
+		return typed == float64(int64(typed))
+	case float32:
+		return typed == float32(int64(typed))
+	case json.Number:
+		_, err := typed.Int64()
+
+		return err == nil
+	}
+
+	switch reflect.TypeOf(value).Kind() { // nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isNumber(value any) bool {
+	if _, ok := value.(json.Number); ok {
+		return true
+	}
+
+	switch reflect.TypeOf(value).Kind() { // nolint:exhaustive
+	case reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return isInteger(value)
+	}
+}
+
+func isTime(value any) bool {
+	switch typed := value.(type) {
+	case time.Time:
+		return true
+	case string:
+		for _, layout := range TimeLayouts {
+			if _, err := time.Parse(layout, typed); err == nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func sortedKeys[V any](dictionary map[string]V) []string {
+	keys := make([]string, 0, len(dictionary))
+	for key := range dictionary {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -27,6 +27,8 @@ var (
 	ErrNotXMLChildren = errors.New("children must be of type 'XMLData' or 'XMLString'")
 	ErrNoSelfClosing  = errors.New("selfClosing cannot be true if children are not present")
 	ErrNoParens       = errors.New("value cannot contain < or >")
+	// ErrUnsupportedXMLBody is returned when request body is neither XMLSchema nor xmldom document or node.
+	ErrUnsupportedXMLBody = errors.New("request body is not XML")
 )
 
 // XMLHTTPClient is an HTTP client that can parse XML response.
@@ -70,6 +72,104 @@ func (j *XMLHTTPClient) Get(ctx context.Context, url string, headers ...Header)
 	return parseXMLResponse(res, body)
 }
 
+// Post makes a POST request to the given URL and returns the response body as a XML object.
+// ReqBody can be XMLSchema, ex: XMLData, or xmldom Document or Node.
+// Content-Type defaults to application/xml, SOAP APIs usually expect text/xml passed via headers.
+func (j *XMLHTTPClient) Post(ctx context.Context,
+	url string, reqBody any, headers ...Header,
+) (*XMLHTTPResponse, error) {
+	return j.send(ctx, http.MethodPost, url, reqBody, headers)
+}
+
+// Put makes a PUT request to the given URL and returns the response body as a XML object, see Post.
+func (j *XMLHTTPClient) Put(ctx context.Context,
+	url string, reqBody any, headers ...Header,
+) (*XMLHTTPResponse, error) {
+	return j.send(ctx, http.MethodPut, url, reqBody, headers)
+}
+
+// Delete makes a DELETE request to the given URL. Empty response, ex: 204 No Content, returns nil response.
+func (j *XMLHTTPClient) Delete(ctx context.Context, url string, headers ...Header) (*XMLHTTPResponse, error) {
+	return j.send(ctx, http.MethodDelete, url, nil, headers)
+}
+
+func (j *XMLHTTPClient) send(ctx context.Context,
+	method string, url string, reqBody any, headers []Header,
+) (*XMLHTTPResponse, error) {
+	fullURL, err := j.HTTPClient.getURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := makeXMLRequest(ctx, method, fullURL, addAcceptXMLHeader(headers), reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	res, body, err := j.HTTPClient.sendRequest(req) //nolint:bodyclose
+	if err != nil {
+		return nil, j.ErrorPostProcessor.handleError(err)
+	}
+
+	if res == nil || len(body) == 0 {
+		// Error was ignored by the ErrorHandler, or response has no content.
+		return nil, nil // nolint:nilnil
+	}
+
+	return parseXMLResponse(res, body)
+}
+
+// makeXMLRequest creates a request with XML body. Content-Type is set unless present in headers.
+// Nil body makes a request without content.
+func makeXMLRequest(ctx context.Context,
+	method string, url string, headers []Header, body any,
+) (*http.Request, error) {
+	if body == nil {
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
+
+		return addHeaders(req, headers), nil
+	}
+
+	data, err := marshalXMLBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.ContentLength = int64(len(data))
+
+	req = addHeaders(req, headers)
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/xml")
+	}
+
+	return req, nil
+}
+
+func marshalXMLBody(body any) ([]byte, error) {
+	switch typed := body.(type) {
+	case *xmldom.Document:
+		return []byte(typed.XML()), nil
+	case *xmldom.Node:
+		return []byte(typed.XML()), nil
+	case XMLSchema:
+		if err := typed.Validate(); err != nil {
+			return nil, err
+		}
+
+		return []byte(typed.String()), nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedXMLBody, body)
+	}
+}
+
 // parseXMLResponse parses the given HTTP response and returns a XMLHTTPResponse.
 func parseXMLResponse(res *http.Response, body []byte) (*XMLHTTPResponse, error) {
 	if len(body) == 0 {
@@ -84,7 +184,7 @@ func parseXMLResponse(res *http.Response, body []byte) (*XMLHTTPResponse, error)
 			return nil, fmt.Errorf("failed to parse content type: %w", err)
 		}
 
-		if mimeType != "application/xml" {
+		if !isXMLMediaType(mimeType) {
 			return nil, fmt.Errorf("%w: expected content type to be application/xml, got %s", ErrNotXML, mimeType)
 		}
 	}
@@ -103,11 +203,23 @@ func parseXMLResponse(res *http.Response, body []byte) (*XMLHTTPResponse, error)
 	}, nil
 }
 
+// isXMLMediaType accepts application/xml and its variants used by SOAP, ex: text/xml, application/soap+xml.
+func isXMLMediaType(mimeType string) bool {
+	return mimeType == "application/xml" || mimeType == "text/xml" || strings.HasSuffix(mimeType, "+xml")
+}
+
+// addAcceptXMLHeader adds Accept header unless the caller has chosen one, ex: text/xml for SOAP.
 func addAcceptXMLHeader(headers []Header) []Header {
 	if headers == nil {
 		headers = make([]Header, 0)
 	}
 
+	for _, header := range headers {
+		if strings.EqualFold(header.Key, "Accept") {
+			return headers
+		}
+	}
+
 	return append(headers, Header{Key: "Accept", Value: "application/xml"})
 }
 
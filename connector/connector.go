@@ -58,6 +58,8 @@ func NewConnector(
 	// Set provider info & http client options
 	conn.ProviderInfo = providerInfo
 	conn.Client.HTTPClient.ErrorHandler = conn.interpretError
+	conn.Client.HTTPClient.RedactedHeaders = append(conn.Client.HTTPClient.RedactedHeaders,
+		providerInfo.RedactedHeaders()...)
 
 	// Set base URL
 	conn.Client.HTTPClient.Base = conn.ProviderInfo.BaseURL
@@ -6,8 +6,11 @@ import (
 	"net/http"
 
 	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/common/paramsbuilder"
 	"github.com/amp-labs/connectors/providers"
 	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/oauth2/jwt"
 )
 
 var (
@@ -21,6 +24,7 @@ var (
 type Option func(*connectorParams)
 
 type connectorParams struct {
+	paramsbuilder.Interceptors
 	provider      providers.Provider
 	client        *common.JSONHTTPClient
 	substitutions map[string]string
@@ -35,6 +39,8 @@ func (p *connectorParams) prepare() (*connectorParams, error) {
 		return nil, ErrMissingClient
 	}
 
+	p.ApplyInterceptors(p.client.HTTPClient)
+
 	return p, nil
 }
 
@@ -67,6 +73,44 @@ func WithClient(ctx context.Context, client *http.Client, config *oauth2.Config,
 	}
 }
 
+// WithClientCredentialsClient sets the http client authenticated with client credentials grant.
+func WithClientCredentialsClient(ctx context.Context, client *http.Client, config *clientcredentials.Config,
+	opts ...common.OAuthOption,
+) Option {
+	return func(params *connectorParams) {
+		options := []common.OAuthOption{
+			common.WithOAuthClient(client),
+			common.WithClientCredentials(config),
+		}
+
+		oauthClient, err := common.NewOAuthHTTPClient(ctx, append(options, opts...)...)
+		if err != nil {
+			panic(err) // caught in NewConnector
+		}
+
+		WithAuthenticatedClient(oauthClient)(params)
+	}
+}
+
+// WithJWTBearerClient sets the http client authenticated with JWT bearer grant.
+func WithJWTBearerClient(ctx context.Context, client *http.Client, config *jwt.Config,
+	opts ...common.OAuthOption,
+) Option {
+	return func(params *connectorParams) {
+		options := []common.OAuthOption{
+			common.WithOAuthClient(client),
+			common.WithJWTBearer(config),
+		}
+
+		oauthClient, err := common.NewOAuthHTTPClient(ctx, append(options, opts...)...)
+		if err != nil {
+			panic(err) // caught in NewConnector
+		}
+
+		WithAuthenticatedClient(oauthClient)(params)
+	}
+}
+
 // WithAuthenticatedClient sets the http client to use for the connector. Its usage is optional.
 func WithAuthenticatedClient(client common.AuthenticatedHTTPClient) Option {
 	return func(params *connectorParams) {
@@ -78,3 +122,17 @@ func WithAuthenticatedClient(client common.AuthenticatedHTTPClient) Option {
 		}
 	}
 }
+
+// WithInterceptors adds middleware of paramsbuilder.Interceptors. Its usage is optional.
+func WithInterceptors(interceptors ...common.Interceptor) Option {
+	return func(params *connectorParams) {
+		params.WithInterceptors(interceptors...)
+	}
+}
+
+// WithCircuitBreaker sets the breaker of paramsbuilder.Interceptors. Its usage is optional.
+func WithCircuitBreaker(breaker *common.CircuitBreaker) Option {
+	return func(params *connectorParams) {
+		params.WithCircuitBreaker(breaker)
+	}
+}
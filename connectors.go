@@ -144,6 +144,9 @@ var (
 	// ErrServer represents non-retryable errors caused by something on the server.
 	ErrServer = common.ErrServer
 
+	// ErrCircuitOpen means the request wasn't sent, because the provider is considered unavailable.
+	ErrCircuitOpen = common.ErrCircuitOpen
+
 	// ErrUnknownConnector represents an unknown connector.
 	ErrUnknownConnector = errors.New("unknown connector")
 )
@@ -176,6 +179,11 @@ func newMock(opts map[string]any) (Connector, error) { //nolint:ireturn
 		options = append(options, mock.WithClient(c))
 	}
 
+	s, valid := getParam[*mock.Store](opts, "store")
+	if valid {
+		options = append(options, mock.WithStore(s))
+	}
+
 	a, valid := getParam[common.AuthenticatedHTTPClient](opts, "authenticated-client")
 	if valid {
 		options = append(options, mock.WithAuthenticatedClient(a))
@@ -191,6 +199,11 @@ func newMock(opts map[string]any) (Connector, error) { //nolint:ireturn
 		options = append(options, mock.WithWrite(w))
 	}
 
+	d, valid := getParam[func(ctx context.Context, params DeleteParams) (*DeleteResult, error)](opts, "delete")
+	if valid {
+		options = append(options, mock.WithDelete(d))
+	}
+
 	l, valid := getParam[func(ctx context.Context, objectNames []string) (*ListObjectMetadataResult, error)](
 		opts, "list-object-metadata")
 	if valid {
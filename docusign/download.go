@@ -0,0 +1,30 @@
+package docusign
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/amp-labs/connectors/common"
+)
+
+const apiVersion = "v2.1"
+
+// DownloadEnvelopeDocument streams a document of the envelope. Besides document ID,
+// the API accepts "combined" for all documents as one PDF and "archive" for a ZIP file.
+// The caller must close the body.
+func (c *Connector) DownloadEnvelopeDocument(ctx context.Context,
+	accountID, envelopeID, documentID string,
+) (*common.Download, error) {
+	location, err := url.JoinPath("restapi", apiVersion,
+		"accounts", accountID, "envelopes", envelopeID, "documents", documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	download, err := c.Client.Download(ctx, location)
+	if err != nil {
+		return nil, c.HandleError(err)
+	}
+
+	return download, nil
+}
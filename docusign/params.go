@@ -5,10 +5,13 @@ import (
 	"net/http"
 
 	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/common/paramsbuilder"
 	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
 )
 
 type docusignParams struct {
+	paramsbuilder.Interceptors
 	client *common.JSONHTTPClient
 }
 
@@ -34,6 +37,27 @@ func WithClient(ctx context.Context, client *http.Client, config *oauth2.Config,
 	}
 }
 
+// WithJWTBearerClient authenticates using JWT grant, impersonating the user who has granted consent.
+// Email is the integration key, Subject is the user ID, Audience is "account.docusign.com",
+// or "account-d.docusign.com" for developer accounts, Scopes should include "signature" and "impersonation".
+func WithJWTBearerClient(ctx context.Context, client *http.Client, config *jwt.Config,
+	opts ...common.OAuthOption,
+) Option {
+	return func(params *docusignParams) {
+		options := []common.OAuthOption{
+			common.WithOAuthClient(client),
+			common.WithJWTBearer(config),
+		}
+
+		oauthClient, err := common.NewOAuthHTTPClient(ctx, append(options, opts...)...)
+		if err != nil {
+			panic(err) // caught in NewConnector
+		}
+
+		WithAuthenticatedClient(oauthClient)(params)
+	}
+}
+
 func WithAuthenticatedClient(client common.AuthenticatedHTTPClient) Option {
 	return func(params *docusignParams) {
 		params.client = &common.JSONHTTPClient{
@@ -50,5 +74,21 @@ func (params *docusignParams) prepare() (*docusignParams, error) {
 		return nil, ErrMissingClient
 	}
 
+	params.ApplyInterceptors(params.client.HTTPClient)
+
 	return params, nil
 }
+
+// WithInterceptors adds middleware of paramsbuilder.Interceptors. Its usage is optional.
+func WithInterceptors(interceptors ...common.Interceptor) Option {
+	return func(params *docusignParams) {
+		params.WithInterceptors(interceptors...)
+	}
+}
+
+// WithCircuitBreaker sets the breaker of paramsbuilder.Interceptors. Its usage is optional.
+func WithCircuitBreaker(breaker *common.CircuitBreaker) Option {
+	return func(params *docusignParams) {
+		params.WithCircuitBreaker(breaker)
+	}
+}
@@ -0,0 +1,57 @@
+package dynamicscrm
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/test/conformance"
+	"github.com/amp-labs/connectors/test/utils/mockutils"
+)
+
+// Write is not checked, Microsoft doesn't return the id of the created record.
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	responseContactsFirstPage := mockutils.DataFromFile(t, "read-contacts-first-page.json")
+	responseContactsLastPage := mockutils.DataFromFile(t, "read-contacts-last-page.json")
+
+	conformance.Run(t, conformance.Suite{
+		NewConnector: func(serverURL string) (any, error) {
+			connector, err := NewConnector(
+				WithAuthenticatedClient(http.DefaultClient),
+				WithWorkspace("test-workspace"),
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			// for testing we want to redirect calls to our mock server
+			connector.setBaseURL(serverURL)
+
+			return connector, nil
+		},
+		Read: &conformance.ReadSpec{
+			Params: common.ReadParams{
+				ObjectName: "contacts",
+				Fields:     []string{"fullname", "EmailAddress1"},
+			},
+			Pages: []conformance.Response{
+				conformance.JSON(responseContactsFirstPage),
+				conformance.JSON(responseContactsLastPage),
+			},
+			Since: &conformance.SinceSpec{
+				Timestamp: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+				IsFiltered: func(req *http.Request, since time.Time) bool {
+					return req.URL.Query().Get("$filter") == "modifiedon ge 2024-05-01T10:00:00Z"
+				},
+			},
+		},
+		Delete: &conformance.DeleteSpec{
+			Params:   common.DeleteParams{ObjectName: "contacts", RecordId: "cdcfa450-cb0c-ea11-a813-000d3a1b1223"},
+			Response: conformance.Response{Status: http.StatusNoContent},
+		},
+		Errors: conformance.StandardErrorCases([]byte(`{"error": {"code": "0x80072560", "message": "Unauthorized"}}`)),
+	})
+}
@@ -21,6 +21,9 @@ type Connector struct {
 	Module    string
 	Client    *common.JSONHTTPClient
 	XMLClient *common.XMLHTTPClient
+
+	// metadata is used to expand wildcard fields and to validate writes.
+	metadata *common.MetadataCache
 }
 
 func NewConnector(opts ...Option) (conn *Connector, outErr error) {
@@ -35,6 +38,7 @@ func NewConnector(opts ...Option) (conn *Connector, outErr error) {
 	}
 
 	httpClient := params.Client.Caller
+	params.ApplyInterceptors(httpClient)
 	conn = &Connector{
 		Module: params.Module.Suffix,
 		Client: &common.JSONHTTPClient{
@@ -57,6 +61,7 @@ func NewConnector(opts ...Option) (conn *Connector, outErr error) {
 	conn.Client.HTTPClient.ErrorHandler = interpreter.ErrorHandler{
 		JSON: conn.interpretJSONError,
 	}.Handle
+	conn.metadata = common.NewMetadataCache(conn.ListObjectMetadata)
 
 	return conn, nil
 }
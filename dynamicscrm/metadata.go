@@ -78,8 +78,10 @@ func extractEntities(root *xmldom.Node) (EntitySet, error) {
 		entityName := property.Parent.GetAttributeValue("Name")
 		parentName := property.Parent.GetAttributeValue("BaseType")
 		entity := entities.GetOrCreate(entityName, parentName)
-		propertyName := property.GetAttributeValue("Name")
-		entity.AddProperty(propertyName)
+		entity.AddProperty(Property{
+			Name: property.GetAttributeValue("Name"),
+			Type: property.GetAttributeValue("Type"),
+		})
 	})
 
 	queryListAbstractEntities := fmt.Sprintf(
@@ -105,7 +107,8 @@ func extractEntities(root *xmldom.Node) (EntitySet, error) {
 }
 
 // Select entities that match entity names of interest.
-// Every property has display identical to itself.
+// Every property has display identical to itself and is described by its type.
+// Allowed values of choice fields are not part of $metadata, so they are not listed.
 func convertEntitySetToMetadataSet(names []string, entities EntitySet) (map[string]common.ObjectMetadata, error) {
 	result := map[string]common.ObjectMetadata{}
 
@@ -117,16 +120,42 @@ func convertEntitySetToMetadataSet(names []string, entities EntitySet) (map[stri
 
 		properties := entity.GetAllProperties()
 		fieldsMap := make(map[string]string)
+		fields := make(map[string]common.FieldMetadata)
 
 		for _, p := range properties {
-			fieldsMap[p] = p
+			fieldsMap[p.Name] = p.Name
+			fields[p.Name] = common.FieldMetadata{
+				ValueType: convertPropertyType(p.Type),
+			}
 		}
 
 		result[name] = common.ObjectMetadata{
 			DisplayName: name,
 			FieldsMap:   fieldsMap,
+			Fields:      fields,
 		}
 	}
 
 	return result, nil
 }
+
+// convertPropertyType maps OData primitive type to the common value type.
+// See https://learn.microsoft.com/en-us/power-apps/developer/data-platform/webapi/web-api-types-operations
+func convertPropertyType(propertyType string) common.ValueType {
+	switch propertyType {
+	case "Edm.String", "Edm.Guid":
+		return common.ValueTypeString
+	case "Edm.Boolean":
+		return common.ValueTypeBoolean
+	case "Edm.Int16", "Edm.Int32", "Edm.Int64":
+		return common.ValueTypeInt
+	case "Edm.Decimal", "Edm.Double", "Edm.Single":
+		return common.ValueTypeFloat
+	case "Edm.Date":
+		return common.ValueTypeDate
+	case "Edm.DateTimeOffset":
+		return common.ValueTypeDateTime
+	default:
+		return common.ValueTypeOther
+	}
+}
@@ -110,6 +110,17 @@ func TestListObjectMetadata(t *testing.T) { // nolint:funlen,gocognit,cyclop
 							"utcconversiontimezonecode": "utcconversiontimezonecode",
 							"versionnumber":             "versionnumber",
 						},
+						Fields: map[string]common.FieldMetadata{
+							"accountid":                 {ValueType: common.ValueTypeString},
+							"accountleadid":             {ValueType: common.ValueTypeString},
+							"importsequencenumber":      {ValueType: common.ValueTypeInt},
+							"leadid":                    {ValueType: common.ValueTypeString},
+							"name":                      {ValueType: common.ValueTypeString},
+							"overriddencreatedon":       {ValueType: common.ValueTypeDateTime},
+							"timezoneruleversionnumber": {ValueType: common.ValueTypeInt},
+							"utcconversiontimezonecode": {ValueType: common.ValueTypeInt},
+							"versionnumber":             {ValueType: common.ValueTypeInt},
+						},
 					},
 					"adx_invitation_invitecontacts": {
 						DisplayName: "adx_invitation_invitecontacts",
@@ -119,6 +130,12 @@ func TestListObjectMetadata(t *testing.T) { // nolint:funlen,gocognit,cyclop
 							"contactid":                       "contactid",
 							"versionnumber":                   "versionnumber",
 						},
+						Fields: map[string]common.FieldMetadata{
+							"adx_invitation_invitecontactsid": {ValueType: common.ValueTypeString},
+							"adx_invitationid":                {ValueType: common.ValueTypeString},
+							"contactid":                       {ValueType: common.ValueTypeString},
+							"versionnumber":                   {ValueType: common.ValueTypeInt},
+						},
 					},
 				},
 				Errors: nil,
@@ -30,7 +30,7 @@ func (s EntitySet) GetOrCreate(name, parentName string) *Entity {
 	if _, ok := s[name]; !ok {
 		s[name] = &Entity{
 			Name:       name,
-			properties: make([]string, 0),
+			properties: make([]Property, 0),
 			parentName: parentName,
 		}
 	}
@@ -42,12 +42,18 @@ func (s EntitySet) GetOrCreate(name, parentName string) *Entity {
 // fields are inherited from parents so there is a tree hierarchy that can be traversed.
 type Entity struct {
 	Name       string
-	properties []string
+	properties []Property
 	parentName string
 	parent     *Entity
 }
 
-func (e *Entity) AddProperty(property string) {
+// Property is a field of an entity, Type is an OData primitive type, ex: Edm.String.
+type Property struct {
+	Name string
+	Type string
+}
+
+func (e *Entity) AddProperty(property Property) {
 	e.properties = append(e.properties, property)
 }
 
@@ -80,7 +86,7 @@ func (s EntitySet) MatchParentsWithChildren(schemaAlias string) error {
 }
 
 // GetAllProperties recursive function that includes inherited fields from parents.
-func (e *Entity) GetAllProperties() []string {
+func (e *Entity) GetAllProperties() []Property {
 	if e.parent == nil {
 		// this is root
 		return e.properties
@@ -8,6 +8,7 @@ import (
 	"github.com/amp-labs/connectors/common"
 	"github.com/amp-labs/connectors/common/paramsbuilder"
 	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 const (
@@ -23,6 +24,7 @@ type parameters struct {
 	paramsbuilder.Client
 	paramsbuilder.Workspace
 	paramsbuilder.Module
+	paramsbuilder.Interceptors
 }
 
 func (p parameters) FromOptions(opts ...Option) (*parameters, error) {
@@ -39,6 +41,7 @@ func (p parameters) ValidateParams() error {
 		p.Client.ValidateParams(),
 		p.Workspace.ValidateParams(),
 		p.Module.ValidateParams(),
+		p.Interceptors.ValidateParams(),
 	)
 }
 
@@ -50,6 +53,17 @@ func WithClient(ctx context.Context, client *http.Client,
 	}
 }
 
+// WithClientCredentialsClient authenticates as the application user, using client credentials grant.
+// Token URL is "https://login.microsoftonline.com/<tenant>/oauth2/v2.0/token",
+// scope is the environment URL with ".default" suffix, ex: "https://org.crm.dynamics.com/.default".
+func WithClientCredentialsClient(ctx context.Context, client *http.Client,
+	config *clientcredentials.Config, opts ...common.OAuthOption,
+) Option {
+	return func(params *parameters) {
+		params.WithClientCredentialsClient(ctx, client, config, opts...)
+	}
+}
+
 func WithAuthenticatedClient(client common.AuthenticatedHTTPClient) Option {
 	return func(params *parameters) {
 		params.WithAuthenticatedClient(client)
@@ -67,3 +81,15 @@ func WithModule(module paramsbuilder.APIModule) Option {
 		params.WithModule(module)
 	}
 }
+
+func WithInterceptors(interceptors ...common.Interceptor) Option {
+	return func(params *parameters) {
+		params.WithInterceptors(interceptors...)
+	}
+}
+
+func WithCircuitBreaker(breaker *common.CircuitBreaker) Option {
+	return func(params *parameters) {
+		params.WithCircuitBreaker(breaker)
+	}
+}
@@ -6,7 +6,18 @@ import (
 	"github.com/spyzhov/ajson"
 )
 
+// getTotalSize returns @odata.count, which is reported when $count is requested.
+// Microsoft caps the count at 5000, when it is missing the size of the page is reported.
 func getTotalSize(node *ajson.Node) (int64, error) {
+	count, err := jsonquery.New(node).Integer("@odata.count", true)
+	if err != nil {
+		return 0, err
+	}
+
+	if count != nil {
+		return *count, nil
+	}
+
 	return jsonquery.New(node).ArraySize("value")
 }
 
@@ -23,15 +34,17 @@ func getNextRecordsURL(node *ajson.Node) (string, error) {
 	return jsonquery.New(node).StrWithDefault("@odata.nextLink", "")
 }
 
-func getMarshaledData(records []map[string]interface{}, fields []string) ([]common.ReadResultRow, error) {
-	data := make([]common.ReadResultRow, len(records))
+func getMarshaledData(extractFields common.FieldsExtractor) common.MarshalFunc {
+	return func(records []map[string]interface{}, fields []string) ([]common.ReadResultRow, error) {
+		data := make([]common.ReadResultRow, len(records))
 
-	for i, record := range records {
-		data[i] = common.ReadResultRow{
-			Fields: common.ExtractLowercaseFieldsFromRaw(fields, record),
-			Raw:    record,
+		for i, record := range records {
+			data[i] = common.ReadResultRow{
+				Fields: extractFields(fields, record),
+				Raw:    record,
+			}
 		}
-	}
 
-	return data, nil
+		return data, nil
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/amp-labs/connectors/common"
 	"github.com/amp-labs/connectors/common/urlbuilder"
@@ -13,6 +14,13 @@ import (
 // Microsoft API supports other capabilities like filtering, grouping, and sorting which we can potentially tap into later.
 // See https://learn.microsoft.com/en-us/power-apps/developer/data-platform/webapi/query-data-web-api#odata-query-options
 func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common.ReadResult, error) {
+	fields, err := c.metadata.ReadFields(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Fields = fields
+
 	link, err := c.buildReadURL(config)
 	if err != nil {
 		return nil, err
@@ -33,7 +41,7 @@ func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common
 		getTotalSize,
 		getRecords,
 		getNextRecordsURL,
-		getMarshaledData,
+		getMarshaledData(common.FieldsExtractorFor(config)),
 		config.Fields,
 	)
 }
@@ -54,6 +62,13 @@ func (c *Connector) buildReadURL(config common.ReadParams) (*urlbuilder.URL, err
 		link.WithQueryParam("$select", strings.Join(config.Fields, ","))
 	}
 
+	if !config.Since.IsZero() {
+		link.WithQueryParam("$filter", "modifiedon ge "+config.Since.UTC().Format(time.RFC3339))
+	}
+
+	// Total is reported only when requested, next page links keep the option.
+	link.WithQueryParam("$count", "true")
+
 	return link, nil
 }
 
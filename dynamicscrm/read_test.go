@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/amp-labs/connectors/common"
 	"github.com/amp-labs/connectors/common/interpreter"
@@ -89,6 +90,53 @@ func TestRead(t *testing.T) { //nolint:funlen,gocognit,cyclop
 			},
 			expectedErrs: nil,
 		},
+		{
+			name: "Since is sent as modifiedon filter in UTC",
+			input: common.ReadParams{
+				Since: time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+			},
+			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("$filter") != "modifiedon ge 2024-05-01T10:00:00Z" {
+					w.WriteHeader(http.StatusTeapot)
+
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				mockutils.WriteBody(w, `{
+					"value": []
+				}`)
+			})),
+			expected: &common.ReadResult{
+				Data: []common.ReadResultRow{},
+				Done: true,
+			},
+			expectedErrs: nil,
+		},
+		{
+			name: "Total count is requested and reported as Rows",
+			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("$count") != "true" {
+					w.WriteHeader(http.StatusTeapot)
+
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				mockutils.WriteBody(w, `{
+					"@odata.count": 7,
+					"value": []
+				}`)
+			})),
+			expected: &common.ReadResult{
+				Rows: 7,
+				Data: []common.ReadResultRow{},
+				Done: true,
+			},
+			expectedErrs: nil,
+		},
 		{
 			name: "Successful read with 2 entries",
 			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -9,11 +9,20 @@ import (
 
 // Write data will be used to Create or Update entity.
 // Return: common.WriteResult, where only the Success flag will be set.
+// Create with WriteParams.IdempotencyKey is refused, since created record id is unknown it cannot be remembered.
 func (c *Connector) Write(ctx context.Context, config common.WriteParams) (*common.WriteResult, error) {
 	if len(config.ObjectName) == 0 {
 		return nil, common.ErrMissingObjects
 	}
 
+	if len(config.IdempotencyKey) != 0 && len(config.RecordId) == 0 {
+		return nil, common.ErrIdempotencyKeyNotSupported
+	}
+
+	if err := c.metadata.ValidateWrite(ctx, config); err != nil {
+		return nil, err
+	}
+
 	var resource string
 
 	var write common.WriteMethod
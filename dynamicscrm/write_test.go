@@ -33,6 +33,14 @@ func TestWrite(t *testing.T) { // nolint:funlen,cyclop
 			})),
 			expectedErrs: []error{common.ErrMissingObjects},
 		},
+		{
+			name:  "Create with idempotency key is refused",
+			input: common.WriteParams{ObjectName: "fax", IdempotencyKey: "key-1"},
+			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTeapot)
+			})),
+			expectedErrs: []error{common.ErrIdempotencyKeyNotSupported},
+		},
 		{
 			name:  "Mime response header expected",
 			input: common.WriteParams{ObjectName: "fax"},
@@ -0,0 +1,45 @@
+package gong
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/test/conformance"
+	"github.com/amp-labs/connectors/test/utils/mockutils"
+)
+
+// Write and Since are not checked, connector supports neither.
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	responseCallsFirstPage := mockutils.DataFromFile(t, "read_cursor.json")
+	responseCallsLastPage := mockutils.DataFromFile(t, "read_last_page.json")
+
+	conformance.Run(t, conformance.Suite{
+		NewConnector: func(serverURL string) (any, error) {
+			connector, err := NewConnector(
+				WithAuthenticatedClient(http.DefaultClient),
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			// for testing we want to redirect calls to our mock server
+			connector.setBaseURL(serverURL)
+
+			return connector, nil
+		},
+		Read: &conformance.ReadSpec{
+			Params: common.ReadParams{
+				ObjectName: "calls",
+				Fields:     []string{"id", "workspaceId", "customData"},
+			},
+			Pages: []conformance.Response{
+				conformance.JSON(responseCallsFirstPage),
+				conformance.JSON(responseCallsLastPage),
+			},
+		},
+		Errors: conformance.StandardErrorCases([]byte(`{"requestId": "4al018gzaztcr8nbukw", "errors": ["Unauthorized"]}`)),
+	})
+}
@@ -12,6 +12,11 @@ const ApiVersion = "v2"
 type Connector struct {
 	BaseURL string
 	Client  *common.JSONHTTPClient
+
+	// metadata is used to expand wildcard fields.
+	metadata *common.MetadataCache
+	// mediaTransport fetches call recordings, nil means http.DefaultTransport.
+	mediaTransport http.RoundTripper
 }
 
 func WithCatalogSubstitutions(substitutions map[string]string) Option {
@@ -47,10 +52,13 @@ func NewConnector(opts ...Option) (conn *Connector, outErr error) {
 	}
 
 	conn = &Connector{
-		Client:  params.client,
-		BaseURL: providerInfo.BaseURL,
+		Client:         params.client,
+		BaseURL:        providerInfo.BaseURL,
+		mediaTransport: params.transport,
 	}
 
+	conn.metadata = common.NewMetadataCache(conn.ListObjectMetadata)
+
 	conn.setBaseURL(providerInfo.BaseURL)
 	conn.Client.HTTPClient.ErrorHandler = conn.interpretError
 
@@ -0,0 +1,102 @@
+package gong
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/common/jsonquery"
+)
+
+// mediaDownloadTimeout limits the whole transfer of a recording, including reading the body.
+const mediaDownloadTimeout = time.Hour
+
+var (
+	ErrCallNotFound     = errors.New("call not found")
+	ErrMediaUnavailable = errors.New("call media is not available")
+)
+
+// CallMedia selects the recording of a call.
+type CallMedia string
+
+const (
+	CallMediaAudio CallMedia = "audio"
+	CallMediaVideo CallMedia = "video"
+)
+
+// DownloadCallMedia streams the audio or video recording of a call. The caller must close the body.
+// Gong returns media as a temporary pre-signed link, which is fetched without credentials.
+func (c *Connector) DownloadCallMedia(ctx context.Context, callID string, media CallMedia) (*common.Download, error) {
+	mediaURL, err := c.getCallMediaURL(ctx, callID, media)
+	if err != nil {
+		return nil, err
+	}
+
+	// Authorization header must not be sent to the storage holding recordings,
+	// the link is fetched using the transport the connector was given, without credentials.
+	client := &common.HTTPClient{
+		Client: &http.Client{
+			Transport: c.mediaTransport,
+			Timeout:   mediaDownloadTimeout,
+		},
+		ErrorHandler: c.interpretError,
+		Interceptors: c.Client.HTTPClient.Interceptors,
+	}
+
+	download, err := client.Download(ctx, mediaURL)
+	if err != nil {
+		return nil, c.HandleError(err)
+	}
+
+	return download, nil
+}
+
+func (c *Connector) getCallMediaURL(ctx context.Context, callID string, media CallMedia) (string, error) {
+	location, err := url.JoinPath(c.BaseURL, ApiVersion, "calls/extensive")
+	if err != nil {
+		return "", err
+	}
+
+	// Listing calls is a read, even though it is a POST.
+	res, err := c.Client.Post(common.WithoutDryRun(ctx), location, map[string]any{
+		"filter": map[string]any{
+			"callIds": []string{callID},
+		},
+		"contentSelector": map[string]any{
+			"exposedFields": map[string]any{
+				"media": true,
+			},
+		},
+	})
+	if err != nil {
+		return "", c.HandleError(err)
+	}
+
+	if res == nil || res.Body == nil {
+		return "", fmt.Errorf("%w: %s", ErrCallNotFound, callID)
+	}
+
+	calls, err := jsonquery.New(res.Body).Array("calls", true)
+	if err != nil {
+		return "", err
+	}
+
+	if len(calls) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrCallNotFound, callID)
+	}
+
+	mediaURL, err := jsonquery.New(calls[0], "media").StrWithDefault(string(media)+"Url", "")
+	if err != nil {
+		return "", err
+	}
+
+	if len(mediaURL) == 0 {
+		return "", fmt.Errorf("%w: %s of call %s", ErrMediaUnavailable, media, callID)
+	}
+
+	return mediaURL, nil
+}
@@ -0,0 +1,68 @@
+package gong
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+type countingTransport struct {
+	requests atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestDownloadCallMedia(t *testing.T) {
+	t.Parallel()
+
+	var mediaAuthorization atomic.Value
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/recordings/7782342274025937895.mp3" {
+			mediaAuthorization.Store(r.Header.Get("Authorization"))
+			w.Header().Set("Content-Type", "audio/mpeg")
+			_, _ = w.Write([]byte("ID3"))
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		writeBody(w, `{"calls": [{"metaData": {"id": "7782342274025937895"},
+			"media": {"audioUrl": "`+server.URL+`/recordings/7782342274025937895.mp3"}}]}`)
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+
+	connector, err := NewConnector(
+		WithClient(context.Background(), &http.Client{Transport: transport}, &oauth2.Config{},
+			&oauth2.Token{AccessToken: "secret", TokenType: "Bearer"}),
+	)
+	require.NoError(t, err)
+
+	connector.setBaseURL(server.URL)
+
+	download, err := connector.DownloadCallMedia(context.Background(), "7782342274025937895", CallMediaAudio)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(download.Body)
+	require.NoError(t, err)
+	require.NoError(t, download.Body.Close())
+
+	require.Equal(t, "ID3", string(data))
+	require.Equal(t, "audio/mpeg", download.ContentType)
+	// Link is fetched without credentials, using the transport given to the connector.
+	require.Equal(t, "", mediaAuthorization.Load())
+	require.Equal(t, int32(2), transport.requests.Load())
+}
@@ -8,6 +8,7 @@ var (
 	ErrMissingClient = errors.New("JSON http client not set")
 	ErrNotArray      = errors.New("results data is not an array")
 	ErrNotObject     = errors.New("record is not an object")
+	ErrUnknownObject = errors.New("object is not described")
 )
 
 func (c *Connector) HandleError(err error) error {
@@ -0,0 +1,58 @@
+package gong
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amp-labs/connectors/common"
+)
+
+// objectFields lists fields returned by Gong for the objects that can be read.
+// Gong has no describe endpoint, names follow https://gong.app.gong.io/settings/api/documentation.
+var objectFields = map[string][]string{ // nolint:gochecknoglobals
+	"calls": {
+		"id", "url", "title", "scheduled", "started", "duration", "primaryUserId", "direction",
+		"system", "scope", "media", "language", "workspaceId", "sdrDisposition", "clientUniqueId",
+		"customData", "purpose", "meetingUrl", "isPrivate", "calendarEventId",
+	},
+	"users": {
+		"id", "emailAddress", "created", "active", "emailAliases", "trustedEmailAddress",
+		"firstName", "lastName", "title", "phoneNumber", "extension", "personalMeetingUrls",
+		"settings", "managerId", "meetingConsentPageUrl", "spokenLanguages",
+	},
+}
+
+// ListObjectMetadata describes objects from the static list of Gong fields.
+func (c *Connector) ListObjectMetadata(
+	ctx context.Context, objectNames []string,
+) (*common.ListObjectMetadataResult, error) {
+	if len(objectNames) == 0 {
+		return nil, common.ErrMissingObjects
+	}
+
+	result := &common.ListObjectMetadataResult{
+		Result: make(map[string]common.ObjectMetadata, len(objectNames)),
+		Errors: make(map[string]error),
+	}
+
+	for _, objectName := range objectNames {
+		fields, ok := objectFields[objectName]
+		if !ok {
+			result.Errors[objectName] = fmt.Errorf("%w: %s", ErrUnknownObject, objectName)
+
+			continue
+		}
+
+		fieldsMap := make(map[string]string, len(fields))
+		for _, field := range fields {
+			fieldsMap[field] = field
+		}
+
+		result.Result[objectName] = common.ObjectMetadata{
+			DisplayName: objectName,
+			FieldsMap:   fieldsMap,
+		}
+	}
+
+	return result, nil
+}
@@ -14,7 +14,10 @@ type gongParams struct {
 	client *common.JSONHTTPClient
 	paramsbuilder.Workspace
 	paramsbuilder.APIModule
+	paramsbuilder.Interceptors
 	substitutions map[string]string
+	// transport is the unauthenticated transport of WithClient, it is used to fetch pre-signed links.
+	transport http.RoundTripper
 }
 
 type Option func(params *gongParams)
@@ -34,18 +37,25 @@ func (params *gongParams) ValidateParams() error {
 }
 
 func WithClient(ctx context.Context, client *http.Client, config *oauth2.Config, token *oauth2.Token,
+	opts ...common.OAuthOption,
 ) Option {
 	return func(params *gongParams) {
-		oauthclient, err := common.NewOAuthHTTPClient(
-			ctx, common.WithOAuthClient(client),
+		options := []common.OAuthOption{
+			common.WithOAuthClient(client),
 			common.WithOAuthConfig(config),
 			common.WithOAuthToken(token),
-		)
+		}
+
+		oauthclient, err := common.NewOAuthHTTPClient(ctx, append(options, opts...)...)
 		if err != nil {
 			panic(err)
 		}
 
 		WithAuthenticatedClient(oauthclient)(params)
+
+		if client != nil {
+			params.transport = client.Transport
+		}
 	}
 }
 
@@ -71,5 +81,19 @@ func (params *gongParams) prepare() (*gongParams, error) {
 		return nil, ErrMissingClient
 	}
 
+	params.ApplyInterceptors(params.client.HTTPClient)
+
 	return params, nil
 }
+
+func WithInterceptors(interceptors ...common.Interceptor) Option {
+	return func(params *gongParams) {
+		params.WithInterceptors(interceptors...)
+	}
+}
+
+func WithCircuitBreaker(breaker *common.CircuitBreaker) Option {
+	return func(params *gongParams) {
+		params.WithCircuitBreaker(breaker)
+	}
+}
@@ -63,14 +63,14 @@ func getRecords(node *ajson.Node, objectName string) ([]map[string]interface{},
 	return out, nil
 }
 
-// getTotalSize returns the total number of records that match the query.
+// getTotalSize returns the total number of records that match the query, across all pages.
 func getTotalSize(node *ajson.Node) (int64, error) {
 	recordsNode, err := node.GetKey("records")
 	if err != nil {
 		return 0, common.ErrParseError
 	}
 
-	totalRecordsNode, err := recordsNode.GetKey("currentPageSize")
+	totalRecordsNode, err := recordsNode.GetKey("totalRecords")
 	if err != nil {
 		return 0, common.ErrParseError
 	}
@@ -84,15 +84,17 @@ func getTotalSize(node *ajson.Node) (int64, error) {
 }
 
 // getMarshaledData accepts a list of records and returns a list of structured data ([]ReadResultRow).
-func getMarshaledData(records []map[string]interface{}, fields []string) ([]common.ReadResultRow, error) {
-	data := make([]common.ReadResultRow, len(records))
-
-	for i, record := range records {
-		data[i] = common.ReadResultRow{
-			Fields: common.ExtractLowercaseFieldsFromRaw(fields, record),
-			Raw:    record,
+func getMarshaledData(extractFields common.FieldsExtractor) common.MarshalFunc {
+	return func(records []map[string]interface{}, fields []string) ([]common.ReadResultRow, error) {
+		data := make([]common.ReadResultRow, len(records))
+
+		for i, record := range records {
+			data[i] = common.ReadResultRow{
+				Fields: extractFields(fields, record),
+				Raw:    record,
+			}
 		}
-	}
 
-	return data, nil
+		return data, nil
+	}
 }
@@ -9,11 +9,12 @@ import (
 )
 
 func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common.ReadResult, error) {
-	var (
-		res *common.JSONHTTPResponse
+	var res *common.JSONHTTPResponse
 
-		fields []string
-	)
+	fields, err := c.metadata.ReadFields(ctx, config)
+	if err != nil {
+		return nil, err
+	}
 
 	fullURL, err := url.JoinPath(c.BaseURL, ApiVersion, config.ObjectName)
 	if err != nil {
@@ -24,10 +25,6 @@ func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common
 		fullURL = fullURL + "?cursor=" + config.NextPage.String()
 	}
 
-	if config.Fields != nil {
-		fields = config.Fields
-	}
-
 	res, err = c.get(ctx, fullURL)
 	if err != nil {
 		return nil, err
@@ -41,7 +38,7 @@ func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common
 			return getNextRecordsURL(node)
 		},
 
-		getMarshaledData,
+		getMarshaledData(common.FieldsExtractorFor(config)),
 		fields,
 	)
 }
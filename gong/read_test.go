@@ -62,7 +62,7 @@ func TestRead(t *testing.T) { //nolint:funlen,gocognit,cyclop
 		},
 
 		{
-			name:  "currentPageSize parameter is missing in the payload",
+			name:  "totalRecords parameter is missing in the payload",
 			input: common.ReadParams{ObjectName: "calls"},
 			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				w.Header().Set("Content-Type", "application/json")
@@ -70,7 +70,7 @@ func TestRead(t *testing.T) { //nolint:funlen,gocognit,cyclop
 				writeBody(w, `{
 					"requestId": "7eey0z6mf3elkp1n5b6",
 					"records": {
-						"totalRecords": 11,
+						"currentPageSize": 11,
 						"currentPageNumber": 0
 					},
 					"calls": [     
@@ -125,7 +125,7 @@ func TestRead(t *testing.T) { //nolint:funlen,gocognit,cyclop
 				_, _ = w.Write(fakeServerResp2)
 			})),
 			expected: &common.ReadResult{
-				Rows: 2,
+				Rows: 4,
 				Data: []common.ReadResultRow{{
 					Fields: map[string]any{},
 					Raw: map[string]any{
@@ -151,6 +151,52 @@ func TestRead(t *testing.T) { //nolint:funlen,gocognit,cyclop
 			expectedErrs: nil,
 		},
 
+		{
+			name:  "Wildcard is expanded into described fields",
+			input: common.ReadParams{ObjectName: "calls", Fields: []string{"*"}},
+			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(fakeServerResp)
+			})),
+			expected: &common.ReadResult{
+				Rows: 2,
+				Data: []common.ReadResultRow{{
+					Fields: map[string]any{
+						"id":             "52947912500572621",
+						"clientuniqueid": "ce93bb26-de69-41e3-8a7f-43ea3714b9e8",
+						"customdata":     "R1201",
+						"url":            "https://us-49467.app.gong.io/call?id=52947912500572621",
+						"workspaceid":    "1007648505208900737",
+					},
+					Raw: map[string]any{
+						"id":             "52947912500572621",
+						"clientUniqueId": "ce93bb26-de69-41e3-8a7f-43ea3714b9e8",
+						"customData":     "R1201",
+						"url":            "https://us-49467.app.gong.io/call?id=52947912500572621",
+						"workspaceId":    "1007648505208900737",
+					},
+				}, {
+					Fields: map[string]any{
+						"id":             "137982752092261989",
+						"clientuniqueid": "f77501df-0c70-4c38-b565-a3a09fee14fb",
+						"customdata":     "R1201",
+						"url":            "https://us-49467.app.gong.io/call?id=137982752092261989",
+						"workspaceid":    "1007648505208900737",
+					},
+					Raw: map[string]any{
+						"id":             "137982752092261989",
+						"clientUniqueId": "f77501df-0c70-4c38-b565-a3a09fee14fb",
+						"customData":     "R1201",
+						"url":            "https://us-49467.app.gong.io/call?id=137982752092261989",
+						"workspaceId":    "1007648505208900737",
+					},
+				}},
+				Done: true,
+			},
+			expectedErrs: nil,
+		},
+
 		{
 			name:  "Incorrect data type in payload",
 			input: common.ReadParams{ObjectName: "calls"},
@@ -0,0 +1,68 @@
+package hubspot
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/test/conformance"
+	"github.com/amp-labs/connectors/test/utils/mockutils"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	responseContactsFirstPage := mockutils.DataFromFile(t, "read-contacts-first-page.json")
+	responseContactsLastPage := mockutils.DataFromFile(t, "read-contacts-last-page.json")
+	responseCreateContact := mockutils.DataFromFile(t, "write-create-contact.json")
+
+	conformance.Run(t, conformance.Suite{
+		NewConnector: func(serverURL string) (any, error) {
+			connector, err := NewConnector(
+				WithAuthenticatedClient(http.DefaultClient),
+				WithModule(ModuleCRM),
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			// for testing we want to redirect calls to our mock server
+			connector.BaseURL = serverURL
+			connector.Client.HTTPClient.Base = serverURL
+
+			return connector, nil
+		},
+		Read: &conformance.ReadSpec{
+			// Only search reports the total, so pages are read with Since.
+			Params: common.ReadParams{
+				ObjectName: "contacts",
+				Fields:     []string{"email", "FirstName"},
+				Since:      time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			},
+			Pages: []conformance.Response{
+				conformance.JSON(responseContactsFirstPage),
+				conformance.JSON(responseContactsLastPage),
+			},
+			Since: &conformance.SinceSpec{
+				Timestamp: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+				IsFiltered: func(req *http.Request, since time.Time) bool {
+					body, err := io.ReadAll(req.Body)
+
+					return err == nil && strings.HasSuffix(req.URL.Path, "/search") &&
+						strings.Contains(string(body), `"value":"2024-05-01T10:00:00Z"`)
+				},
+			},
+		},
+		Write: &conformance.WriteSpec{
+			Params: common.WriteParams{
+				ObjectName: "contacts",
+				RecordData: map[string]any{"email": "new@hubspot.com"},
+			},
+			Response: conformance.JSON(responseCreateContact),
+		},
+		Errors: conformance.StandardErrorCases([]byte(`{"status": "error", "message": "Authentication failed"}`)),
+	})
+}
@@ -10,6 +10,11 @@ type Connector struct {
 	Module  string
 	BaseURL string
 	Client  *common.JSONHTTPClient
+
+	// metadata is used to expand wildcard fields and to validate writes.
+	metadata *common.MetadataCache
+	// idempotency remembers records created with an idempotency key.
+	idempotency common.IdempotencyStore
 }
 
 // NewConnector returns a new Hubspot connector.
@@ -49,9 +54,12 @@ func NewConnector(opts ...Option) (conn *Connector, outErr error) {
 		BaseURL: params.client.HTTPClient.Base,
 		Module:  params.module,
 		Client:  params.client,
+
+		idempotency: params.IdempotencyStore(),
 	}
 
 	conn.Client.HTTPClient.ErrorHandler = conn.interpretError
+	conn.metadata = common.NewMetadataCache(conn.ListObjectMetadata)
 
 	return conn, nil
 }
@@ -16,6 +16,7 @@ var (
 	ErrNotArray         = errors.New("results is not an array")
 	ErrNotObject        = errors.New("result is not an object")
 	ErrNotString        = errors.New("link is not a string")
+	ErrNotNumeric       = errors.New("total is not a number")
 )
 
 type HubspotError struct {
@@ -80,8 +80,21 @@ type describeObjectResponse struct {
 }
 
 type describeObjectResult struct {
-	Name  string `json:"name"`
-	Label string `json:"label"`
+	Name                 string               `json:"name"`
+	Label                string               `json:"label"`
+	Type                 string               `json:"type"`
+	FieldType            string               `json:"fieldType"`
+	Options              []propertyOption     `json:"options"`
+	ModificationMetadata modificationMetadata `json:"modificationMetadata"`
+}
+
+type propertyOption struct {
+	Value  string `json:"value"`
+	Hidden bool   `json:"hidden"`
+}
+
+type modificationMetadata struct {
+	ReadOnlyValue bool `json:"readOnlyValue"`
 }
 
 // describeObject returns object metadata for the given object name.
@@ -108,6 +121,7 @@ func (c *Connector) describeObject(ctx context.Context, objectName string) (*com
 	return &common.ObjectMetadata{
 		DisplayName: objectName,
 		FieldsMap:   makeFieldsMap(resp),
+		Fields:      makeFieldsMetadata(resp),
 	}, nil
 }
 
@@ -124,3 +138,58 @@ func makeFieldsMap(data *describeObjectResponse) map[string]string {
 
 	return fieldsMap
 }
+
+// makeFieldsMetadata describes property types, read-only flags and enumeration options.
+// HubSpot doesn't report which properties are required.
+func makeFieldsMetadata(data *describeObjectResponse) map[string]common.FieldMetadata {
+	fieldsMetadata := make(map[string]common.FieldMetadata)
+
+	for _, field := range data.Results {
+		values := make([]string, 0, len(field.Options))
+
+		for _, option := range field.Options {
+			if !option.Hidden {
+				values = append(values, option.Value)
+			}
+		}
+
+		if len(values) == 0 {
+			values = nil
+		}
+
+		fieldsMetadata[strings.ToLower(field.Name)] = common.FieldMetadata{
+			Name:      field.Name,
+			ValueType: convertPropertyType(field.Type, field.FieldType),
+			ReadOnly:  field.ModificationMetadata.ReadOnlyValue,
+			Values:    values,
+		}
+	}
+
+	return fieldsMetadata
+}
+
+// convertPropertyType maps HubSpot property type to the common value type.
+// Enumeration is multi select only when it is edited with checkboxes.
+// See https://developers.hubspot.com/docs/api/crm/properties
+func convertPropertyType(propertyType, fieldType string) common.ValueType {
+	switch propertyType {
+	case "string", "phone_number":
+		return common.ValueTypeString
+	case "bool":
+		return common.ValueTypeBoolean
+	case "number":
+		return common.ValueTypeFloat
+	case "date":
+		return common.ValueTypeDate
+	case "datetime":
+		return common.ValueTypeDateTime
+	case "enumeration":
+		if fieldType == "checkbox" {
+			return common.ValueTypeMultiSelect
+		}
+
+		return common.ValueTypeSingleSelect
+	default:
+		return common.ValueTypeOther
+	}
+}
@@ -0,0 +1,68 @@
+package hubspot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/test/utils/mockutils"
+)
+
+func TestListObjectMetadataFields(t *testing.T) {
+	t.Parallel()
+
+	responseProperties := mockutils.DataFromFile(t, "metadata-properties-contacts.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/properties/contacts") {
+			w.WriteHeader(http.StatusTeapot)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(responseProperties)
+	}))
+	defer server.Close()
+
+	connector, err := NewConnector(
+		WithAuthenticatedClient(http.DefaultClient),
+		WithModule(ModuleCRM),
+	)
+	if err != nil {
+		t.Fatalf("error in test while constructing connector %v", err)
+	}
+
+	// for testing we want to redirect calls to our mock server
+	connector.BaseURL = server.URL
+	connector.Client.HTTPClient.Base = server.URL
+
+	result, err := connector.ListObjectMetadata(context.Background(), []string{"contacts"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]common.FieldMetadata{
+		"email": {Name: "email", ValueType: common.ValueTypeString},
+		"hs_lead_status": {
+			Name:      "hs_lead_status",
+			ValueType: common.ValueTypeSingleSelect,
+			Values:    []string{"NEW", "OPEN"},
+		},
+		"hs_buying_role": {
+			Name:      "hs_buying_role",
+			ValueType: common.ValueTypeMultiSelect,
+			Values:    []string{"CHAMPION", "END_USER"},
+		},
+		"num_notes":        {Name: "num_notes", ValueType: common.ValueTypeFloat, ReadOnly: true},
+		"lastmodifieddate": {Name: "lastmodifieddate", ValueType: common.ValueTypeDateTime, ReadOnly: true},
+	}
+
+	if fields := result.Result["contacts"].Fields; !reflect.DeepEqual(fields, expected) {
+		t.Fatalf("unexpected fields metadata: %v", fields)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/common/paramsbuilder"
 	"golang.org/x/oauth2"
 )
 
@@ -57,8 +58,17 @@ func WithModule(module APIModule) Option {
 	}
 }
 
+// WithIdempotencyStore sets the store of paramsbuilder.Idempotency. Its usage is optional.
+func WithIdempotencyStore(store common.IdempotencyStore) Option {
+	return func(params *hubspotParams) {
+		params.WithIdempotencyStore(store)
+	}
+}
+
 // hubspotParams is the internal configuration for the hubspot connector.
 type hubspotParams struct {
+	paramsbuilder.Interceptors
+	paramsbuilder.Idempotency
 	client *common.JSONHTTPClient // required
 	module string                 // required
 }
@@ -75,9 +85,25 @@ func (p *hubspotParams) prepare() (out *hubspotParams, err error) {
 		p.module = ModuleCRM.String()
 	}
 
+	p.ApplyInterceptors(p.client.HTTPClient)
+
 	return p, nil
 }
 
 func requiresFiltering(config common.ReadParams) bool {
 	return !config.Since.IsZero()
 }
+
+// WithInterceptors adds middleware of paramsbuilder.Interceptors. Its usage is optional.
+func WithInterceptors(interceptors ...common.Interceptor) Option {
+	return func(params *hubspotParams) {
+		params.WithInterceptors(interceptors...)
+	}
+}
+
+// WithCircuitBreaker sets the breaker of paramsbuilder.Interceptors. Its usage is optional.
+func WithCircuitBreaker(breaker *common.CircuitBreaker) Option {
+	return func(params *hubspotParams) {
+		params.WithCircuitBreaker(breaker)
+	}
+}
@@ -115,7 +115,21 @@ func getRecords(node *ajson.Node) ([]map[string]interface{}, error) {
 }
 
 // getTotalSize returns the total number of records that match the query.
+// Only search reports the total, list endpoint has no count, so the size of the page is reported.
 func getTotalSize(node *ajson.Node) (int64, error) {
+	if node.HasKey("total") {
+		total, err := node.GetKey("total")
+		if err != nil {
+			return 0, err
+		}
+
+		if !total.IsNumeric() {
+			return 0, ErrNotNumeric
+		}
+
+		return int64(total.MustNumeric()), nil
+	}
+
 	node, err := node.GetKey("results")
 	if err != nil {
 		return 0, err
@@ -129,22 +143,24 @@ func getTotalSize(node *ajson.Node) (int64, error) {
 }
 
 // getMarshaledData accepts a list of records and returns a list of structured data ([]ReadResultRow).
-func getMarshaledData(records []map[string]interface{}, fields []string) ([]common.ReadResultRow, error) {
-	data := make([]common.ReadResultRow, len(records))
-
-	for i, record := range records {
-		recordProperties, ok := record["properties"].(map[string]interface{})
-		if !ok {
-			return nil, ErrNotObject
+func getMarshaledData(extractFields common.FieldsExtractor) common.MarshalFunc {
+	return func(records []map[string]interface{}, fields []string) ([]common.ReadResultRow, error) {
+		data := make([]common.ReadResultRow, len(records))
+
+		for i, record := range records {
+			recordProperties, ok := record["properties"].(map[string]interface{})
+			if !ok {
+				return nil, ErrNotObject
+			}
+
+			data[i] = common.ReadResultRow{
+				Fields: extractFields(fields, recordProperties),
+				Raw:    record,
+			}
 		}
 
-		data[i] = common.ReadResultRow{
-			Fields: common.ExtractLowercaseFieldsFromRaw(fields, recordProperties),
-			Raw:    record,
-		}
+		return data, nil
 	}
-
-	return data, nil
 }
 
 // GetLastResultId returns the last row's id from a result.
@@ -14,12 +14,18 @@ import (
 // search endpoint. If Since is not set, it will use the read endpoint.
 // In case Deleted objects won’t appear in any search results.
 // Deleted objects can only be read by using this endpoint.
+// Only the Search endpoint reports the total number of records, otherwise Rows is the size of the page.
 func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common.ReadResult, error) {
 	var (
 		rsp *common.JSONHTTPResponse
 		err error
 	)
 
+	config.Fields, err = c.metadata.ReadFields(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
 	// If filtering is required, then we have to use the search endpoint.
 	// The Search endpoint has a 10K record limit. In case this limit is reached,
 	// the sorting allows the caller to continue in another call by offsetting
@@ -39,8 +45,9 @@ func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common
 			SortBy: []SortBy{
 				BuildSort(ObjectFieldHsObjectId, SortDirectionAsc),
 			},
-			NextPage: config.NextPage,
-			Fields:   config.Fields,
+			NextPage:          config.NextPage,
+			Fields:            config.Fields,
+			PreserveFieldCase: config.PreserveFieldCase,
 		}
 
 		return c.Search(ctx, searchParams)
@@ -66,7 +73,7 @@ func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common
 		getTotalSize,
 		getRecords,
 		getNextRecordsURL,
-		getMarshaledData,
+		getMarshaledData(common.FieldsExtractorFor(config)),
 		config.Fields,
 	)
 }
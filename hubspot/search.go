@@ -22,7 +22,8 @@ func (c *Connector) Search(ctx context.Context, config SearchParams) (*common.Re
 
 	relativeURL := strings.Join([]string{"objects", config.ObjectName, "search"}, "/")
 
-	rsp, err = c.Client.Post(ctx, c.getURL(relativeURL), makeFilterBody(config))
+	// Search only reads data, it must work in dry run.
+	rsp, err = c.Client.Post(common.WithoutDryRun(ctx), c.getURL(relativeURL), makeFilterBody(config))
 	if err != nil {
 		return nil, err
 	}
@@ -32,7 +33,7 @@ func (c *Connector) Search(ctx context.Context, config SearchParams) (*common.Re
 		getTotalSize,
 		getRecords,
 		getNextRecordsAfter,
-		getMarshaledData,
+		getMarshaledData(common.FieldsExtractorFor(common.ReadParams{PreserveFieldCase: config.PreserveFieldCase})),
 		config.Fields,
 	)
 }
@@ -13,6 +13,8 @@ type SearchParams struct {
 	FilterGroups []FilterGroup // optional
 	// Fields is the list of fields to return in the result.
 	Fields []string // optional
+	// PreserveFieldCase has the same meaning as common.ReadParams.PreserveFieldCase.
+	PreserveFieldCase bool // optional, defaults to false
 }
 
 type SortBy struct {
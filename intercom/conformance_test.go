@@ -0,0 +1,59 @@
+package intercom
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/test/conformance"
+	"github.com/amp-labs/connectors/test/utils/mockutils"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	responseContactsFirstPage := mockutils.DataFromFile(t, "read-contacts-1-first-page.json")
+	responseContactsSecondPage := mockutils.DataFromFile(t, "read-contacts-2-second-page.json")
+	responseContactsThirdPage := mockutils.DataFromFile(t, "read-contacts-3-last-page.json")
+	responseCreateArticle := mockutils.DataFromFile(t, "write-create-article.json")
+	responseNotFound := mockutils.DataFromFile(t, "resource-not-found.json")
+
+	conformance.Run(t, conformance.Suite{
+		NewConnector: func(serverURL string) (any, error) {
+			connector, err := NewConnector(
+				WithAuthenticatedClient(http.DefaultClient),
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			// for testing we want to redirect calls to our mock server
+			connector.setBaseURL(serverURL)
+
+			return connector, nil
+		},
+		Read: &conformance.ReadSpec{
+			Params: common.ReadParams{
+				ObjectName: "contacts",
+				Fields:     []string{"Email", "name", "Role"},
+			},
+			Pages: []conformance.Response{
+				conformance.JSON(responseContactsFirstPage),
+				conformance.JSON(responseContactsSecondPage),
+				conformance.JSON(responseContactsThirdPage),
+			},
+		},
+		Write: &conformance.WriteSpec{
+			Params: common.WriteParams{
+				ObjectName: "articles",
+				RecordData: map[string]any{"title": "Famous quotes"},
+			},
+			Response: conformance.JSON(responseCreateArticle),
+		},
+		Delete: &conformance.DeleteSpec{
+			Params:   common.DeleteParams{ObjectName: "articles", RecordId: "9333415"},
+			Response: conformance.JSON([]byte(`{"id": "9333415", "object": "article", "deleted": true}`)),
+		},
+		Errors: conformance.StandardErrorCases(responseNotFound),
+	})
+}
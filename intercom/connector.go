@@ -15,6 +15,9 @@ const apiVersion = "2.11"
 type Connector struct {
 	BaseURL string
 	Client  *common.JSONHTTPClient
+
+	// metadata is used to expand wildcard fields and to validate writes.
+	metadata *common.MetadataCache
 }
 
 func NewConnector(opts ...Option) (conn *Connector, outErr error) {
@@ -34,6 +37,7 @@ func NewConnector(opts ...Option) (conn *Connector, outErr error) {
 	}
 
 	httpClient := params.Client.Caller
+	params.ApplyInterceptors(httpClient)
 	conn = &Connector{
 		Client: &common.JSONHTTPClient{
 			HTTPClient: httpClient,
@@ -44,6 +48,7 @@ func NewConnector(opts ...Option) (conn *Connector, outErr error) {
 	conn.Client.HTTPClient.ErrorHandler = interpreter.ErrorHandler{
 		JSON: conn.interpretJSONError,
 	}.Handle
+	conn.metadata = common.NewMetadataCache(conn.ListObjectMetadata)
 
 	return conn, nil
 }
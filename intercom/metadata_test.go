@@ -10,6 +10,8 @@ import (
 	"testing"
 
 	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/common/jsonschema"
+	"github.com/amp-labs/connectors/intercom/metadata"
 	"github.com/amp-labs/connectors/tools/scrapper"
 	"github.com/go-test/deep"
 )
@@ -149,3 +151,27 @@ func TestListObjectMetadata(t *testing.T) { // nolint:funlen,gocognit,cyclop
 		})
 	}
 }
+
+func TestMetadataJSONSchemaRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	schemas, err := metadata.FileManager.LoadSchemas()
+	if err != nil {
+		t.Fatalf("failed to load static schemas: %v", err)
+	}
+
+	document := jsonschema.FromListObjectMetadata(schemas.ToListObjectMetadata())
+	if document.Schema != jsonschema.Draft {
+		t.Fatalf("unexpected JSON Schema dialect: %v", document.Schema)
+	}
+
+	list, err := jsonschema.ToListObjectMetadata(document)
+	if err != nil {
+		t.Fatalf("failed to convert JSON Schema: %v", err)
+	}
+
+	output := scrapper.NewObjectMetadataResultFromList(list)
+	if !reflect.DeepEqual(output, schemas) {
+		t.Fatalf("round trip changed schemas, diff: (%v)", deep.Equal(output, schemas))
+	}
+}
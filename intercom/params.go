@@ -21,6 +21,7 @@ type Option func(params *parameters)
 // parameters Intercom supports auth client by delegation.
 type parameters struct {
 	paramsbuilder.Client
+	paramsbuilder.Interceptors
 }
 
 func (p parameters) FromOptions(opts ...Option) (*parameters, error) {
@@ -35,6 +36,7 @@ func (p parameters) FromOptions(opts ...Option) (*parameters, error) {
 func (p parameters) ValidateParams() error {
 	return errors.Join(
 		p.Client.ValidateParams(),
+		p.Interceptors.ValidateParams(),
 	)
 }
 
@@ -51,3 +53,15 @@ func WithAuthenticatedClient(client common.AuthenticatedHTTPClient) Option {
 		params.WithAuthenticatedClient(client)
 	}
 }
+
+func WithInterceptors(interceptors ...common.Interceptor) Option {
+	return func(params *parameters) {
+		params.WithInterceptors(interceptors...)
+	}
+}
+
+func WithCircuitBreaker(breaker *common.CircuitBreaker) Option {
+	return func(params *parameters) {
+		params.WithCircuitBreaker(breaker)
+	}
+}
@@ -31,8 +31,18 @@ Note:
 
 	=> `pages.next` can be null.
 	=> Sometimes array of objects is not stored at `data` but named after `type`.
+	=> `total_count` covers every page, when it is missing the size of the page is reported.
 */
 func getTotalSize(node *ajson.Node) (int64, error) {
+	total, err := jsonquery.New(node).Integer("total_count", true)
+	if err != nil {
+		return 0, err
+	}
+
+	if total != nil {
+		return *total, nil
+	}
+
 	arrKey, err := extractListFieldName(node)
 	if err != nil {
 		return 0, err
@@ -98,17 +108,19 @@ func getNextPageStringURL(node *ajson.Node) (string, error) {
 	return *nextPage, nil
 }
 
-func getMarshaledData(records []map[string]interface{}, fields []string) ([]common.ReadResultRow, error) {
-	data := make([]common.ReadResultRow, len(records))
+func getMarshaledData(extractFields common.FieldsExtractor) common.MarshalFunc {
+	return func(records []map[string]interface{}, fields []string) ([]common.ReadResultRow, error) {
+		data := make([]common.ReadResultRow, len(records))
 
-	for i, record := range records {
-		data[i] = common.ReadResultRow{
-			Fields: common.ExtractLowercaseFieldsFromRaw(fields, record),
-			Raw:    record,
+		for i, record := range records {
+			data[i] = common.ReadResultRow{
+				Fields: extractFields(fields, record),
+				Raw:    record,
+			}
 		}
-	}
 
-	return data, nil
+		return data, nil
+	}
 }
 
 // The key that stores array in response payload will be dynamically figured out.
@@ -9,6 +9,13 @@ import (
 )
 
 func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common.ReadResult, error) {
+	fields, err := c.metadata.ReadFields(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Fields = fields
+
 	link, err := c.buildReadURL(config)
 	if err != nil {
 		return nil, err
@@ -27,7 +34,7 @@ func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common
 		getTotalSize,
 		getRecords,
 		makeNextRecordsURL(link),
-		getMarshaledData,
+		getMarshaledData(common.FieldsExtractorFor(config)),
 		config.Fields,
 	)
 }
@@ -194,6 +194,21 @@ func TestRead(t *testing.T) { //nolint:funlen,gocognit,cyclop,maintidx
 			},
 			expectedErrs: nil,
 		},
+		{
+			name: "Total count of all pages is reported as Rows",
+			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(responseNotesFirstPage)
+			})),
+			comparator: func(baseURL string, actual, expected *common.ReadResult) bool {
+				return actual.Rows == expected.Rows && len(actual.Data) == 2
+			},
+			expected: &common.ReadResult{
+				Rows: 3,
+			},
+			expectedErrs: nil,
+		},
 		{
 			name: "Successful read of named list",
 			input: common.ReadParams{
@@ -13,16 +13,29 @@ func (c *Connector) Write(ctx context.Context, config common.WriteParams) (*comm
 		return nil, common.ErrMissingObjects
 	}
 
+	if err := c.metadata.ValidateWrite(ctx, config); err != nil {
+		return nil, err
+	}
+
 	url, err := c.getURL(config.ObjectName)
 	if err != nil {
 		return nil, err
 	}
 
-	var write common.WriteMethod
+	var (
+		write   common.WriteMethod
+		headers []common.Header
+	)
+
 	if len(config.RecordId) == 0 {
 		// writing to the entity without id means
 		// that we are extending 'List' resource and creating a new record
 		write = c.Client.Post
+
+		// idempotency is supported natively, provider deduplicates retried creates
+		if len(config.IdempotencyKey) != 0 {
+			headers = append(headers, common.IdempotencyKeyHeader(config.IdempotencyKey))
+		}
 	} else {
 		// only put is supported for updating 'Single' resource
 		write = c.Client.Put
@@ -30,7 +43,7 @@ func (c *Connector) Write(ctx context.Context, config common.WriteParams) (*comm
 		url.AddPath(config.RecordId)
 	}
 
-	res, err := write(ctx, url.String(), config.RecordData)
+	res, err := write(ctx, url.String(), config.RecordData, headers...)
 	if err != nil {
 		return nil, err
 	}
@@ -72,6 +72,25 @@ func TestWrite(t *testing.T) { // nolint:funlen,cyclop
 			expected:     &common.WriteResult{Success: true},
 			expectedErrs: nil,
 		},
+		{
+			name:  "Idempotency key is sent natively on create",
+			input: common.WriteParams{ObjectName: "signals", IdempotencyKey: "d2c1a7b0"},
+			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				if r.Header.Get(common.IdempotencyKeyHeaderName) != "d2c1a7b0" {
+					w.WriteHeader(http.StatusTeapot)
+
+					return
+				}
+
+				mockutils.RespondToMethod(w, r, "POST", func() {
+					w.WriteHeader(http.StatusOK)
+				})
+			})),
+			expected:     &common.WriteResult{Success: true},
+			expectedErrs: nil,
+		},
 		{
 			name:  "Write must act as an Update",
 			input: common.WriteParams{ObjectName: "signals", RecordId: "22165"},
@@ -0,0 +1,387 @@
+package connectors
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+)
+
+var (
+	// ErrManagerClosed is returned by Manager.Get after Manager.Close was called.
+	ErrManagerClosed = errors.New("connection manager is closed")
+
+	// errStaleConnection means connector was invalidated while it was being constructed.
+	errStaleConnection = errors.New("connection was invalidated during construction")
+)
+
+// minEvictInterval limits how often idle connectors are checked when idle timeout is very short.
+const minEvictInterval = time.Second
+
+// ConnectionKey identifies connection of a tenant.
+type ConnectionKey struct {
+	Tenant       string
+	ConnectionID string
+}
+
+// ConnectorFactory constructs connector for the connection. It is called once per key,
+// until connector is evicted or invalidated. The context carries values of the request which
+// triggered construction, but not its cancellation, since other requests may wait for the same connector.
+type ConnectorFactory func(ctx context.Context, key ConnectionKey) (Connector, error)
+
+// ManagerOption configures the Manager.
+type ManagerOption func(params *managerParams)
+
+type managerParams struct {
+	idleTimeout time.Duration
+	now         func() time.Time
+}
+
+// WithIdleTimeout evicts connectors which were not requested for the given duration.
+// Eviction is checked in the background every half of the timeout, but not more often than once a second.
+// Zero disables eviction.
+func WithIdleTimeout(timeout time.Duration) ManagerOption {
+	return func(params *managerParams) {
+		params.idleTimeout = timeout
+	}
+}
+
+// WithManagerClock overrides current time, it is useful for testing idle eviction.
+func WithManagerClock(now func() time.Time) ManagerOption {
+	return func(params *managerParams) {
+		params.now = now
+	}
+}
+
+// Manager caches connectors by tenant and connection, so that providers catalog is read and
+// clients are built once per connection instead of once per request. Connectors are closed when
+// they are evicted, invalidated or when the manager is closed. Manager is safe for concurrent use.
+//
+// Connectors are meant to be requested via Get for every operation and not kept by the caller
+// for longer than the idle timeout, since evicted connectors are closed.
+type Manager struct {
+	mut     sync.Mutex
+	factory ConnectorFactory
+	params  managerParams
+	entries map[ConnectionKey]*managerEntry
+	clients map[ConnectionKey]*managerClient
+	closed  bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+type managerEntry struct {
+	// ready is closed once construction has finished, then conn and err are set.
+	ready    chan struct{}
+	conn     Connector
+	err      error
+	lastUsed time.Time
+}
+
+type managerClient struct {
+	// ready is closed once construction has finished, then client and err are set.
+	ready  chan struct{}
+	client common.AuthenticatedHTTPClient
+	err    error
+}
+
+// NewManager creates manager which constructs connectors using the factory.
+// Close the manager to stop background eviction and to close cached connectors.
+func NewManager(factory ConnectorFactory, opts ...ManagerOption) *Manager {
+	params := managerParams{
+		now: time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(&params)
+	}
+
+	manager := &Manager{
+		factory: factory,
+		params:  params,
+		entries: make(map[ConnectionKey]*managerEntry),
+		clients: make(map[ConnectionKey]*managerClient),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	if params.idleTimeout > 0 {
+		go manager.evictLoop()
+	} else {
+		close(manager.done)
+	}
+
+	return manager
+}
+
+// Get returns cached connector, constructing it on the first call. Concurrent calls for the same key
+// wait for a single construction. Failed construction is not cached, the next call will try again.
+func (m *Manager) Get(ctx context.Context, key ConnectionKey) (Connector, error) { // nolint:ireturn
+	for {
+		conn, err := m.get(ctx, key)
+		if errors.Is(err, errStaleConnection) {
+			continue
+		}
+
+		return conn, err
+	}
+}
+
+func (m *Manager) get(ctx context.Context, key ConnectionKey) (Connector, error) { // nolint:ireturn
+	m.mut.Lock()
+
+	if m.closed {
+		m.mut.Unlock()
+
+		return nil, ErrManagerClosed
+	}
+
+	entry, found := m.entries[key]
+	if found {
+		entry.lastUsed = m.params.now()
+	} else {
+		entry = &managerEntry{
+			ready:    make(chan struct{}),
+			lastUsed: m.params.now(),
+		}
+		m.entries[key] = entry
+
+		// Construction outlives the caller, who may give up waiting while others are still interested.
+		go m.build(context.WithoutCancel(ctx), key, entry)
+	}
+
+	m.mut.Unlock()
+
+	select {
+	case <-entry.ready:
+		return entry.conn, entry.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (m *Manager) build(ctx context.Context, key ConnectionKey, entry *managerEntry) {
+	conn, err := m.factory(ctx, key)
+
+	m.mut.Lock()
+
+	current := m.entries[key] == entry
+
+	switch {
+	case err != nil:
+		if current {
+			delete(m.entries, key)
+		}
+	case !current:
+		// Credentials changed or manager was closed while connector was built, it must not be used.
+		err = errStaleConnection
+	}
+
+	m.mut.Unlock()
+
+	if errors.Is(err, errStaleConnection) {
+		closeConnector(key, conn)
+
+		conn = nil
+	}
+
+	entry.conn, entry.err = conn, err
+	close(entry.ready)
+}
+
+// HTTPClient returns authenticated client shared by every connector of the connection. It is created using
+// newClient on the first call and it outlives idle eviction, so that rebuilt connectors keep the current
+// access token instead of refreshing it. Pass it to the connector, ex: via WithAuthenticatedClient.
+// Failed construction is not cached. Invalidate forgets the client together with the connector.
+func (m *Manager) HTTPClient( // nolint:ireturn
+	key ConnectionKey, newClient func() (common.AuthenticatedHTTPClient, error),
+) (common.AuthenticatedHTTPClient, error) {
+	m.mut.Lock()
+
+	shared, found := m.clients[key]
+	if found {
+		m.mut.Unlock()
+		<-shared.ready
+
+		return shared.client, shared.err
+	}
+
+	shared = &managerClient{ready: make(chan struct{})}
+	m.clients[key] = shared
+	m.mut.Unlock()
+
+	shared.client, shared.err = newClient()
+
+	if shared.err != nil {
+		m.mut.Lock()
+		if m.clients[key] == shared {
+			delete(m.clients, key)
+		}
+		m.mut.Unlock()
+	}
+
+	close(shared.ready)
+
+	return shared.client, shared.err
+}
+
+// Invalidate closes and forgets connector and HTTP client of the connection.
+// Call it when credentials have changed, the next Get will construct a new connector.
+func (m *Manager) Invalidate(key ConnectionKey) error {
+	m.mut.Lock()
+	entry := m.entries[key]
+	delete(m.entries, key)
+	delete(m.clients, key)
+	m.mut.Unlock()
+
+	return closeEntry(entry)
+}
+
+// InvalidateTenant invalidates every connection of the tenant.
+func (m *Manager) InvalidateTenant(tenant string) error {
+	m.mut.Lock()
+
+	entries := make([]*managerEntry, 0)
+
+	for key, entry := range m.entries {
+		if key.Tenant == tenant {
+			entries = append(entries, entry)
+			delete(m.entries, key)
+		}
+	}
+
+	for key := range m.clients {
+		if key.Tenant == tenant {
+			delete(m.clients, key)
+		}
+	}
+
+	m.mut.Unlock()
+
+	return closeEntries(entries)
+}
+
+// EvictIdle closes connectors which were not requested within the idle timeout.
+// HTTP clients are kept. It is called periodically when WithIdleTimeout is set.
+func (m *Manager) EvictIdle() {
+	if m.params.idleTimeout <= 0 {
+		return
+	}
+
+	deadline := m.params.now().Add(-m.params.idleTimeout)
+
+	m.mut.Lock()
+
+	evicted := make(map[ConnectionKey]*managerEntry)
+
+	for key, entry := range m.entries {
+		if entry.lastUsed.Before(deadline) && isReady(entry) {
+			evicted[key] = entry
+			delete(m.entries, key)
+		}
+	}
+
+	m.mut.Unlock()
+
+	for key, entry := range evicted {
+		closeConnector(key, entry.conn)
+	}
+}
+
+// Len returns the number of cached connectors, including those being constructed.
+func (m *Manager) Len() int {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	return len(m.entries)
+}
+
+// Close stops background eviction and closes every cached connector.
+// Connectors which are being constructed are closed once construction finishes.
+func (m *Manager) Close() error {
+	m.mut.Lock()
+
+	if m.closed {
+		m.mut.Unlock()
+
+		return nil
+	}
+
+	m.closed = true
+	close(m.stop)
+
+	entries := make([]*managerEntry, 0, len(m.entries))
+	for _, entry := range m.entries {
+		entries = append(entries, entry)
+	}
+
+	m.entries = make(map[ConnectionKey]*managerEntry)
+	m.clients = make(map[ConnectionKey]*managerClient)
+	m.mut.Unlock()
+
+	<-m.done
+
+	return closeEntries(entries)
+}
+
+func (m *Manager) evictLoop() {
+	defer close(m.done)
+
+	ticker := time.NewTicker(max(m.params.idleTimeout/2, minEvictInterval)) // nolint:gomnd
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.EvictIdle()
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+func isReady(entry *managerEntry) bool {
+	select {
+	case <-entry.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+// closeEntry closes constructed connector. Connector under construction is
+// no longer registered, therefore it will be closed by the goroutine building it.
+func closeEntry(entry *managerEntry) error {
+	if entry == nil || !isReady(entry) || entry.conn == nil {
+		return nil
+	}
+
+	return entry.conn.Close()
+}
+
+func closeEntries(entries []*managerEntry) error {
+	errs := make([]error, 0)
+
+	for _, entry := range entries {
+		if err := closeEntry(entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// closeConnector is used when there is no caller to report the error to.
+func closeConnector(key ConnectionKey, conn Connector) {
+	if conn == nil {
+		return
+	}
+
+	if err := conn.Close(); err != nil {
+		slog.Warn("Failed to close connector",
+			"tenant", key.Tenant, "connectionId", key.ConnectionID, "error", err)
+	}
+}
@@ -0,0 +1,252 @@
+package connectors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type closeCountingConnector struct {
+	*mock.Connector
+	closed *atomic.Int32
+}
+
+func (c closeCountingConnector) Close() error {
+	c.closed.Add(1)
+
+	return nil
+}
+
+type testClient struct {
+	*http.Client
+	token string
+}
+
+func newTestClient(token string) func() (common.AuthenticatedHTTPClient, error) {
+	return func() (common.AuthenticatedHTTPClient, error) {
+		return &testClient{Client: &http.Client{}, token: token}, nil
+	}
+}
+
+type countingFactory struct {
+	built  atomic.Int32
+	closed atomic.Int32
+}
+
+func (f *countingFactory) New(_ context.Context, _ ConnectionKey) (Connector, error) { // nolint:ireturn
+	f.built.Add(1)
+
+	conn, err := mock.NewConnector(mock.WithClient(http.DefaultClient))
+	if err != nil {
+		return nil, err
+	}
+
+	return closeCountingConnector{Connector: conn, closed: &f.closed}, nil
+}
+
+func TestManagerGetCachesPerConnection(t *testing.T) {
+	t.Parallel()
+
+	factory := &countingFactory{}
+	manager := NewManager(factory.New)
+	ctx := context.Background()
+	first := ConnectionKey{Tenant: "acme", ConnectionID: "1"}
+	second := ConnectionKey{Tenant: "acme", ConnectionID: "2"}
+
+	var wg sync.WaitGroup
+
+	errs := make([]error, 20)
+
+	for i := range errs {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			_, errs[i] = manager.Get(ctx, first)
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	_, err := manager.Get(ctx, second)
+	require.NoError(t, err)
+
+	require.Equal(t, int32(2), factory.built.Load())
+	require.Equal(t, 2, manager.Len())
+
+	require.NoError(t, manager.Close())
+	require.Equal(t, int32(2), factory.closed.Load())
+
+	_, err = manager.Get(ctx, first)
+	require.ErrorIs(t, err, ErrManagerClosed)
+}
+
+func TestManagerInvalidate(t *testing.T) {
+	t.Parallel()
+
+	factory := &countingFactory{}
+	manager := NewManager(factory.New)
+	ctx := context.Background()
+	key := ConnectionKey{Tenant: "acme", ConnectionID: "1"}
+	other := ConnectionKey{Tenant: "globex", ConnectionID: "1"}
+
+	for _, k := range []ConnectionKey{key, other} {
+		_, err := manager.Get(ctx, k)
+		require.NoError(t, err)
+	}
+
+	client, err := manager.HTTPClient(key, newTestClient("old"))
+	require.NoError(t, err)
+
+	require.NoError(t, manager.InvalidateTenant("acme"))
+	require.Equal(t, int32(1), factory.closed.Load())
+	require.Equal(t, 1, manager.Len())
+
+	// Client is rebuilt with new credentials.
+	refreshed, err := manager.HTTPClient(key, newTestClient("new"))
+	require.NoError(t, err)
+	require.NotSame(t, client, refreshed)
+	require.Equal(t, "new", refreshed.(*testClient).token)
+
+	_, err = manager.Get(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), factory.built.Load())
+
+	require.NoError(t, manager.Invalidate(other))
+	require.Equal(t, int32(2), factory.closed.Load())
+}
+
+func TestManagerEvictIdle(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mut sync.Mutex
+		now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	clock := func() time.Time {
+		mut.Lock()
+		defer mut.Unlock()
+
+		return now
+	}
+	advance := func(duration time.Duration) {
+		mut.Lock()
+		defer mut.Unlock()
+
+		now = now.Add(duration)
+	}
+
+	factory := &countingFactory{}
+	manager := NewManager(factory.New, WithIdleTimeout(time.Hour), WithManagerClock(clock))
+	ctx := context.Background()
+	idle := ConnectionKey{Tenant: "acme", ConnectionID: "idle"}
+	busy := ConnectionKey{Tenant: "acme", ConnectionID: "busy"}
+
+	for _, key := range []ConnectionKey{idle, busy} {
+		_, err := manager.Get(ctx, key)
+		require.NoError(t, err)
+	}
+
+	clients := 0
+	newClient := func() (common.AuthenticatedHTTPClient, error) {
+		clients++
+
+		return newTestClient("token")()
+	}
+	_, err := manager.HTTPClient(idle, newClient)
+	require.NoError(t, err)
+
+	advance(45 * time.Minute)
+
+	_, err = manager.Get(ctx, busy)
+	require.NoError(t, err)
+
+	advance(30 * time.Minute)
+	manager.EvictIdle()
+
+	require.Equal(t, 1, manager.Len())
+	require.Equal(t, int32(1), factory.closed.Load())
+
+	// Client survives eviction.
+	_, err = manager.HTTPClient(idle, newClient)
+	require.NoError(t, err)
+	require.Equal(t, 1, clients)
+
+	require.NoError(t, manager.Close())
+}
+
+func TestManagerFactoryError(t *testing.T) {
+	t.Parallel()
+
+	errFactory := errors.New("bad credentials")
+	calls := 0
+	manager := NewManager(func(ctx context.Context, key ConnectionKey) (Connector, error) {
+		calls++
+
+		return nil, errFactory
+	})
+	key := ConnectionKey{Tenant: "acme", ConnectionID: "1"}
+
+	for i := 0; i < 2; i++ {
+		_, err := manager.Get(context.Background(), key)
+		require.ErrorIs(t, err, errFactory)
+	}
+
+	// Failures are not cached.
+	require.Equal(t, 2, calls)
+	require.Equal(t, 0, manager.Len())
+}
+
+func TestManagerDetachesFactoryContext(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	factory := &countingFactory{}
+	manager := NewManager(func(ctx context.Context, key ConnectionKey) (Connector, error) {
+		close(started)
+		<-release
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		return factory.New(ctx, key)
+	}, WithIdleTimeout(time.Nanosecond))
+	key := ConnectionKey{Tenant: "acme", ConnectionID: "1"}
+
+	// First caller gives up while the connector is being built.
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+
+	go func() {
+		_, err := manager.Get(ctx, key)
+		errs <- err
+	}()
+
+	<-started
+	cancel()
+	require.ErrorIs(t, <-errs, context.Canceled)
+
+	close(release)
+
+	_, err := manager.Get(context.Background(), key)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), factory.built.Load())
+
+	require.NoError(t, manager.Close())
+}
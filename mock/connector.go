@@ -13,6 +13,7 @@ type Connector struct {
 
 	read               func(ctx context.Context, params common.ReadParams) (*common.ReadResult, error)
 	write              func(ctx context.Context, params common.WriteParams) (*common.WriteResult, error)
+	delete             func(ctx context.Context, params common.DeleteParams) (*common.DeleteResult, error)
 	listObjectMetadata func(ctx context.Context, objectNames []string) (*common.ListObjectMetadataResult, error)
 }
 
@@ -36,6 +37,9 @@ func NewConnector(opts ...Option) (conn *Connector, outErr error) {
 		write: func(ctx context.Context, params common.WriteParams) (*common.WriteResult, error) {
 			return nil, fmt.Errorf("%w: %s", ErrNotImplemented, "write")
 		},
+		delete: func(ctx context.Context, params common.DeleteParams) (*common.DeleteResult, error) {
+			return nil, fmt.Errorf("%w: %s", ErrNotImplemented, "delete")
+		},
 		listObjectMetadata: func(ctx context.Context, objectNames []string) (*common.ListObjectMetadataResult, error) {
 			return nil, fmt.Errorf("%w: %s", ErrNotImplemented, "listObjectMetadata")
 		},
@@ -53,6 +57,7 @@ func NewConnector(opts ...Option) (conn *Connector, outErr error) {
 		client:             params.client,
 		read:               params.read,
 		write:              params.write,
+		delete:             params.delete,
 		listObjectMetadata: params.listObjectMetadata,
 	}, nil
 }
@@ -85,6 +90,10 @@ func (c *Connector) Write(ctx context.Context, params common.WriteParams) (*comm
 	return c.write(ctx, params)
 }
 
+func (c *Connector) Delete(ctx context.Context, params common.DeleteParams) (*common.DeleteResult, error) {
+	return c.delete(ctx, params)
+}
+
 func (c *Connector) ListObjectMetadata(
 	ctx context.Context,
 	objectNames []string,
@@ -5,6 +5,12 @@ import (
 )
 
 var (
-	ErrNotImplemented = errors.New("not implemented")
-	ErrMissingParam   = errors.New("missing required parameter")
+	ErrNotImplemented    = errors.New("not implemented")
+	ErrMissingParam      = errors.New("missing required parameter")
+	ErrUnknownObject     = errors.New("unknown object")
+	ErrUnknownField      = errors.New("unknown field")
+	ErrReadOnlyField     = errors.New("field is read-only")
+	ErrRecordNotFound    = errors.New("record not found")
+	ErrInvalidRecordData = errors.New("invalid record data")
+	ErrInvalidNextPage   = errors.New("invalid next page token")
 )
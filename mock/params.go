@@ -6,9 +6,10 @@ import (
 	"net/http"
 
 	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/common/paramsbuilder"
 )
 
-// Option is a function which mutates the hubspot connector configuration.
+// Option is a function which mutates the mock connector configuration.
 type Option func(params *mockParams)
 
 // WithClient sets the http client to use for the connector. Saves some boilerplate.
@@ -44,6 +45,24 @@ func WithWrite(write func(ctx context.Context, params common.WriteParams) (*comm
 	}
 }
 
+// WithDelete sets the delete function for the connector.
+func WithDelete(del func(ctx context.Context, params common.DeleteParams) (*common.DeleteResult, error)) Option {
+	return func(params *mockParams) {
+		params.delete = del
+	}
+}
+
+// WithStore backs read, write, delete and listObjectMetadata with the stateful in-memory store.
+// Functions set by other options after this one take precedence.
+func WithStore(store *Store) Option {
+	return func(params *mockParams) {
+		params.read = store.Read
+		params.write = store.Write
+		params.delete = store.Delete
+		params.listObjectMetadata = store.ListObjectMetadata
+	}
+}
+
 // WithListObjectMetadata sets the listObjectMetadata function for the connector.
 func WithListObjectMetadata(
 	listObjectMetadata func(ctx context.Context, objectNames []string) (*common.ListObjectMetadataResult, error),
@@ -55,9 +74,11 @@ func WithListObjectMetadata(
 
 // mockParams is the internal configuration for the mock connector.
 type mockParams struct {
+	paramsbuilder.Interceptors
 	client             *common.JSONHTTPClient // required
 	read               func(ctx context.Context, params common.ReadParams) (*common.ReadResult, error)
 	write              func(ctx context.Context, params common.WriteParams) (*common.WriteResult, error)
+	delete             func(ctx context.Context, params common.DeleteParams) (*common.DeleteResult, error)
 	listObjectMetadata func(ctx context.Context, objectNames []string) (*common.ListObjectMetadataResult, error)
 }
 
@@ -75,9 +96,29 @@ func (p *mockParams) prepare() (out *mockParams, err error) {
 		return nil, fmt.Errorf("%w: %s", ErrMissingParam, "write")
 	}
 
+	if p.delete == nil {
+		return nil, fmt.Errorf("%w: %s", ErrMissingParam, "delete")
+	}
+
 	if p.listObjectMetadata == nil {
 		return nil, fmt.Errorf("%w: %s", ErrMissingParam, "listObjectMetadata")
 	}
 
+	p.ApplyInterceptors(p.client.HTTPClient)
+
 	return p, nil
 }
+
+// WithInterceptors adds middleware of paramsbuilder.Interceptors. Its usage is optional.
+func WithInterceptors(interceptors ...common.Interceptor) Option {
+	return func(params *mockParams) {
+		params.WithInterceptors(interceptors...)
+	}
+}
+
+// WithCircuitBreaker sets the breaker of paramsbuilder.Interceptors. Its usage is optional.
+func WithCircuitBreaker(breaker *common.CircuitBreaker) Option {
+	return func(params *mockParams) {
+		params.WithCircuitBreaker(breaker)
+	}
+}
@@ -0,0 +1,369 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+)
+
+const (
+	// DefaultPageSize is number of records returned by Store.Read per page.
+	DefaultPageSize = 100
+
+	// FieldID is the key under which record identifier is stored in the Raw record.
+	FieldID = "id"
+	// FieldUpdatedAt is the key under which last modification time is stored in the Raw record.
+	FieldUpdatedAt = "updated_at"
+)
+
+// Store is a stateful in-memory backend for the mock connector.
+// It keeps object schemas and records, so the mock behaves like a real provider:
+// records get ids and modification timestamps, reads are paginated and honor Since and Deleted.
+// Store is safe for concurrent use.
+type Store struct {
+	mut      sync.Mutex
+	objects  map[string]*objectTable
+	pageSize int
+	now      func() time.Time
+}
+
+type objectTable struct {
+	metadata common.ObjectMetadata
+	records  map[string]*storedRecord
+	lastID   int64
+}
+
+type storedRecord struct {
+	id       string
+	data     map[string]any
+	modified time.Time
+	deleted  bool
+}
+
+// StoreOption is a function which mutates the store configuration.
+type StoreOption func(store *Store)
+
+// WithPageSize sets maximum number of records per page.
+func WithPageSize(size int) StoreOption {
+	return func(store *Store) {
+		store.pageSize = size
+	}
+}
+
+// WithClock sets the time source used for modification timestamps. Useful for deterministic tests.
+func WithClock(now func() time.Time) StoreOption {
+	return func(store *Store) {
+		store.now = now
+	}
+}
+
+// NewStore creates store with given object schemas. Each object will accept only fields listed in its FieldsMap,
+// unless the map is empty. Fields FieldID and FieldUpdatedAt are managed by the store and added to every schema.
+func NewStore(schemas map[string]common.ObjectMetadata, opts ...StoreOption) *Store {
+	store := &Store{
+		objects:  make(map[string]*objectTable),
+		pageSize: DefaultPageSize,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(store)
+	}
+
+	for objectName, schema := range schemas {
+		store.AddSchema(objectName, schema)
+	}
+
+	return store
+}
+
+// AddSchema registers new object or replaces the schema of existing one. Records are preserved.
+func (s *Store) AddSchema(objectName string, schema common.ObjectMetadata) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	fields := make(map[string]string, len(schema.FieldsMap)+2) // nolint:gomnd
+	for name, displayName := range schema.FieldsMap {
+		fields[name] = displayName
+	}
+
+	if len(fields) != 0 {
+		fields[FieldID] = FieldID
+		fields[FieldUpdatedAt] = FieldUpdatedAt
+	}
+
+	metadata := common.ObjectMetadata{
+		DisplayName: schema.DisplayName,
+		FieldsMap:   fields,
+	}
+
+	if table, ok := s.objects[objectName]; ok {
+		table.metadata = metadata
+
+		return
+	}
+
+	s.objects[objectName] = &objectTable{
+		metadata: metadata,
+		records:  make(map[string]*storedRecord),
+	}
+}
+
+// Read returns a page of records ordered by modification time.
+// Rows holds the number of all records matching the query, not only the ones on the current page.
+func (s *Store) Read(_ context.Context, params common.ReadParams) (*common.ReadResult, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	table, err := s.table(params.ObjectName)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, err := parseOffset(params.NextPage)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make([]*storedRecord, 0, len(table.records))
+
+	for _, record := range table.records {
+		if record.deleted != params.Deleted {
+			continue
+		}
+
+		if !params.Since.IsZero() && !record.modified.After(params.Since) {
+			continue
+		}
+
+		matching = append(matching, record)
+	}
+
+	sort.Slice(matching, func(i, j int) bool {
+		if matching[i].modified.Equal(matching[j].modified) {
+			return compareIDs(matching[i].id, matching[j].id)
+		}
+
+		return matching[i].modified.Before(matching[j].modified)
+	})
+
+	end := min(offset+s.pageSize, len(matching))
+	start := min(offset, end)
+	page := matching[start:end]
+
+	rows := make([]common.ReadResultRow, len(page))
+	for i, record := range page {
+		raw := record.raw()
+		rows[i] = common.ReadResultRow{
+			Fields: common.FieldsExtractorFor(params)(params.Fields, raw),
+			Raw:    raw,
+		}
+	}
+
+	var nextPage common.NextPageToken
+	if end < len(matching) {
+		nextPage = common.NextPageToken(strconv.Itoa(end))
+	}
+
+	return &common.ReadResult{
+		Rows:     int64(len(matching)),
+		Data:     rows,
+		NextPage: nextPage,
+		Done:     len(nextPage) == 0,
+	}, nil
+}
+
+// Write creates a record when RecordId is empty, otherwise updates fields of existing record.
+// RecordData must be a JSON object.
+func (s *Store) Write(_ context.Context, params common.WriteParams) (*common.WriteResult, error) {
+	data, err := common.RecordDataToMap(params.RecordData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRecordData, err)
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	table, err := s.table(params.ObjectName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = table.validateFields(data); err != nil {
+		return nil, err
+	}
+
+	var record *storedRecord
+
+	if len(params.RecordId) == 0 {
+		table.lastID++
+		record = &storedRecord{
+			id:   strconv.FormatInt(table.lastID, 10),
+			data: make(map[string]any),
+		}
+		table.records[record.id] = record
+	} else {
+		record, err = table.activeRecord(params.RecordId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for key, value := range data {
+		record.data[key] = value
+	}
+
+	record.modified = s.now()
+
+	return &common.WriteResult{
+		Success:  true,
+		RecordId: record.id,
+		Data:     record.raw(),
+	}, nil
+}
+
+// Delete marks record as deleted. It can be still read with ReadParams.Deleted.
+func (s *Store) Delete(_ context.Context, params common.DeleteParams) (*common.DeleteResult, error) {
+	if len(params.RecordId) == 0 {
+		return nil, common.ErrMissingRecordID
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	table, err := s.table(params.ObjectName)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := table.activeRecord(params.RecordId)
+	if err != nil {
+		return nil, err
+	}
+
+	record.deleted = true
+	record.modified = s.now()
+
+	return &common.DeleteResult{
+		Success: true,
+	}, nil
+}
+
+// ListObjectMetadata describes registered objects. Unknown objects are reported in Errors.
+func (s *Store) ListObjectMetadata(_ context.Context, objectNames []string) (*common.ListObjectMetadataResult, error) {
+	if len(objectNames) == 0 {
+		return nil, common.ErrMissingObjects
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	result := &common.ListObjectMetadataResult{
+		Result: make(map[string]common.ObjectMetadata),
+		Errors: make(map[string]error),
+	}
+
+	for _, objectName := range objectNames {
+		table, err := s.table(objectName)
+		if err != nil {
+			result.Errors[objectName] = err
+
+			continue
+		}
+
+		fields := make(map[string]string, len(table.metadata.FieldsMap))
+		for name, displayName := range table.metadata.FieldsMap {
+			fields[name] = displayName
+		}
+
+		result.Result[objectName] = common.ObjectMetadata{
+			DisplayName: table.metadata.DisplayName,
+			FieldsMap:   fields,
+		}
+	}
+
+	return result, nil
+}
+
+func (s *Store) table(objectName string) (*objectTable, error) {
+	if len(objectName) == 0 {
+		return nil, common.ErrMissingObjects
+	}
+
+	table, ok := s.objects[objectName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %w: %s", common.ErrBadRequest, ErrUnknownObject, objectName)
+	}
+
+	return table, nil
+}
+
+func (t *objectTable) activeRecord(recordID string) (*storedRecord, error) {
+	record, ok := t.records[recordID]
+	if !ok || record.deleted {
+		return nil, fmt.Errorf("%w: %w: %s", common.ErrBadRequest, ErrRecordNotFound, recordID)
+	}
+
+	return record, nil
+}
+
+func (t *objectTable) validateFields(data map[string]any) error {
+	if len(t.metadata.FieldsMap) == 0 {
+		// schema-less object accepts anything
+		return nil
+	}
+
+	for field := range data {
+		if field == FieldID || field == FieldUpdatedAt {
+			return fmt.Errorf("%w: %w: %s", common.ErrBadRequest, ErrReadOnlyField, field)
+		}
+
+		if _, ok := t.metadata.FieldsMap[field]; !ok {
+			return fmt.Errorf("%w: %w: %s", common.ErrBadRequest, ErrUnknownField, field)
+		}
+	}
+
+	return nil
+}
+
+// raw returns a copy of record data enriched with fields managed by the store.
+func (r *storedRecord) raw() map[string]any {
+	raw := make(map[string]any, len(r.data)+2) // nolint:gomnd
+	for key, value := range r.data {
+		raw[key] = value
+	}
+
+	raw[FieldID] = r.id
+	raw[FieldUpdatedAt] = r.modified.Format(time.RFC3339Nano)
+
+	return raw
+}
+
+func parseOffset(token common.NextPageToken) (int, error) {
+	if len(token) == 0 {
+		return 0, nil
+	}
+
+	offset, err := strconv.Atoi(token.String())
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("%w: %w: %s", common.ErrBadRequest, ErrInvalidNextPage, token)
+	}
+
+	return offset, nil
+}
+
+// compareIDs orders numeric identifiers by value, so that "10" comes after "9".
+func compareIDs(first, second string) bool {
+	a, errA := strconv.ParseInt(first, 10, 64)
+	b, errB := strconv.ParseInt(second, 10, 64)
+
+	if errA != nil || errB != nil {
+		return first < second
+	}
+
+	return a < b
+}
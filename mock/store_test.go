@@ -0,0 +1,242 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+)
+
+func TestStoreLifecycle(t *testing.T) { // nolint:funlen,gocognit,cyclop
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := newTestClock()
+	store := NewStore(map[string]common.ObjectMetadata{
+		"contacts": {
+			DisplayName: "Contacts",
+			FieldsMap:   map[string]string{"name": "Name", "email": "Email"},
+		},
+	}, WithPageSize(2), WithClock(clock.Now))
+
+	connector, err := NewConnector(WithClient(http.DefaultClient), WithStore(store))
+	if err != nil {
+		t.Fatalf("error in test while constructing connector %v", err)
+	}
+
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		result, err := connector.Write(ctx, common.WriteParams{
+			ObjectName: "contacts",
+			RecordData: map[string]any{"name": name},
+		})
+		if err != nil {
+			t.Fatalf("failed to create record: %v", err)
+		}
+
+		if len(result.RecordId) == 0 {
+			t.Fatalf("expected record id for %v", name)
+		}
+	}
+
+	checkpoint := clock.Now()
+
+	if _, err = connector.Write(ctx, common.WriteParams{
+		ObjectName: "contacts",
+		RecordId:   "1",
+		RecordData: map[string]any{"email": "alice@example.com"},
+	}); err != nil {
+		t.Fatalf("failed to update record: %v", err)
+	}
+
+	// Pagination walks over all records.
+	names := make([]any, 0)
+	params := common.ReadParams{ObjectName: "contacts", Fields: []string{"Name"}}
+
+	for {
+		result, err := connector.Read(ctx, params)
+		if err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+
+		if result.Rows != 3 {
+			t.Fatalf("expected 3 total rows, got %v", result.Rows)
+		}
+
+		for _, row := range result.Data {
+			names = append(names, row.Fields["name"])
+		}
+
+		if result.Done {
+			break
+		}
+
+		params.NextPage = result.NextPage
+	}
+
+	// Updated record moves to the end, because it was modified last.
+	if len(names) != 3 || names[0] != "Bob" || names[1] != "Carol" || names[2] != "Alice" {
+		t.Fatalf("unexpected read order: %v", names)
+	}
+
+	// Since returns only the updated record.
+	result, err := connector.Read(ctx, common.ReadParams{ObjectName: "contacts", Since: checkpoint})
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+
+	if len(result.Data) != 1 || result.Data[0].Raw["email"] != "alice@example.com" {
+		t.Fatalf("unexpected incremental read: %v", result.Data)
+	}
+
+	// Deleted records are visible only with Deleted flag.
+	if _, err = connector.Delete(ctx, common.DeleteParams{ObjectName: "contacts", RecordId: "2"}); err != nil {
+		t.Fatalf("failed to delete: %v", err)
+	}
+
+	result, err = connector.Read(ctx, common.ReadParams{ObjectName: "contacts", Deleted: true})
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+
+	if len(result.Data) != 1 || result.Data[0].Raw[FieldID] != "2" {
+		t.Fatalf("unexpected deleted read: %v", result.Data)
+	}
+
+	_, err = connector.Delete(ctx, common.DeleteParams{ObjectName: "contacts", RecordId: "2"})
+	if !errors.Is(err, ErrRecordNotFound) {
+		t.Fatalf("expected error (%v), got (%v)", ErrRecordNotFound, err)
+	}
+}
+
+func TestStoreErrors(t *testing.T) { // nolint:funlen
+	t.Parallel()
+
+	store := NewStore(map[string]common.ObjectMetadata{
+		"contacts": {DisplayName: "Contacts", FieldsMap: map[string]string{"name": "Name"}},
+	})
+
+	tests := []struct {
+		name         string
+		action       func(ctx context.Context) error
+		expectedErrs []error
+	}{
+		{
+			name: "Unknown object cannot be read",
+			action: func(ctx context.Context) error {
+				_, err := store.Read(ctx, common.ReadParams{ObjectName: "deals"})
+
+				return err
+			},
+			expectedErrs: []error{common.ErrBadRequest, ErrUnknownObject},
+		},
+		{
+			name: "Unknown field cannot be written",
+			action: func(ctx context.Context) error {
+				_, err := store.Write(ctx, common.WriteParams{
+					ObjectName: "contacts",
+					RecordData: map[string]any{"age": 3},
+				})
+
+				return err
+			},
+			expectedErrs: []error{common.ErrBadRequest, ErrUnknownField},
+		},
+		{
+			name: "Managed fields are read-only",
+			action: func(ctx context.Context) error {
+				_, err := store.Write(ctx, common.WriteParams{
+					ObjectName: "contacts",
+					RecordData: map[string]any{FieldID: "7"},
+				})
+
+				return err
+			},
+			expectedErrs: []error{common.ErrBadRequest, ErrReadOnlyField},
+		},
+		{
+			name: "Record data must be an object",
+			action: func(ctx context.Context) error {
+				_, err := store.Write(ctx, common.WriteParams{ObjectName: "contacts", RecordData: []string{"a"}})
+
+				return err
+			},
+			expectedErrs: []error{ErrInvalidRecordData},
+		},
+		{
+			name: "Missing record cannot be updated",
+			action: func(ctx context.Context) error {
+				_, err := store.Write(ctx, common.WriteParams{
+					ObjectName: "contacts",
+					RecordId:   "42",
+					RecordData: map[string]any{"name": "Bob"},
+				})
+
+				return err
+			},
+			expectedErrs: []error{common.ErrBadRequest, ErrRecordNotFound},
+		},
+		{
+			name: "Next page token must be produced by the store",
+			action: func(ctx context.Context) error {
+				_, err := store.Read(ctx, common.ReadParams{ObjectName: "contacts", NextPage: "abc"})
+
+				return err
+			},
+			expectedErrs: []error{ErrInvalidNextPage},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt // rebind, omit loop side effects for parallel goroutine
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.action(context.Background())
+			for _, expectedErr := range tt.expectedErrs {
+				if !errors.Is(err, expectedErr) {
+					t.Fatalf("%s: expected Error: (%v), got: (%v)", tt.name, expectedErr, err)
+				}
+			}
+		})
+	}
+}
+
+func TestStoreListObjectMetadata(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(map[string]common.ObjectMetadata{
+		"contacts": {DisplayName: "Contacts", FieldsMap: map[string]string{"name": "Name"}},
+	})
+
+	result, err := store.ListObjectMetadata(context.Background(), []string{"contacts", "deals"})
+	if err != nil {
+		t.Fatalf("failed to list metadata: %v", err)
+	}
+
+	fields := result.Result["contacts"].FieldsMap
+	if fields["name"] != "Name" || fields[FieldID] != FieldID || fields[FieldUpdatedAt] != FieldUpdatedAt {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+
+	if !errors.Is(result.Errors["deals"], ErrUnknownObject) {
+		t.Fatalf("expected error (%v), got (%v)", ErrUnknownObject, result.Errors["deals"])
+	}
+}
+
+// testClock advances by one second on every call, so each write gets a distinct timestamp.
+type testClock struct {
+	current time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{current: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.current = c.current.Add(time.Second)
+
+	return c.current
+}
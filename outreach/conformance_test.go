@@ -0,0 +1,59 @@
+package outreach
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/test/conformance"
+	"github.com/amp-labs/connectors/test/utils/mockutils"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	responseAccountsFirstPage := mockutils.DataFromFile(t, "read-accounts-first-page.json")
+	responseAccountsLastPage := mockutils.DataFromFile(t, "read-accounts-last-page.json")
+	responseCreateAccount := mockutils.DataFromFile(t, "write-create-account.json")
+
+	conformance.Run(t, conformance.Suite{
+		NewConnector: func(serverURL string) (any, error) {
+			connector, err := NewConnector(
+				WithAuthenticatedClient(http.DefaultClient),
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			// for testing we want to redirect calls to our mock server
+			connector.BaseURL = serverURL
+
+			return connector, nil
+		},
+		Read: &conformance.ReadSpec{
+			Params: common.ReadParams{
+				ObjectName: "accounts",
+				Fields:     []string{"id", "attributes.Name"},
+			},
+			Pages: []conformance.Response{
+				conformance.JSON(responseAccountsFirstPage),
+				conformance.JSON(responseAccountsLastPage),
+			},
+			Since: &conformance.SinceSpec{
+				Timestamp: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+				IsFiltered: func(req *http.Request, since time.Time) bool {
+					return req.URL.Query().Get("filter[updatedAt]") == "2024-05-01T10:00:00Z..inf"
+				},
+			},
+		},
+		Write: &conformance.WriteSpec{
+			Params: common.WriteParams{
+				ObjectName: "accounts",
+				RecordData: map[string]any{"type": "account", "attributes": map[string]any{"name": "Umbrella"}},
+			},
+			Response: conformance.JSON(responseCreateAccount),
+		},
+		Errors: conformance.StandardErrorCases([]byte(`{"errors": [{"id": "unauthorized", "title": "Unauthorized"}]}`)),
+	})
+}
@@ -14,6 +14,11 @@ const (
 type Connector struct {
 	BaseURL string
 	Client  *common.JSONHTTPClient
+
+	// metadata is used to expand wildcard fields and to validate writes.
+	metadata *common.MetadataCache
+	// idempotency remembers records created with an idempotency key.
+	idempotency common.IdempotencyStore
 }
 
 func NewConnector(opts ...Option) (conn *Connector, outErr error) {
@@ -54,8 +59,13 @@ func NewConnector(opts ...Option) (conn *Connector, outErr error) {
 
 	params.client.HTTPClient.Base = providerInfo.BaseURL
 
-	return &Connector{
+	conn = &Connector{
 		Client:  params.client,
 		BaseURL: restApi,
-	}, nil
+
+		idempotency: params.IdempotencyStore(),
+	}
+	conn.metadata = common.NewMetadataCache(conn.ListObjectMetadata)
+
+	return conn, nil
 }
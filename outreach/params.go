@@ -5,23 +5,29 @@ import (
 	"net/http"
 
 	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/common/paramsbuilder"
 	"golang.org/x/oauth2"
 )
 
 type outreachParams struct {
+	paramsbuilder.Interceptors
+	paramsbuilder.Idempotency
 	client *common.JSONHTTPClient
 }
 
 type Option func(params *outreachParams)
 
 func WithClient(ctx context.Context, client *http.Client, config *oauth2.Config, token *oauth2.Token,
+	opts ...common.OAuthOption,
 ) Option {
 	return func(params *outreachParams) {
-		oauthclient, err := common.NewOAuthHTTPClient(
-			ctx, common.WithOAuthClient(client),
+		options := []common.OAuthOption{
+			common.WithOAuthClient(client),
 			common.WithOAuthConfig(config),
 			common.WithOAuthToken(token),
-		)
+		}
+
+		oauthclient, err := common.NewOAuthHTTPClient(ctx, append(options, opts...)...)
 		if err != nil {
 			panic(err)
 		}
@@ -41,10 +47,30 @@ func WithAuthenticatedClient(client common.AuthenticatedHTTPClient) Option {
 	}
 }
 
+func WithIdempotencyStore(store common.IdempotencyStore) Option {
+	return func(params *outreachParams) {
+		params.WithIdempotencyStore(store)
+	}
+}
+
 func (params *outreachParams) prepare() (*outreachParams, error) {
 	if params.client == nil {
 		return nil, ErrMissingClient
 	}
 
+	params.ApplyInterceptors(params.client.HTTPClient)
+
 	return params, nil
 }
+
+func WithInterceptors(interceptors ...common.Interceptor) Option {
+	return func(params *outreachParams) {
+		params.WithInterceptors(interceptors...)
+	}
+}
+
+func WithCircuitBreaker(breaker *common.CircuitBreaker) Option {
+	return func(params *outreachParams) {
+		params.WithCircuitBreaker(breaker)
+	}
+}
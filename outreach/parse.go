@@ -2,6 +2,7 @@ package outreach
 
 import (
 	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/common/jsonquery"
 	"github.com/spyzhov/ajson"
 )
 
@@ -54,7 +55,7 @@ func checkURL(node *ajson.Node) (string, error) {
 		return "", ErrNotString
 	}
 
-	return next.String(), nil
+	return next.MustString(), nil
 }
 
 // getRecords returns the records from the response.
@@ -94,8 +95,18 @@ func getRecords(node *ajson.Node) ([]map[string]interface{}, error) {
 }
 
 // getTotalSize returns the total number of records that match the query.
+// It is reported in meta.count, when the count is missing the size of the page is used.
 func getTotalSize(node *ajson.Node) (int64, error) {
-	node, err := node.GetKey("data")
+	count, err := jsonquery.New(node, "meta").Integer("count", true)
+	if err != nil {
+		return 0, err
+	}
+
+	if count != nil {
+		return *count, nil
+	}
+
+	node, err = node.GetKey("data")
 	if err != nil {
 		return 0, err
 	}
@@ -108,15 +119,17 @@ func getTotalSize(node *ajson.Node) (int64, error) {
 }
 
 // getMarshaledData accepts a list of records and returns a list of structured data ([]ReadResultRow).
-func getMarshaledData(records []map[string]interface{}, fields []string) ([]common.ReadResultRow, error) {
-	data := make([]common.ReadResultRow, len(records))
-
-	for i, record := range records {
-		data[i] = common.ReadResultRow{
-			Fields: common.ExtractLowercaseFieldsFromRaw(fields, record),
-			Raw:    record,
+func getMarshaledData(extractFields common.FieldsExtractor) common.MarshalFunc {
+	return func(records []map[string]interface{}, fields []string) ([]common.ReadResultRow, error) {
+		data := make([]common.ReadResultRow, len(records))
+
+		for i, record := range records {
+			data[i] = common.ReadResultRow{
+				Fields: extractFields(fields, record),
+				Raw:    record,
+			}
 		}
-	}
 
-	return data, nil
+		return data, nil
+	}
 }
@@ -3,17 +3,22 @@ package outreach
 import (
 	"context"
 	"net/url"
+	"time"
 
 	"github.com/amp-labs/connectors/common"
 )
 
 func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common.ReadResult, error) {
 	var (
-		res    *common.JSONHTTPResponse
-		err    error
-		fields []string
+		res *common.JSONHTTPResponse
+		err error
 	)
 
+	config.Fields, err = c.metadata.ReadFields(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(config.NextPage) > 0 {
 		// If NextPage is set, then we're reading the next page of results.
 		// The NextPage URL has all the necessary parameters.
@@ -27,6 +32,13 @@ func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common
 			return nil, err
 		}
 
+		if !config.Since.IsZero() {
+			// Range filter without upper bound, next page links keep it.
+			query := url.Values{}
+			query.Add("filter[updatedAt]", config.Since.UTC().Format(time.RFC3339)+"..inf")
+			fullURL += "?" + query.Encode()
+		}
+
 		res, err = c.Client.Get(ctx, fullURL)
 		if err != nil {
 			return nil, err
@@ -36,7 +48,7 @@ func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common
 	return common.ParseResult(res, getTotalSize,
 		getRecords,
 		getNextRecordsURL,
-		getMarshaledData,
-		fields,
+		getMarshaledData(common.FieldsExtractorFor(config)),
+		config.Fields,
 	)
 }
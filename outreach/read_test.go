@@ -0,0 +1,81 @@
+package outreach
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+)
+
+func TestReadNestedAttributes(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": [{"id": 1, "type": "account",
+			"attributes": {"name": "Acme", "Domain": "acme.com"}}], "links": {}}`))
+	}))
+	defer server.Close()
+
+	connector, err := NewConnector(WithAuthenticatedClient(http.DefaultClient))
+	if err != nil {
+		t.Fatalf("error in test while constructing connector %v", err)
+	}
+
+	// for testing we want to redirect calls to our mock server
+	connector.BaseURL = server.URL
+
+	result, err := connector.Read(context.Background(), common.ReadParams{
+		ObjectName: "accounts",
+		Fields:     []string{"id", "attributes.name", "attributes.domain"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fields := result.Data[0].Fields
+	if fields["attributes.name"] != "Acme" || fields["attributes.domain"] != "acme.com" {
+		t.Fatalf("expected nested attributes to be extracted, got: %v", fields)
+	}
+}
+
+func TestReadSinceReportsCount(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("filter[updatedAt]") != "2024-05-01T10:00:00Z..inf" {
+			w.WriteHeader(http.StatusTeapot)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": [{"id": 1, "type": "account", "attributes": {"name": "Acme"}}],
+			"links": {}, "meta": {"count": 5, "count_truncated": false}}`))
+	}))
+	defer server.Close()
+
+	connector, err := NewConnector(WithAuthenticatedClient(http.DefaultClient))
+	if err != nil {
+		t.Fatalf("error in test while constructing connector %v", err)
+	}
+
+	// for testing we want to redirect calls to our mock server
+	connector.BaseURL = server.URL
+
+	result, err := connector.Read(context.Background(), common.ReadParams{
+		ObjectName: "accounts",
+		Fields:     []string{"id"},
+		Since:      time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Rows != 5 {
+		t.Fatalf("expected Rows to be the total from meta.count (5), got: %v", result.Rows)
+	}
+}
@@ -18,7 +18,16 @@ var JSONAPIContentTypeHeader = common.Header{ //nolint:gochecknoglobals
 	Value: "application/vnd.api+json",
 }
 
+// Write creates or updates a record.
 func (c *Connector) Write(ctx context.Context, config common.WriteParams) (*common.WriteResult, error) {
+	return common.IdempotentWrite(ctx, c.idempotency, config, c.write)
+}
+
+func (c *Connector) write(ctx context.Context, config common.WriteParams) (*common.WriteResult, error) {
+	if err := c.metadata.ValidateWrite(ctx, config); err != nil {
+		return nil, err
+	}
+
 	var write common.WriteMethod
 
 	URL, err := url.JoinPath(c.BaseURL, config.ObjectName)
@@ -91,6 +91,7 @@ var catalog = CatalogType{ // nolint:gochecknoglobals
 		BaseURL:  "https://{{.workspace}}.my.salesforce.com",
 		OauthOpts: &OauthOpts{
 			GrantType:                 AuthorizationCode,
+			AlternativeGrantTypes:     []OauthOptsGrantType{ClientCredentials, JwtBearer},
 			AuthURL:                   "https://{{.workspace}}.my.salesforce.com/services/oauth2/authorize",
 			TokenURL:                  "https://{{.workspace}}.my.salesforce.com/services/oauth2/token",
 			ExplicitScopesRequired:    false,
@@ -717,6 +718,8 @@ var catalog = CatalogType{ // nolint:gochecknoglobals
 		AuthType: Oauth2,
 		BaseURL:  "https://{{.server}}.docusign.net",
 		OauthOpts: &OauthOpts{
+			GrantType:                 AuthorizationCode,
+			AlternativeGrantTypes:     []OauthOptsGrantType{JwtBearer},
 			AuthURL:                   "https://account.docusign.com/oauth/auth",
 			TokenURL:                  "https://account.docusign.com/oauth/token",
 			ExplicitScopesRequired:    true,
@@ -743,6 +746,7 @@ var catalog = CatalogType{ // nolint:gochecknoglobals
 		BaseURL:  "https://demo.docusign.net",
 		OauthOpts: &OauthOpts{
 			GrantType:                 AuthorizationCode,
+			AlternativeGrantTypes:     []OauthOptsGrantType{JwtBearer},
 			AuthURL:                   "https://account-d.docusign.com/oauth/auth",
 			TokenURL:                  "https://account-d.docusign.com/oauth/token",
 			ExplicitScopesRequired:    true,
@@ -15,6 +15,7 @@ const (
 const (
 	AuthorizationCode OauthOptsGrantType = "authorizationCode"
 	ClientCredentials OauthOptsGrantType = "clientCredentials"
+	JwtBearer         OauthOptsGrantType = "jwtBearer"
 	PKCE              OauthOptsGrantType = "PKCE"
 )
 
@@ -42,12 +43,14 @@ type CatalogType map[string]ProviderInfo
 
 // OauthOpts defines model for OauthOpts.
 type OauthOpts struct {
-	AuthURL                   string              `json:"authURL" validate:"required"`
-	ExplicitScopesRequired    bool                `json:"explicitScopesRequired"`
-	ExplicitWorkspaceRequired bool                `json:"explicitWorkspaceRequired"`
-	GrantType                 OauthOptsGrantType  `json:"grantType"`
-	TokenMetadataFields       TokenMetadataFields `json:"tokenMetadataFields"`
-	TokenURL                  string              `json:"tokenURL" validate:"required"`
+	// AlternativeGrantTypes Other grant types supported by the provider, ex. server-to-server JWT bearer grant.
+	AlternativeGrantTypes     []OauthOptsGrantType `json:"alternativeGrantTypes,omitempty"`
+	AuthURL                   string               `json:"authURL" validate:"required"`
+	ExplicitScopesRequired    bool                 `json:"explicitScopesRequired"`
+	ExplicitWorkspaceRequired bool                 `json:"explicitWorkspaceRequired"`
+	GrantType                 OauthOptsGrantType   `json:"grantType"`
+	TokenMetadataFields       TokenMetadataFields  `json:"tokenMetadataFields"`
+	TokenURL                  string               `json:"tokenURL" validate:"required"`
 }
 
 // OauthOptsGrantType defines model for OauthOpts.GrantType.
@@ -6,6 +6,7 @@ import (
 	"encoding/gob"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"reflect"
 	"strings"
@@ -15,6 +16,7 @@ import (
 	"github.com/go-playground/validator"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/oauth2/jwt"
 )
 
 var (
@@ -167,6 +169,11 @@ type BasicParams struct {
 type OAuth2AuthCodeParams struct {
 	Config *oauth2.Config
 	Token  *oauth2.Token
+
+	// TokenStore is where the token is loaded from and refreshed tokens are saved to. Optional.
+	TokenStore common.TokenStore
+	// TokenStoreKey identifies the connection in the TokenStore.
+	TokenStoreKey string
 }
 
 // NewClientParams is the parameters to create a new HTTP client.
@@ -174,6 +181,10 @@ type NewClientParams struct {
 	// Debug will enable debug mode for the client.
 	Debug bool
 
+	// DebugLogger enables structured debug logging of requests and responses.
+	// Secrets are redacted, including the provider's API key header. It takes precedence over Debug.
+	DebugLogger *slog.Logger
+
 	// Client is the http client to use for the client. If
 	// the value is nil, the default http client will be used.
 	Client *http.Client
@@ -190,41 +201,81 @@ type NewClientParams struct {
 	// If the provider uses auth code, this field must be set.
 	OAuth2AuthCodeCreds *OAuth2AuthCodeParams
 
+	// OAuth2JWTCreds is the JWT bearer grant configuration, with private key in PEM format.
+	// If the provider uses JWT bearer grant, this field must be set.
+	OAuth2JWTCreds *jwt.Config
+
+	// GrantType selects one of the alternative grant types supported by the provider.
+	// If the value is empty, the grant type from the catalog is used.
+	GrantType OauthOptsGrantType
+
 	// ApiKey is the api key to use for the client. If the provider uses api-key
 	// auth, this field must be set.
 	ApiKey string
 }
 
+// SupportsGrantType reports whether the provider supports the OAuth2 grant type,
+// either as the main one or as an alternative.
+func (i *ProviderInfo) SupportsGrantType(grantType OauthOptsGrantType) bool {
+	if i.OauthOpts == nil {
+		return false
+	}
+
+	if i.OauthOpts.GrantType == grantType {
+		return true
+	}
+
+	for _, alternative := range i.OauthOpts.AlternativeGrantTypes {
+		if alternative == grantType {
+			return true
+		}
+	}
+
+	return false
+}
+
 // NewClient will create a new authenticated client based on the provider's auth type.
 func (i *ProviderInfo) NewClient(ctx context.Context, params *NewClientParams) (common.AuthenticatedHTTPClient, error) { //nolint:lll,cyclop,ireturn
 	if params == nil {
 		params = &NewClientParams{}
 	}
 
+	debug := i.debugFunc(params)
+
 	switch i.AuthType {
 	case None:
-		return createUnauthenticatedClient(ctx, params.Client, params.Debug)
+		return createUnauthenticatedClient(ctx, params.Client, debug)
 	case Oauth2:
 		if i.OauthOpts == nil {
 			return nil, fmt.Errorf("%w: %s", ErrClient, "oauth2 options not found")
 		}
 
-		switch i.OauthOpts.GrantType {
-		case AuthorizationCode:
-			return createOAuth2AuthCodeHTTPClient(ctx, params.Client, params.Debug, params.OAuth2AuthCodeCreds)
+		grantType := i.OauthOpts.GrantType
+		if len(params.GrantType) != 0 {
+			if !i.SupportsGrantType(params.GrantType) {
+				return nil, fmt.Errorf("%w: grant type %q not supported by %s", ErrClient, params.GrantType, i.Name)
+			}
+
+			grantType = params.GrantType
+		}
+
+		switch grantType {
+		case AuthorizationCode, PKCE:
+			// Tokens obtained with PKCE are refreshed the same way, only the code exchange differs.
+			return createOAuth2AuthCodeHTTPClient(ctx, params.Client, debug, params.OAuth2AuthCodeCreds)
 		case ClientCredentials:
-			return createOAuth2ClientCredentialsHTTPClient(ctx, params.Client, params.Debug, params.OAuth2ClientCreds)
-		case PKCE:
-			return nil, fmt.Errorf("%w: %s", ErrClient, "PKCE grant type not supported")
+			return createOAuth2ClientCredentialsHTTPClient(ctx, params.Client, debug, params.OAuth2ClientCreds)
+		case JwtBearer:
+			return createOAuth2JWTBearerHTTPClient(ctx, params.Client, debug, params.OAuth2JWTCreds)
 		default:
-			return nil, fmt.Errorf("%w: unsupported grant type %q", ErrClient, i.OauthOpts.GrantType)
+			return nil, fmt.Errorf("%w: unsupported grant type %q", ErrClient, grantType)
 		}
 	case Basic:
 		if params.BasicCreds == nil {
 			return nil, fmt.Errorf("%w: %s", ErrClient, "basic credentials not found")
 		}
 
-		return createBasicAuthHTTPClient(ctx, params.Client, params.Debug, params.BasicCreds.User, params.BasicCreds.Pass)
+		return createBasicAuthHTTPClient(ctx, params.Client, debug, params.BasicCreds.User, params.BasicCreds.Pass)
 	case ApiKey:
 		if i.ApiKeyOpts == nil {
 			return nil, fmt.Errorf("%w: api key options not found", ErrClient)
@@ -234,12 +285,43 @@ func (i *ProviderInfo) NewClient(ctx context.Context, params *NewClientParams) (
 			return nil, fmt.Errorf("%w: api key not given", ErrClient)
 		}
 
-		return createApiKeyHTTPClient(ctx, params.Client, params.Debug, i, params.ApiKey)
+		return createApiKeyHTTPClient(ctx, params.Client, debug, i, params.ApiKey)
 	default:
 		return nil, fmt.Errorf("%w: unsupported auth type %q", ErrClient, i.AuthType)
 	}
 }
 
+// RedactedHeaders returns headers carrying credentials specific to the provider.
+func (i *ProviderInfo) RedactedHeaders() []string {
+	headers := make([]string, 0)
+
+	if i.ApiKeyOpts != nil && len(i.ApiKeyOpts.HeaderName) != 0 {
+		headers = append(headers, i.ApiKeyOpts.HeaderName)
+	}
+
+	return headers
+}
+
+// DebugLoggerOptions configures common.NewDebugLogger to redact credentials specific to the provider.
+func (i *ProviderInfo) DebugLoggerOptions() []common.DebugLoggerOption {
+	return []common.DebugLoggerOption{
+		common.WithRedactedHeaders(i.RedactedHeaders()...),
+	}
+}
+
+// debugFunc returns the callback which dumps requests and responses, or nil if debugging is disabled.
+func (i *ProviderInfo) debugFunc(params *NewClientParams) func(req *http.Request, rsp *http.Response) {
+	if params.DebugLogger != nil {
+		return common.NewDebugLogger(append(i.DebugLoggerOptions(), common.WithDebugSlogLogger(params.DebugLogger))...)
+	}
+
+	if params.Debug {
+		return common.PrintRequestAndResponse
+	}
+
+	return nil
+}
+
 func getClient(client *http.Client) *http.Client {
 	if client == nil {
 		return http.DefaultClient
@@ -251,14 +333,14 @@ func getClient(client *http.Client) *http.Client {
 func createUnauthenticatedClient( //nolint:ireturn
 	ctx context.Context,
 	client *http.Client,
-	dbg bool,
+	debug func(req *http.Request, rsp *http.Response),
 ) (common.AuthenticatedHTTPClient, error) {
 	opts := []common.HeaderAuthClientOption{
 		common.WithHeaderClient(getClient(client)),
 	}
 
-	if dbg {
-		opts = append(opts, common.WithHeaderDebug(common.PrintRequestAndResponse))
+	if debug != nil {
+		opts = append(opts, common.WithHeaderDebug(debug))
 	}
 
 	return common.NewHeaderAuthHTTPClient(ctx, opts...)
@@ -267,7 +349,7 @@ func createUnauthenticatedClient( //nolint:ireturn
 func createBasicAuthHTTPClient( //nolint:ireturn
 	ctx context.Context,
 	client *http.Client,
-	dbg bool,
+	debug func(req *http.Request, rsp *http.Response),
 	user string,
 	pass string,
 ) (common.AuthenticatedHTTPClient, error) {
@@ -275,8 +357,8 @@ func createBasicAuthHTTPClient( //nolint:ireturn
 		common.WithHeaderClient(getClient(client)),
 	}
 
-	if dbg {
-		opts = append(opts, common.WithHeaderDebug(common.PrintRequestAndResponse))
+	if debug != nil {
+		opts = append(opts, common.WithHeaderDebug(debug))
 	}
 
 	c, err := common.NewBasicAuthHTTPClient(ctx, user, pass, opts...)
@@ -290,7 +372,7 @@ func createBasicAuthHTTPClient( //nolint:ireturn
 func createOAuth2AuthCodeHTTPClient( //nolint:ireturn
 	ctx context.Context,
 	client *http.Client,
-	dbg bool,
+	debug func(req *http.Request, rsp *http.Response),
 	cfg *OAuth2AuthCodeParams,
 ) (common.AuthenticatedHTTPClient, error) {
 	if cfg == nil {
@@ -303,8 +385,12 @@ func createOAuth2AuthCodeHTTPClient( //nolint:ireturn
 		common.WithOAuthToken(cfg.Token),
 	}
 
-	if dbg {
-		options = append(options, common.WithOAuthDebug(common.PrintRequestAndResponse))
+	if debug != nil {
+		options = append(options, common.WithOAuthDebug(debug))
+	}
+
+	if cfg.TokenStore != nil {
+		options = append(options, common.WithTokenStore(cfg.TokenStore, cfg.TokenStoreKey))
 	}
 
 	oauthClient, err := common.NewOAuthHTTPClient(ctx, options...)
@@ -318,16 +404,43 @@ func createOAuth2AuthCodeHTTPClient( //nolint:ireturn
 func createOAuth2ClientCredentialsHTTPClient( //nolint:ireturn
 	ctx context.Context,
 	client *http.Client,
-	dbg bool,
+	debug func(req *http.Request, rsp *http.Response),
 	cfg *clientcredentials.Config,
+) (common.AuthenticatedHTTPClient, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("%w: client credentials config not provided", ErrClient)
+	}
+
+	return createOAuth2GrantHTTPClient(ctx, client, debug, common.WithClientCredentials(cfg))
+}
+
+func createOAuth2JWTBearerHTTPClient( //nolint:ireturn
+	ctx context.Context,
+	client *http.Client,
+	debug func(req *http.Request, rsp *http.Response),
+	cfg *jwt.Config,
+) (common.AuthenticatedHTTPClient, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("%w: jwt config not provided", ErrClient)
+	}
+
+	return createOAuth2GrantHTTPClient(ctx, client, debug, common.WithJWTBearer(cfg))
+}
+
+// createOAuth2GrantHTTPClient creates client for grants which obtain tokens without user interaction.
+func createOAuth2GrantHTTPClient( //nolint:ireturn
+	ctx context.Context,
+	client *http.Client,
+	debug func(req *http.Request, rsp *http.Response),
+	grant common.OAuthOption,
 ) (common.AuthenticatedHTTPClient, error) {
 	options := []common.OAuthOption{
 		common.WithOAuthClient(getClient(client)),
-		common.WithTokenSource(cfg.TokenSource(ctx)),
+		grant,
 	}
 
-	if dbg {
-		options = append(options, common.WithOAuthDebug(common.PrintRequestAndResponse))
+	if debug != nil {
+		options = append(options, common.WithOAuthDebug(debug))
 	}
 
 	oauthClient, err := common.NewOAuthHTTPClient(ctx, options...)
@@ -341,7 +454,7 @@ func createOAuth2ClientCredentialsHTTPClient( //nolint:ireturn
 func createApiKeyHTTPClient( //nolint:ireturn
 	ctx context.Context,
 	client *http.Client,
-	dbg bool,
+	debug func(req *http.Request, rsp *http.Response),
 	info *ProviderInfo,
 	apiKey string,
 ) (common.AuthenticatedHTTPClient, error) {
@@ -353,8 +466,8 @@ func createApiKeyHTTPClient( //nolint:ireturn
 		common.WithHeaderClient(getClient(client)),
 	}
 
-	if dbg {
-		opts = append(opts, common.WithHeaderDebug(common.PrintRequestAndResponse))
+	if debug != nil {
+		opts = append(opts, common.WithHeaderDebug(debug))
 	}
 
 	c, err := common.NewApiKeyAuthHTTPClient(ctx, info.ApiKeyOpts.HeaderName, apiKey, opts...)
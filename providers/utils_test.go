@@ -0,0 +1,90 @@
+package providers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2/jwt"
+)
+
+func TestNewClientJWTBearerAssertionExpiry(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	var validity atomic.Int64
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			var claims struct {
+				ExpiresAt int64 `json:"exp"`
+			}
+
+			parts := strings.Split(r.FormValue("assertion"), ".")
+			if len(parts) != 3 { // nolint:gomnd
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+			if err != nil || json.Unmarshal(payload, &claims) != nil {
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			validity.Store(claims.ExpiresAt - time.Now().Unix())
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token": "access", "token_type": "Bearer"}`))
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	info, err := ReadInfo(Salesforce, &map[string]string{"workspace": "test"})
+	require.NoError(t, err)
+
+	client, err := info.NewClient(context.Background(), &NewClientParams{
+		GrantType: JwtBearer,
+		OAuth2JWTCreds: &jwt.Config{
+			Email:      "consumer-key",
+			Subject:    "user@example.com",
+			Audience:   "https://login.salesforce.com",
+			PrivateKey: privatePEM,
+			TokenURL:   server.URL + "/token",
+		},
+	})
+	require.NoError(t, err)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/api", nil)
+	require.NoError(t, err)
+
+	rsp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = rsp.Body.Close()
+
+	// Salesforce rejects assertions valid for more than 3 minutes.
+	assert.Positive(t, validity.Load())
+	assert.LessOrEqual(t, time.Duration(validity.Load())*time.Second, 3*time.Minute)
+}
@@ -1,6 +1,7 @@
 package salesforce
 
 import (
+	"bytes"
 	"context"
 	"encoding/csv"
 	"errors"
@@ -27,6 +28,10 @@ const (
 
 	sfIdFieldName    = "sf__Id"
 	sfErrorFieldName = "sf__Error"
+
+	// CSV dialect of bulk write jobs.
+	bulkColumnDelimiter = "COMMA"
+	bulkLineEnding      = "LF"
 )
 
 var (
@@ -37,6 +42,7 @@ var (
 	ErrUnsupportedMode      = errors.New("unsupported mode")
 	ErrReadToByteFailed     = errors.New("failed to read data to bytes")
 	ErrUnsupportedOperation = errors.New("unsupported operation")
+	ErrUnsupportedCSVFormat = errors.New("unsupported CSV format")
 )
 
 // BulkOperationParams defines how we are writing data to a SaaS API.
@@ -52,6 +58,10 @@ type BulkOperationParams struct {
 
 	// Salesforce operation mode
 	Mode BulkOperationMode
+
+	// Validate checks CSV columns and values against object metadata before the job is created.
+	// Nothing is sent to Salesforce when any row is invalid.
+	Validate bool // optional, defaults to false
 }
 
 type BulkOperationMode string
@@ -90,6 +100,38 @@ type GetJobInfoResult struct {
 	IsPkChunkingSupported   bool    `json:"isPkChunkingSupported,omitempty"`
 }
 
+// CSVFormat returns the dialect of job's CSV data, empty settings are Salesforce defaults: comma and LF.
+func (r *GetJobInfoResult) CSVFormat() (common.CSVFormat, error) {
+	return jobCSVFormat(r.ColumnDelimiter, r.LineEnding)
+}
+
+// jobCSVFormat converts job's column delimiter and line ending settings to the CSV dialect.
+func jobCSVFormat(columnDelimiter, lineEnding string) (common.CSVFormat, error) {
+	delimiters := map[string]rune{
+		"":          ',',
+		"BACKQUOTE": '`',
+		"CARET":     '^',
+		"COMMA":     ',',
+		"PIPE":      '|',
+		"SEMICOLON": ';',
+		"TAB":       '\t',
+	}
+
+	delimiter, ok := delimiters[columnDelimiter]
+	if !ok {
+		return common.CSVFormat{}, fmt.Errorf("%w: column delimiter %s", ErrUnsupportedCSVFormat, columnDelimiter)
+	}
+
+	switch lineEnding {
+	case "", "LF":
+		return common.CSVFormat{Delimiter: delimiter}, nil
+	case "CRLF":
+		return common.CSVFormat{Delimiter: delimiter, UseCRLF: true}, nil
+	default:
+		return common.CSVFormat{}, fmt.Errorf("%w: line ending %s", ErrUnsupportedCSVFormat, lineEnding)
+	}
+}
+
 type FailInfo struct {
 	FailureType   string              `json:"failureType"`
 	FailedUpdates map[string][]string `json:"failedUpdates,omitempty"`
@@ -119,7 +161,27 @@ func (c *Connector) BulkWrite( //nolint:funlen,cyclop
 		"externalIdFieldName": config.ExternalIdField,
 		"contentType":         "CSV",
 		"operation":           "upsert",
-		"lineEnding":          "LF",
+		"columnDelimiter":     bulkColumnDelimiter,
+		"lineEnding":          bulkLineEnding,
+	}
+
+	if config.Validate {
+		format, err := jobCSVFormat(bulkColumnDelimiter, bulkLineEnding)
+		if err != nil {
+			return nil, err
+		}
+
+		// Upload reads the whole CSV into memory anyway, so it is checked before the job is created.
+		data, err := io.ReadAll(config.CSVData)
+		if err != nil {
+			return nil, errors.Join(ErrReadToByteFailed, err)
+		}
+
+		if err = c.validateCSV(ctx, config.ObjectName, data, format); err != nil {
+			return nil, err
+		}
+
+		config.CSVData = bytes.NewReader(data)
 	}
 
 	result, err := c.bulkOperation(ctx, config, jobBody)
@@ -130,6 +192,53 @@ func (c *Connector) BulkWrite( //nolint:funlen,cyclop
 	return result, nil
 }
 
+// validateCSV checks every row against object metadata.
+// Relationship columns, ex: "Account.External_Id__c", reference other objects and are not checked.
+// Upserts can update existing records, so create-only fields are reported.
+func (c *Connector) validateCSV(ctx context.Context,
+	objectName string, data []byte, format common.CSVFormat,
+) error {
+	metadata, err := c.metadata.GetObjectMetadata(ctx, objectName)
+	if err != nil {
+		return err
+	}
+
+	validator := common.NewTextRecordsValidator(objectName, metadata, false)
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = format.Delimiter
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", common.ErrParseError, err)
+	}
+
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return validator.Err()
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w: %w", common.ErrParseError, err)
+		}
+
+		record := make(map[string]string, len(header))
+
+		for index, column := range header {
+			if !strings.Contains(column, ".") && index < len(row) {
+				record[column] = row[index]
+			}
+		}
+
+		validator.Check(record)
+	}
+}
+
 func joinURLPath(baseURL string, paths ...string) (string, error) {
 	location, err := url.JoinPath(baseURL, paths...)
 	if err != nil {
@@ -165,6 +274,15 @@ func (c *Connector) uploadCSV(ctx context.Context, jobId string, csvData io.Read
 	return c.Client.PutCSV(ctx, location, data)
 }
 
+func (c *Connector) abortJob(ctx context.Context, jobId string) (*common.JSONHTTPResponse, error) {
+	location, err := joinURLPath(c.BaseURL, "jobs/ingest/"+jobId)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Client.Patch(ctx, location, map[string]any{"state": JobStateAborted})
+}
+
 func (c *Connector) completeUpload(ctx context.Context, jobId string) (*common.JSONHTTPResponse, error) {
 	updateLoadCompleteBody := map[string]interface{}{
 		"state": JobStateUploadComplete,
@@ -232,37 +350,36 @@ func (c *Connector) GetSuccessfulJobResults(ctx context.Context, jobId string) (
 		return nil, fmt.Errorf("failed to create get request: %w", err)
 	}
 
-	// Get the connector's JSONHTTPClient, which is a special HTTPClient that handles JSON responses,
-	// and use it's underlying http.Client to make the request.
-	return c.Client.HTTPClient.Client.Do(req)
+	return c.streamResults(req)
 }
 
-func (c *Connector) getJobResults(ctx context.Context, jobId string) (*http.Response, error) {
-	location, err := joinURLPath(c.BaseURL, fmt.Sprintf("jobs/ingest/%s/failedResults", jobId))
+func (c *Connector) getJobResults(ctx context.Context, jobInfo *GetJobInfoResult) (*common.CSVRecordReader, error) {
+	location, err := joinURLPath(c.BaseURL, fmt.Sprintf("jobs/ingest/%s/failedResults", jobInfo.Id))
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := common.MakeJSONGetRequest(ctx, location, nil)
+	format, err := jobInfo.CSVFormat()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create get request: %w", err)
+		return nil, err
+	}
+
+	client := &common.CSVHTTPClient{
+		HTTPClient:         c.Client.HTTPClient,
+		ErrorPostProcessor: c.Client.ErrorPostProcessor,
 	}
 
-	// Get the connector's JSONHTTPClient, which is a special HTTPClient that handles JSON responses,
-	// and use it's underlying http.Client to make the request.
-	return c.Client.HTTPClient.Client.Do(req)
+	return client.Get(ctx, location, format)
 }
 
-//nolint:funlen,cyclop
+//nolint:cyclop
 func (c *Connector) getPartialFailureDetails(ctx context.Context, jobInfo *GetJobInfoResult) (*JobResults, error) {
 	// Query Salesforce to get partial failure details
-	res, err := c.getJobResults(ctx, jobInfo.Id)
+	reader, err := c.getJobResults(ctx, jobInfo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get job results: %w", err)
 	}
-	defer res.Body.Close()
-	// if partially failed, we need to get the error message for each record
-	reader := csv.NewReader(res.Body)
+	defer reader.Close()
 
 	failInfo := &FailInfo{
 		FailureType:   "Partial",
@@ -270,12 +387,26 @@ func (c *Connector) getPartialFailureDetails(ctx context.Context, jobInfo *GetJo
 		FailedCreates: make(map[string][]string),
 	}
 
-	var sfIdColIdx, sfErrorColIdx, externalIdColIdx int
+	fieldNames := []string{sfIdFieldName, sfErrorFieldName}
+
+	if jobInfo.ExternalIdFieldName != "" {
+		fieldNames = append(fieldNames, jobInfo.ExternalIdFieldName)
+	}
 
-	rowIdx := 0
+	header, err := reader.Header()
+	if err != nil {
+		return nil, err
+	}
+
+	// Salesforce API responses may not be consistent with the order and case of columns,
+	// so we need to find the column name used in the header row.
+	columns, err := getColumnNames(header, fieldNames)
+	if err != nil {
+		return nil, err
+	}
 
 	for {
-		record, err := reader.Read()
+		record, err := reader.Next()
 		if err != nil {
 			// end of file
 			if errors.Is(err, io.EOF) {
@@ -285,35 +416,9 @@ func (c *Connector) getPartialFailureDetails(ctx context.Context, jobInfo *GetJo
 			return nil, err
 		}
 
-		fieldNames := []string{sfIdFieldName, sfErrorFieldName}
-
-		if jobInfo.ExternalIdFieldName != "" {
-			fieldNames = append(fieldNames, jobInfo.ExternalIdFieldName)
-		}
-
-		// Get column index of sf__Id, sf__Error, and externalIdFieldName in header row
-		// Salesforce API responses may not be consistent with the order of columns, so we need to get the index
-		if rowIdx == 0 {
-			indiceMap, err := getColumnIndice(record, fieldNames)
-			if err != nil {
-				return nil, err
-			}
-
-			sfIdColIdx = indiceMap[sfIdFieldName]
-			sfErrorColIdx = indiceMap[sfErrorFieldName]
-
-			if jobInfo.ExternalIdFieldName != "" {
-				externalIdColIdx = indiceMap[jobInfo.ExternalIdFieldName]
-			}
-
-			rowIdx++
-
-			continue
-		}
-
-		sfId := record[sfIdColIdx]
+		sfId := record[columns[sfIdFieldName]]
 		failureMap := failInfo.FailedUpdates
-		errMsg := record[sfErrorColIdx]
+		errMsg := record[columns[sfErrorFieldName]]
 
 		if sfId == "" {
 			// If sf__Id is empty, it means the record is not updated, so it's a create failure
@@ -325,7 +430,7 @@ func (c *Connector) getPartialFailureDetails(ctx context.Context, jobInfo *GetJo
 		switch jobInfo.Operation {
 		case "upsert":
 			// for bulkwrite, we will have ExternalIdFieldName
-			referenceId = record[externalIdColIdx]
+			referenceId = record[columns[jobInfo.ExternalIdFieldName]]
 		case "delete":
 			// for bulkdelete, we will have sf__Id as reference
 			referenceId = sfId
@@ -345,15 +450,16 @@ func (c *Connector) getPartialFailureDetails(ctx context.Context, jobInfo *GetJo
 	}, nil
 }
 
-func getColumnIndice(record []string, columnNames []string) (map[string]int, error) {
-	indices := make(map[string]int)
+// getColumnNames maps each of column names to its spelling in the header, names are matched ignoring the case.
+func getColumnNames(header []string, columnNames []string) (map[string]string, error) {
+	names := make(map[string]string)
 
 	for _, columnName := range columnNames {
 		found := false
 
-		for j, value := range record {
+		for _, value := range header {
 			if strings.EqualFold(value, columnName) {
-				indices[columnName] = j
+				names[columnName] = value
 				found = true
 
 				break
@@ -365,7 +471,7 @@ func getColumnIndice(record []string, columnNames []string) (map[string]int, err
 		}
 	}
 
-	return indices, nil
+	return names, nil
 }
 
 func (c *Connector) getIncompleteJobResults(jobInfo *GetJobInfoResult) *JobResults {
@@ -446,6 +552,9 @@ func (c *Connector) bulkOperation(
 	// upload csv and there is no response body other than status code
 	_, err = c.uploadCSV(ctx, jobId, params.CSVData)
 	if err != nil {
+		// Job would stay open otherwise.
+		_, _ = c.abortJob(ctx, jobId)
+
 		return nil, fmt.Errorf("uploadCSV failed: %w", err)
 	}
 
@@ -549,7 +658,20 @@ func (c *Connector) GetBulkQueryResults(
 		return nil, fmt.Errorf("failed to get results for bulk query %s: %w", jobId, err)
 	}
 
-	// Get the connector's JSONHTTPClient, which is a special HTTPClient that handles JSON responses,
-	// and use it's underlying http.Client to make the request.
-	return c.Client.HTTPClient.Client.Do(req)
+	return c.streamResults(req)
+}
+
+// streamResults sends the request and returns the response with unread body, which is usually CSV.
+// Non-2xx responses are turned into errors by the connector's error interpreter.
+func (c *Connector) streamResults(req *http.Request) (*http.Response, error) {
+	res, err := c.Client.HTTPClient.Stream(req) //nolint:bodyclose
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, common.ErrRequestFailed
+	}
+
+	return res, nil
 }
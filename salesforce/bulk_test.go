@@ -0,0 +1,136 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/test/utils/mockutils"
+)
+
+func TestGetPartialFailureDetails(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/jobs/ingest/750/failedResults") {
+			w.WriteHeader(http.StatusTeapot)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv")
+		// Columns are in a different order and case than requested.
+		_, _ = w.Write([]byte("\"sf__Error\"|\"sf__Id\"|\"external_id__c\"\r\n" +
+			"\"DUPLICATE_VALUE\"|\"\"|\"A-1\"\r\n" +
+			"\"FIELD_INTEGRITY_EXCEPTION\"|\"0015\"|\"A-2\"\r\n"))
+	}))
+	defer server.Close()
+
+	connector, err := NewConnector(
+		WithAuthenticatedClient(http.DefaultClient),
+		WithWorkspace("test"),
+	)
+	if err != nil {
+		t.Fatalf("error in test while constructing connector %v", err)
+	}
+
+	// for testing we want to redirect calls to our mock server
+	connector.BaseURL = server.URL + "/services/data/" + APIVersion()
+	connector.Client.HTTPClient.Base = server.URL
+
+	result, err := connector.getPartialFailureDetails(context.Background(), &GetJobInfoResult{
+		Id:                  "750",
+		Operation:           "upsert",
+		ExternalIdFieldName: "External_Id__c",
+		ColumnDelimiter:     "PIPE",
+		LineEnding:          "CRLF",
+	})
+	if err != nil {
+		t.Fatalf("failed to get failure details: %v", err)
+	}
+
+	expectedCreates := map[string][]string{"DUPLICATE_VALUE": {"A-1"}}
+	expectedUpdates := map[string][]string{"FIELD_INTEGRITY_EXCEPTION": {"A-2"}}
+
+	if !reflect.DeepEqual(result.FailureDetails.FailedCreates, expectedCreates) ||
+		!reflect.DeepEqual(result.FailureDetails.FailedUpdates, expectedUpdates) {
+		t.Fatalf("unexpected failure details: %v", result.FailureDetails)
+	}
+}
+
+func TestBulkWriteValidation(t *testing.T) { // nolint:funlen
+	t.Parallel()
+
+	responseDescribe := mockutils.DataFromFile(t, "metadata-describe-account.json")
+
+	var (
+		mut      sync.Mutex
+		created  bool
+		uploaded bool
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		defer mut.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/composite"):
+			_, _ = w.Write(responseDescribe)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/jobs/ingest"):
+			created = true
+
+			_, _ = w.Write([]byte(`{"id": "750", "state": "Open"}`))
+		case strings.HasSuffix(r.URL.Path, "/jobs/ingest/750/batches"):
+			uploaded = true
+
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusTeapot)
+		}
+	}))
+	defer server.Close()
+
+	connector, err := NewConnector(
+		WithAuthenticatedClient(http.DefaultClient),
+		WithWorkspace("test"),
+	)
+	if err != nil {
+		t.Fatalf("error in test while constructing connector %v", err)
+	}
+
+	connector.BaseURL = server.URL + "/services/data/" + APIVersion()
+	connector.Client.HTTPClient.Base = server.URL
+
+	// Column is not a field of Account.
+	_, err = connector.BulkWrite(context.Background(), BulkOperationParams{
+		ObjectName:      "Account",
+		ExternalIdField: "External_Id__c",
+		CSVData:         strings.NewReader("Name,Unknown__c,Owner.Email\nAcme,A-1,a@example.com\n"),
+		Mode:            Upsert,
+		Validate:        true,
+	})
+
+	var validationErr *common.ValidationError
+	if !errors.As(err, &validationErr) || !errors.Is(err, common.ErrUnknownField) {
+		t.Fatalf("expected unknown field to be reported, got: %v", err)
+	}
+
+	if len(validationErr.Problems) != 1 || validationErr.Problems[0].Field != "Unknown__c" {
+		t.Fatalf("unexpected problems: %v", validationErr.Problems)
+	}
+
+	mut.Lock()
+	defer mut.Unlock()
+
+	if created || uploaded {
+		t.Fatal("job must not be created for invalid CSV")
+	}
+}
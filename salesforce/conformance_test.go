@@ -0,0 +1,61 @@
+package salesforce
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/test/conformance"
+	"github.com/amp-labs/connectors/test/utils/mockutils"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	responseReadAccounts := mockutils.DataFromFile(t, "read-accounts.json")
+	responseCreateAccount := mockutils.DataFromFile(t, "write-create-account.json")
+
+	conformance.Run(t, conformance.Suite{
+		NewConnector: func(serverURL string) (any, error) {
+			connector, err := NewConnector(
+				WithAuthenticatedClient(http.DefaultClient),
+				WithWorkspace("test"),
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			// for testing we want to redirect calls to our mock server
+			connector.BaseURL = serverURL + "/services/data/" + APIVersion()
+			connector.Client.HTTPClient.Base = serverURL
+
+			return connector, nil
+		},
+		Read: &conformance.ReadSpec{
+			Params: common.ReadParams{
+				ObjectName: "Account",
+				Fields:     []string{"Id", "Name", "BillingCity"},
+			},
+			Pages: []conformance.Response{
+				conformance.JSON(responseReadAccounts),
+			},
+			ExpectedRows: 2,
+			Since: &conformance.SinceSpec{
+				Timestamp: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+				IsFiltered: func(req *http.Request, since time.Time) bool {
+					return strings.Contains(req.URL.Query().Get("q"), "SystemModstamp > 2024-05-01T10:00:00Z")
+				},
+			},
+		},
+		Write: &conformance.WriteSpec{
+			Params: common.WriteParams{
+				ObjectName: "Account",
+				RecordData: map[string]any{"Name": "Edge Communications"},
+			},
+			Response: conformance.JSON(responseCreateAccount),
+		},
+		Errors: conformance.StandardErrorCases([]byte(`[{"message": "Session expired", "errorCode": "UNKNOWN"}]`)),
+	})
+}
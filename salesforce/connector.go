@@ -22,6 +22,11 @@ type Connector struct {
 	Domain  string
 	BaseURL string
 	Client  *common.JSONHTTPClient
+
+	// metadata is used to expand wildcard fields and to validate writes.
+	metadata *common.MetadataCache
+	// idempotency remembers records created with an idempotency key.
+	idempotency common.IdempotencyStore
 }
 
 func APIVersion() string {
@@ -80,11 +85,14 @@ func NewConnector(opts ...Option) (conn *Connector, outErr error) {
 		BaseURL: restApi,
 		Domain:  domain,
 		Client:  params.client,
+
+		idempotency: params.IdempotencyStore(),
 	}
 
 	conn.Client.HTTPClient.Base = providerInfo.BaseURL
 	conn.Client.HTTPClient.ErrorHandler = conn.interpretError
 	conn.Client.ErrorPostProcessor.Process = handleError
+	conn.metadata = common.NewMetadataCache(conn.ListObjectMetadata)
 
 	return conn, nil
 }
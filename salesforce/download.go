@@ -0,0 +1,47 @@
+package salesforce
+
+import (
+	"context"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/common/jsonquery"
+)
+
+// DownloadContentVersion streams the binary data of a ContentVersion, which is a Salesforce file.
+// File name is taken from the PathOnClient field, when the response doesn't suggest one.
+// The caller must close the body.
+func (c *Connector) DownloadContentVersion(ctx context.Context, contentVersionID string) (*common.Download, error) {
+	location, err := joinURLPath(c.BaseURL, "sobjects/ContentVersion", contentVersionID)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := c.Client.Get(ctx, location+"?fields=PathOnClient")
+	if err != nil {
+		return nil, err
+	}
+
+	var fileName string
+	if rsp != nil && rsp.Body != nil {
+		fileName, err = jsonquery.New(rsp.Body).StrWithDefault("PathOnClient", "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dataLocation, err := joinURLPath(location, "VersionData")
+	if err != nil {
+		return nil, err
+	}
+
+	download, err := c.Client.Download(ctx, dataLocation)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(download.FileName) == 0 {
+		download.FileName = fileName
+	}
+
+	return download, nil
+}
@@ -0,0 +1,54 @@
+package salesforce
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDownloadContentVersion(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/VersionData") {
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write([]byte("%PDF-1.4 content"))
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"Id": "0681", "PathOnClient": "contract.pdf"}`))
+	}))
+	defer server.Close()
+
+	connector, err := NewConnector(
+		WithAuthenticatedClient(http.DefaultClient),
+		WithWorkspace("test"),
+	)
+	if err != nil {
+		t.Fatalf("error in test while constructing connector %v", err)
+	}
+
+	// for testing we want to redirect calls to our mock server
+	connector.BaseURL = server.URL + "/services/data/" + APIVersion()
+	connector.Client.HTTPClient.Base = server.URL
+
+	download, err := connector.DownloadContentVersion(context.Background(), "0681")
+	if err != nil {
+		t.Fatalf("failed to download: %v", err)
+	}
+	defer download.Body.Close()
+
+	if download.FileName != "contract.pdf" || download.ContentType != "application/pdf" {
+		t.Fatalf("unexpected file details: %v, %v", download.FileName, download.ContentType)
+	}
+
+	data, err := io.ReadAll(download.Body)
+	if err != nil || string(data) != "%PDF-1.4 content" {
+		t.Fatalf("unexpected file content: %q, %v", data, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"mime"
 	"net/http"
 	"net/url"
 
@@ -33,9 +34,25 @@ func (c *Connector) interpretError(res *http.Response, body []byte) error {
 		return c.interpretJSONError(res, body)
 	}
 
+	if mimeType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type")); mimeType == "text/xml" {
+		return interpretSOAPError(res, body)
+	}
+
 	return common.InterpretError(res, body)
 }
 
+// interpretSOAPError handles faults of SOAP APIs, ex: Metadata API.
+func interpretSOAPError(res *http.Response, body []byte) error {
+	err := common.InterpretSOAPError(res, body)
+
+	var fault *common.SOAPFault
+	if errors.As(err, &fault) && fault.HasCode("INVALID_SESSION_ID") {
+		return errors.Join(common.ErrInvalidSessionId, err)
+	}
+
+	return err
+}
+
 func createError(baseErr error, sfErr jsonError) error {
 	if len(sfErr.Message) > 0 {
 		return fmt.Errorf("%w: %s", baseErr, sfErr.Message)
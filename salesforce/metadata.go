@@ -90,6 +90,7 @@ func constructResponseMap(result *common.JSONHTTPResponse) (*common.ListObjectMe
 				DisplayName: result.Label,
 				// Map that satisfies type constraint
 				FieldsMap: makeFieldsMap(result.Fields),
+				Fields:    makeFieldsMetadata(result.Fields),
 			}
 		}
 	}
@@ -111,6 +112,67 @@ func makeFieldsMap(fields []fieldResult) map[string]string {
 	return fieldsMap
 }
 
+// makeFieldsMetadata describes field types, required and read-only flags, and picklist values.
+func makeFieldsMetadata(fields []fieldResult) map[string]common.FieldMetadata {
+	fieldsMetadata := make(map[string]common.FieldMetadata)
+
+	for _, field := range fields {
+		values := make([]string, 0, len(field.PicklistValues))
+
+		for _, picklistValue := range field.PicklistValues {
+			if picklistValue.Active {
+				values = append(values, picklistValue.Value)
+			}
+		}
+
+		if len(values) == 0 {
+			values = nil
+		}
+
+		// Record id and external ids identify records of updates and upserts, so they are accepted in record data.
+		isKey := field.Type == "id" || field.ExternalId
+
+		fieldsMetadata[strings.ToLower(field.Name)] = common.FieldMetadata{
+			Name:      field.Name,
+			ValueType: convertFieldType(field.Type),
+			// Required on create unless Salesforce will fill it in.
+			Required:   field.Createable && !field.Nillable && !field.DefaultedOnCreate,
+			ReadOnly:   !isKey && !field.Createable && !field.Updateable,
+			CreateOnly: !isKey && field.Createable && !field.Updateable,
+			Values:     values,
+		}
+	}
+
+	return fieldsMetadata
+}
+
+// convertFieldType maps Salesforce field type to the common value type.
+// See https://developer.salesforce.com/docs/atlas.en-us.api.meta/api/field_types.htm
+func convertFieldType(fieldType string) common.ValueType { // nolint:cyclop
+	switch fieldType {
+	case "string", "textarea", "id", "reference", "email", "phone", "url", "encryptedstring", "combobox":
+		return common.ValueTypeString
+	case "boolean":
+		return common.ValueTypeBoolean
+	case "int", "long":
+		return common.ValueTypeInt
+	case "double", "currency", "percent":
+		return common.ValueTypeFloat
+	case "date":
+		return common.ValueTypeDate
+	case "datetime":
+		return common.ValueTypeDateTime
+	case "picklist":
+		return common.ValueTypeSingleSelect
+	case "multipicklist":
+		return common.ValueTypeMultiSelect
+	case "address", "location":
+		return common.ValueTypeObject
+	default:
+		return common.ValueTypeOther
+	}
+}
+
 type compositeRequest struct {
 	AllOrNone        bool                   `json:"allOrNone"`
 	CompositeRequest []compositeRequestItem `json:"compositeRequest"`
@@ -150,6 +212,19 @@ type describeSObjectResult struct {
 //
 //nolint:lll
 type fieldResult struct {
-	Name  string `json:"name"`
-	Label string `json:"label"`
+	Name              string           `json:"name"`
+	Label             string           `json:"label"`
+	Type              string           `json:"type"`
+	Nillable          bool             `json:"nillable"`
+	Createable        bool             `json:"createable"`
+	Updateable        bool             `json:"updateable"`
+	DefaultedOnCreate bool             `json:"defaultedOnCreate"`
+	ExternalId        bool             `json:"externalId"`
+	PicklistValues    []picklistResult `json:"picklistValues"`
+}
+
+type picklistResult struct {
+	Value  string `json:"value"`
+	Label  string `json:"label"`
+	Active bool   `json:"active"`
 }
@@ -1,15 +1,9 @@
 package salesforce
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"io"
-	"log/slog"
-	"net/http"
-	"net/url"
-	"strings"
 
 	"github.com/amp-labs/connectors/common"
 	"github.com/subchen/go-xmldom"
@@ -22,118 +16,70 @@ var (
 	ErrBadRequest      = errors.New("bad request")
 )
 
+// CreateMetadata calls Metadata API with the operation and returns the whole SOAP response envelope,
+// ex: <soapenv:Envelope>...</soapenv:Envelope>. Faults are returned as errors.
+// Metadata API takes the access token in the session header, so tok must be kept up to date, see GetTokenUpdater.
 func (c *Connector) CreateMetadata(
 	ctx context.Context,
 	metadata *xmldom.Node,
 	tok *oauth2.Token,
 ) (string, error) {
-	req, err := c.prepareXMLRequest(ctx, metadata, tok)
-	if err != nil {
-		return "", err
-	}
+	sent := *tok
 
-	res, body, err := c.makeRequest(req) //nolint:bodyclose
-	// below is a workaround to refresh token if it is expired
-	// normally oauth2 library should handle this
-	// but SOAP API does not take token in header
-	// but takes it in body
-	// So in case of 500 error and INVALID_SESSION_ID in body
-	// we know it is session expired, and automatically refresh the token
-	// tok object will be updated with new token automatically after failing first call
-	// we simply make another call with updated token.
-	if res.StatusCode == 500 && strings.Contains(string(body), "INVALID_SESSION_ID") {
-		req, err = c.prepareXMLRequest(ctx, metadata, tok)
+	body, err := c.callMetadata(ctx, metadata, tok)
+	// SOAP API does not take token in header but in body,
+	// so the OAuth client cannot tell the token was rejected and won't refresh it.
+	// In case of INVALID_SESSION_ID fault we know the session has expired,
+	// so we make the client refresh the token and call again with the new one.
+	if errors.Is(err, common.ErrInvalidSessionId) {
+		var refreshed *oauth2.Token
+
+		refreshed, err = common.RefreshToken(ctx, c.Client.HTTPClient.Client, &sent)
 		if err != nil {
-			return "", errors.Join(ErrCreateMetadata, err)
+			return "", fmt.Errorf("%w: %w", ErrCreateMetadata, err)
 		}
-		//nolint:bodyclose,ineffassign,staticcheck,wastedassign
-		res, body, err = c.makeRequest(req)
-	}
 
-	if err != nil {
-		return "", fmt.Errorf("%w: %s", errors.Join(ErrCreateMetadata, err), string(body))
+		tok.AccessToken = refreshed.AccessToken
+		tok.RefreshToken = refreshed.RefreshToken
+		tok.TokenType = refreshed.TokenType
+		tok.Expiry = refreshed.Expiry
+
+		body, err = c.callMetadata(ctx, metadata, tok)
 	}
 
-	if res.StatusCode < 200 || res.StatusCode > 299 {
-		return "", fmt.Errorf("%w: %s", ErrCreateMetadata, string(body))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrCreateMetadata, err)
 	}
 
-	return string(body), nil
+	return body.Root().XML(), nil
 }
 
-func (c *Connector) prepareXMLRequest(
+func (c *Connector) callMetadata(
 	ctx context.Context,
 	metadata *xmldom.Node,
 	tok *oauth2.Token,
-) (*http.Request, error) {
-	data := preparePayload(metadata, tok.AccessToken)
-
-	endPointURL, err := url.JoinPath(c.Client.HTTPClient.Base, "services/Soap/m/"+APIVersionSOAP())
-	if err != nil {
-		return nil, errors.Join(ErrCreatingRequest, err)
-	}
-
-	byteData := []byte(data)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endPointURL, bytes.NewBuffer(byteData))
-	if err != nil {
-		return nil, errors.Join(ErrCreatingRequest, err)
-	}
-
-	addSOAPHeaders(req)
-	req.ContentLength = int64(len(byteData))
-
-	return req, nil
-}
-
-func (c *Connector) makeRequest(req *http.Request) (*http.Response, []byte, error) {
-	res, err := c.Client.HTTPClient.Client.Do(req)
-	if err != nil {
-		return res, nil, err
-	}
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return res, nil, err
+) (*xmldom.Node, error) {
+	client := &common.SOAPClient{
+		XMLClient: &common.XMLHTTPClient{
+			HTTPClient:         c.Client.HTTPClient,
+			ErrorPostProcessor: c.Client.ErrorPostProcessor,
+		},
+		Namespace: "http://soap.sforce.com/2006/04/metadata",
+		Headers: []common.SOAPHeaderFunc{
+			func(context.Context) (*xmldom.Node, error) {
+				return getAllOrNoneHeader(true), nil
+			},
+			func(context.Context) (*xmldom.Node, error) {
+				// Token is read on every call, it may have been refreshed.
+				return getSessionHeader(tok.AccessToken), nil
+			},
+		},
 	}
 
-	defer func() {
-		if res != nil && res.Body != nil {
-			if closeErr := res.Body.Close(); closeErr != nil {
-				slog.Warn("unable to close response body", "error", closeErr)
-			}
-		}
-	}()
-
-	return res, body, nil
-}
-
-func addSOAPHeaders(req *http.Request) {
-	req.Header.Add("Content-Type", "text/xml")
 	// SOAP API definition specifies that SOAPAction header should be empty string
 	// but if we set to "", API will error
 	// so we use "''" instead as workaround
-	req.Header.Set("SOAPAction", "''")
-}
-
-func getEnvelope(header *xmldom.Node, body *xmldom.Node) *xmldom.Document {
-	envelop := xmldom.NewDocument("soapenv:Envelope")
-	envelop.Root.SetAttributeValue("xmlns:soapenv", "http://schemas.xmlsoap.org/soap/envelope/")
-	envelop.Root.SetAttributeValue("xmlns:xsd", "http://www.w3.org/2001/XMLSchema")
-	envelop.Root.SetAttributeValue("xmlns:xsi", "http://www.w3.org/2001/XMLSchema-instance")
-	envelop.Root.Children = []*xmldom.Node{header, body}
-
-	return envelop
-}
-
-func getHeader(headers ...*xmldom.Node) *xmldom.Node {
-	header := &xmldom.Node{
-		Name:     "soapenv:Header",
-		Children: headers,
-	}
-	header.SetAttributeValue("xmlns", "http://soap.sforce.com/2006/04/metadata")
-
-	return header
+	return client.Call(ctx, "services/Soap/m/"+APIVersionSOAP(), "''", metadata)
 }
 
 func getSessionHeader(token string) *xmldom.Node {
@@ -151,31 +97,6 @@ func getSessionHeader(token string) *xmldom.Node {
 	return header
 }
 
-func getBody(items ...*xmldom.Node) *xmldom.Node {
-	body := &xmldom.Node{
-		Name: "soapenv:Body",
-		Attributes: []*xmldom.Attribute{
-			{
-				Name:  "xmlns",
-				Value: "http://soap.sforce.com/2006/04/metadata",
-			},
-		},
-		Children: items,
-	}
-
-	return body
-}
-
-func preparePayload(metadata *xmldom.Node, accessToken string) string {
-	sessionHeader := getSessionHeader(accessToken)
-	allOrNonHeader := getAllOrNoneHeader(true)
-	header := getHeader(allOrNonHeader, sessionHeader)
-	body := getBody(metadata)
-	envelop := getEnvelope(header, body)
-
-	return envelop.XML()
-}
-
 type BoolString string
 
 const (
@@ -0,0 +1,80 @@
+package salesforce
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/subchen/go-xmldom"
+	"golang.org/x/oauth2"
+)
+
+func TestCreateMetadataRefreshesSession(t *testing.T) {
+	t.Parallel()
+
+	refreshes := 0
+	calls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			refreshes++
+
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token": "refreshed", "token_type": "Bearer"}`))
+
+			return
+		}
+
+		calls++
+
+		data, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+
+		if !strings.Contains(string(data), "<sessionId>refreshed</sessionId>") {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/">` +
+				`<soapenv:Body><soapenv:Fault><faultcode>sf:INVALID_SESSION_ID</faultcode>` +
+				`<faultstring>Invalid Session ID found</faultstring></soapenv:Fault></soapenv:Body></soapenv:Envelope>`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/">` +
+			`<soapenv:Body><createMetadataResponse><result><success>true</success></result>` +
+			`</createMetadataResponse></soapenv:Body></soapenv:Envelope>`))
+	}))
+	defer server.Close()
+
+	// Token is still valid as far as the OAuth client knows, only the SOAP fault tells it was revoked.
+	tok := &oauth2.Token{AccessToken: "revoked", RefreshToken: "refresh", TokenType: "Bearer"}
+
+	connector, err := NewConnector(
+		WithClient(context.Background(), http.DefaultClient,
+			&oauth2.Config{Endpoint: oauth2.Endpoint{TokenURL: server.URL + "/token"}}, tok),
+		WithWorkspace("test"),
+	)
+	if err != nil {
+		t.Fatalf("error in test while constructing connector %v", err)
+	}
+
+	// for testing we want to redirect calls to our mock server
+	connector.Client.HTTPClient.Base = server.URL
+
+	result, err := connector.CreateMetadata(context.Background(), &xmldom.Node{Name: "createMetadata"}, tok)
+	if err != nil {
+		t.Fatalf("failed to create metadata: %v", err)
+	}
+
+	if calls != 2 || refreshes != 1 || tok.AccessToken != "refreshed" {
+		t.Fatalf("expected retry with refreshed session, calls: %v, refreshes: %v", calls, refreshes)
+	}
+
+	expected := "<createMetadataResponse><result><success>true</success></result></createMetadataResponse>"
+	if !strings.Contains(result, "Envelope") || !strings.Contains(result, expected) {
+		t.Fatalf("expected envelope with %v, got %v", expected, result)
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"net/http"
 
 	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/common/paramsbuilder"
 	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/oauth2/jwt"
 )
 
 // Option is a function which mutates the salesforce connector configuration.
@@ -31,6 +34,51 @@ func WithClient(ctx context.Context, client *http.Client, config *oauth2.Config,
 	}
 }
 
+// WithClientCredentialsClient authenticates as the integration user of the connected app,
+// using client credentials flow. Token URL is "https://<workspace>.my.salesforce.com/services/oauth2/token".
+func WithClientCredentialsClient(ctx context.Context, client *http.Client, config *clientcredentials.Config,
+	opts ...common.OAuthOption,
+) Option {
+	return func(params *sfParams) {
+		options := []common.OAuthOption{
+			common.WithOAuthClient(client),
+			common.WithClientCredentials(config),
+		}
+
+		oauthClient, err := common.NewOAuthHTTPClient(ctx, append(options, opts...)...)
+		if err != nil {
+			panic(err) // caught in NewConnector
+		}
+
+		WithAuthenticatedClient(oauthClient)(params)
+	}
+}
+
+// WithJWTBearerClient authenticates server-to-server using OAuth 2.0 JWT bearer flow.
+// Email is the consumer key of the connected app, Subject is the username, Audience is
+// "https://login.salesforce.com" or "https://test.salesforce.com" for sandboxes.
+// Salesforce rejects assertions valid for more than 3 minutes, common.DefaultJWTAssertionExpiry is used when
+// config.Expires is zero.
+// Salesforce doesn't report when the token expires, so a new one is obtained every common.DefaultGrantTokenLifetime,
+// the shortest session timeout Salesforce allows. Pass common.WithGrantTokenLifetime to match the org settings.
+func WithJWTBearerClient(ctx context.Context, client *http.Client, config *jwt.Config,
+	opts ...common.OAuthOption,
+) Option {
+	return func(params *sfParams) {
+		options := []common.OAuthOption{
+			common.WithOAuthClient(client),
+			common.WithJWTBearer(config),
+		}
+
+		oauthClient, err := common.NewOAuthHTTPClient(ctx, append(options, opts...)...)
+		if err != nil {
+			panic(err) // caught in NewConnector
+		}
+
+		WithAuthenticatedClient(oauthClient)(params)
+	}
+}
+
 // WithAuthenticatedClient sets the http client to use for the connector. Its usage is optional.
 func WithAuthenticatedClient(client common.AuthenticatedHTTPClient) Option {
 	return func(params *sfParams) {
@@ -50,8 +98,17 @@ func WithWorkspace(workspaceRef string) Option {
 	}
 }
 
+// WithIdempotencyStore sets the store of paramsbuilder.Idempotency. Its usage is optional.
+func WithIdempotencyStore(store common.IdempotencyStore) Option {
+	return func(params *sfParams) {
+		params.WithIdempotencyStore(store)
+	}
+}
+
 // sfParams is the internal configuration for the salesforce connector.
 type sfParams struct {
+	paramsbuilder.Interceptors
+	paramsbuilder.Idempotency
 	client    *common.JSONHTTPClient // required
 	workspace string                 // required
 }
@@ -66,5 +123,21 @@ func (p *sfParams) prepare() (out *sfParams, err error) {
 		return nil, ErrMissingWorkspace
 	}
 
+	p.ApplyInterceptors(p.client.HTTPClient)
+
 	return p, nil
 }
+
+// WithInterceptors adds middleware of paramsbuilder.Interceptors. Its usage is optional.
+func WithInterceptors(interceptors ...common.Interceptor) Option {
+	return func(params *sfParams) {
+		params.WithInterceptors(interceptors...)
+	}
+}
+
+// WithCircuitBreaker sets the breaker of paramsbuilder.Interceptors. Its usage is optional.
+func WithCircuitBreaker(breaker *common.CircuitBreaker) Option {
+	return func(params *sfParams) {
+		params.WithCircuitBreaker(breaker)
+	}
+}
@@ -3,6 +3,7 @@ package salesforce
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/url"
 	"strings"
 
@@ -11,12 +12,39 @@ import (
 
 // Read reads data from Salesforce. By default it will read all rows (backfill). However, if Since is set,
 // it will read only rows that have been updated since the specified time.
+//
+// Wildcard is read as FIELDS(ALL) first, which SOQL allows only for a single page of at most 200 records.
+// When the page is full, there may be more records, so they are read again with the wildcard
+// expanded into explicit fields using metadata.
 func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common.ReadResult, error) {
+	if common.HasWildcardField(config.Fields) && len(config.NextPage) == 0 {
+		result, err := c.readPage(ctx, config)
+		if err != nil || len(result.Data) < fieldsAllLimit {
+			return result, err
+		}
+	}
+
+	var err error
+
+	// Wildcard of the next pages was expanded when the first page was read.
+	config.Fields, err = c.metadata.ReadFields(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.readPage(ctx, config)
+}
+
+func (c *Connector) readPage(ctx context.Context, config common.ReadParams) (*common.ReadResult, error) {
 	var (
-		rsp *common.JSONHTTPResponse
-		err error
+		body io.ReadCloser
+		err  error
 	)
 
+	if isChunkedPage(config.NextPage) {
+		return c.readChunked(ctx, config)
+	}
+
 	if len(config.NextPage) > 0 {
 		// If NextPage is set, then we're reading the next page of results.
 		// All that matters is the NextPage URL, the fields are ignored.
@@ -25,7 +53,7 @@ func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common
 			return nil, joinErr
 		}
 
-		rsp, err = c.Client.Get(ctx, location)
+		body, err = c.Client.GetStream(ctx, location)
 	} else {
 		// If NextPage is not set, then we're reading the first page of results.
 		// We need to construct the SOQL query and then make the request.
@@ -34,32 +62,49 @@ func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common
 			return nil, soqlErr
 		}
 
-		// Encode the SOQL query as a URL parameter
-		qp := url.Values{}
-		qp.Add("q", soql)
+		location, locationErr := c.getQueryURL(soql)
+		if locationErr != nil {
+			return nil, locationErr
+		}
 
-		location, joinErr := url.JoinPath(c.BaseURL, "/query/")
-		if joinErr != nil {
-			return nil, joinErr
+		if len(location) > maxURLLength {
+			// Too many fields to fit into one query, they are read in several queries.
+			return c.readChunked(ctx, config)
 		}
 
-		rsp, err = c.Client.Get(ctx, location+"?"+qp.Encode())
+		body, err = c.Client.GetStream(ctx, location)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	return common.ParseResult(
-		rsp,
+	// Pages may be large, especially with every field requested, records are decoded as they arrive.
+	decoder := common.NewJSONRecordDecoder(body, "records")
+	defer decoder.Close()
+
+	return common.ParseStreamResult(
+		decoder,
 		getTotalSize,
-		getRecords,
 		getNextRecordsURL,
-		getMarshaledData,
+		getMarshaledData(common.FieldsExtractorFor(config)),
 		config.Fields,
 	)
 }
 
+// getQueryURL returns the URL of the query endpoint with the SOQL encoded as a URL parameter.
+func (c *Connector) getQueryURL(soql string) (string, error) {
+	qp := url.Values{}
+	qp.Add("q", soql)
+
+	location, err := url.JoinPath(c.BaseURL, "/query/")
+	if err != nil {
+		return "", err
+	}
+
+	return location + "?" + qp.Encode(), nil
+}
+
 // makeSOQL returns the SOQL query for the desired read operation.
 func makeSOQL(config common.ReadParams) (string, error) {
 	// Make sure we have at least one field
@@ -67,37 +112,49 @@ func makeSOQL(config common.ReadParams) (string, error) {
 		return "", ErrNoFields
 	}
 
-	// Get the field set in SOQL format
-	fields := getFieldSet(config.Fields)
+	soql := makeQuery(getFieldSet(config.Fields), config, "")
+
+	if common.HasWildcardField(config.Fields) {
+		soql += fmt.Sprintf(" LIMIT %d", fieldsAllLimit)
+	}
+
+	return soql, nil
+}
 
-	hasWhere := false
-	soql := fmt.Sprintf("SELECT %s FROM %s", fields, config.ObjectName)
+// soqlQuoteEscaper escapes a value placed inside single quotes.
+var soqlQuoteEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`) // nolint:gochecknoglobals
+
+// makeQuery builds SOQL selecting the field set, optionally starting after the record id.
+func makeQuery(fieldSet string, config common.ReadParams, afterID string) string {
+	conditions := make([]string, 0, 3) // nolint:gomnd
 
 	// If Since is not set, then we're doing a backfill. We read all rows (in pages)
 	if !config.Since.IsZero() {
-		soql += " WHERE SystemModstamp > " + config.Since.Format("2006-01-02T15:04:05Z")
-		hasWhere = true
+		conditions = append(conditions, "SystemModstamp > "+config.Since.Format("2006-01-02T15:04:05Z"))
 	}
 
 	if config.Deleted {
-		if !hasWhere {
-			soql += " WHERE"
-		} else {
-			soql += " AND"
-		}
+		conditions = append(conditions, "IsDeleted = true")
+	}
 
-		soql += " IsDeleted = true"
+	if afterID != "" {
+		conditions = append(conditions, "Id > '"+soqlQuoteEscaper.Replace(afterID)+"'")
 	}
 
-	return soql, nil
+	soql := fmt.Sprintf("SELECT %s FROM %s", fieldSet, config.ObjectName)
+
+	if len(conditions) != 0 {
+		soql += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return soql
 }
 
 // getFieldSet returns the field set in SOQL format.
+// Wildcard becomes FIELDS(ALL), which SOQL only allows with at most 200 records.
 func getFieldSet(fields []string) string {
-	for _, field := range fields {
-		if field == "*" {
-			return "FIELDS(ALL)"
-		}
+	if common.HasWildcardField(fields) {
+		return "FIELDS(ALL)"
 	}
 
 	return strings.Join(fields, ",")
@@ -0,0 +1,157 @@
+package salesforce
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/amp-labs/connectors/common"
+)
+
+const (
+	// fieldsAllLimit is the most records SOQL returns for FIELDS(ALL).
+	fieldsAllLimit = 200
+	// maxURLLength is the longest request URI Salesforce accepts.
+	// See https://developer.salesforce.com/docs/atlas.en-us.salesforce_app_limits_cheatsheet.meta/salesforce_app_limits_cheatsheet/salesforce_app_limits_platform_api.htm
+	maxURLLength = 16384
+	// chunkPageSize is the number of records read by one chunked page.
+	chunkPageSize = 2000
+	// chunkedPagePrefix marks NextPage of chunked reads, it is followed by the last read record id.
+	chunkedPagePrefix = "after:"
+)
+
+func isChunkedPage(nextPage common.NextPageToken) bool {
+	return strings.HasPrefix(nextPage.String(), chunkedPagePrefix)
+}
+
+// readChunked reads records when the field list is too long for a single query URL.
+// Fields are split into several queries, each ordered by record id and limited to the same page,
+// the rows are then merged by id. Pages continue after the last id, so unlike regular reads,
+// the next page must be requested with the same ReadParams.
+func (c *Connector) readChunked(ctx context.Context, config common.ReadParams) (*common.ReadResult, error) {
+	afterID := strings.TrimPrefix(config.NextPage.String(), chunkedPagePrefix)
+
+	chunks, err := c.chunkFields(config, afterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		result *common.ReadResult
+		rows   map[string]int
+	)
+
+	for _, chunk := range chunks {
+		page, err := c.readChunk(ctx, config, chunk, afterID)
+		if err != nil {
+			return nil, err
+		}
+
+		if result == nil {
+			result = page
+			rows = make(map[string]int, len(page.Data))
+
+			for index, row := range page.Data {
+				rows[recordID(row)] = index
+			}
+
+			continue
+		}
+
+		for _, row := range page.Data {
+			index, ok := rows[recordID(row)]
+			if !ok {
+				// Record changed between the queries, it is not part of this page.
+				continue
+			}
+
+			for key, value := range row.Fields {
+				result.Data[index].Fields[key] = value
+			}
+
+			for key, value := range row.Raw {
+				result.Data[index].Raw[key] = value
+			}
+		}
+	}
+
+	// Total size of a chunk is capped by its limit, the page has as many rows as were merged.
+	result.Rows = int64(len(result.Data))
+	result.NextPage = ""
+	result.Done = true
+
+	if len(result.Data) == chunkPageSize {
+		result.NextPage = common.NextPageToken(chunkedPagePrefix + recordID(result.Data[len(result.Data)-1]))
+		result.Done = false
+	}
+
+	return result, nil
+}
+
+func (c *Connector) readChunk(
+	ctx context.Context, config common.ReadParams, fields []string, afterID string,
+) (*common.ReadResult, error) {
+	location, err := c.getQueryURL(makeChunkSOQL(config, fields, afterID))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.Client.GetStream(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := common.NewJSONRecordDecoder(body, "records")
+	defer decoder.Close()
+
+	// Every chunk extracts all requested fields, those missing from the chunk are skipped.
+	return common.ParseStreamResult(
+		decoder,
+		getTotalSize,
+		getNextRecordsURL,
+		getMarshaledData(common.FieldsExtractorFor(config)),
+		config.Fields,
+	)
+}
+
+// chunkFields splits fields, so that every query URL stays within the limit. Each chunk selects Id.
+func (c *Connector) chunkFields(config common.ReadParams, afterID string) ([][]string, error) {
+	base, err := c.getQueryURL(makeChunkSOQL(config, []string{"Id"}, afterID))
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make([][]string, 0)
+	chunk := []string{"Id"}
+	length := len(base)
+
+	for _, field := range config.Fields {
+		if strings.EqualFold(field, "Id") {
+			continue
+		}
+
+		fieldLength := len(url.QueryEscape("," + field))
+
+		if length+fieldLength > maxURLLength && len(chunk) > 1 {
+			chunks = append(chunks, chunk)
+			chunk = []string{"Id"}
+			length = len(base)
+		}
+
+		chunk = append(chunk, field)
+		length += fieldLength
+	}
+
+	return append(chunks, chunk), nil
+}
+
+func makeChunkSOQL(config common.ReadParams, fields []string, afterID string) string {
+	return makeQuery(strings.Join(fields, ","), config, afterID) + fmt.Sprintf(" ORDER BY Id LIMIT %d", chunkPageSize)
+}
+
+func recordID(row common.ReadResultRow) string {
+	id, _ := row.Raw["Id"].(string)
+
+	return id
+}
@@ -0,0 +1,201 @@
+package salesforce
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/test/utils/mockutils"
+)
+
+func TestReadWildcardFields(t *testing.T) { // nolint:funlen
+	t.Parallel()
+
+	responseDescribe := mockutils.DataFromFile(t, "metadata-describe-account.json")
+	responseReadAccounts := mockutils.DataFromFile(t, "read-accounts.json")
+
+	var describeCalls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/composite"):
+			describeCalls.Add(1)
+			_, _ = w.Write(responseDescribe)
+		case r.URL.Query().Get("q") == "SELECT FIELDS(ALL) FROM Account LIMIT 200":
+			// Full page, there may be more records than FIELDS(ALL) can return.
+			records := make([]map[string]any, fieldsAllLimit)
+			for index := range records {
+				records[index] = map[string]any{"Id": fmt.Sprintf("001%03d", index)}
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{"totalSize": len(records), "done": true, "records": records})
+		case strings.HasSuffix(r.URL.Path, "/query/"):
+			if query := r.URL.Query().Get("q"); query != "SELECT billingcity,external_id__c,id,name FROM Account" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`[{"message": "unexpected query ` + query + `", "errorCode": "MALFORMED_QUERY"}]`))
+
+				return
+			}
+
+			_, _ = w.Write(responseReadAccounts)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	connector, err := NewConnector(
+		WithAuthenticatedClient(http.DefaultClient),
+		WithWorkspace("test"),
+	)
+	if err != nil {
+		t.Fatalf("error in test while constructing connector %v", err)
+	}
+
+	// for testing we want to redirect calls to our mock server
+	connector.BaseURL = server.URL + "/services/data/" + APIVersion()
+	connector.Client.HTTPClient.Base = server.URL
+
+	for i := 0; i < 2; i++ {
+		result, err := connector.Read(context.Background(), common.ReadParams{
+			ObjectName: "Account",
+			Fields:     []string{"*"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if city := result.Data[0].Fields["billingcity"]; city != "Austin" {
+			t.Fatalf("expected expanded field billingcity, got: %v", result.Data[0].Fields)
+		}
+	}
+
+	if calls := describeCalls.Load(); calls != 1 {
+		t.Fatalf("metadata must be described once and cached, got %v calls", calls)
+	}
+}
+
+func TestReadWildcardFieldsAll(t *testing.T) {
+	t.Parallel()
+
+	responseReadAccounts := mockutils.DataFromFile(t, "read-accounts.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch query := r.URL.Query().Get("q"); query {
+		case "SELECT FIELDS(ALL) FROM Account LIMIT 200":
+			_, _ = w.Write(responseReadAccounts)
+		default:
+			// Metadata must not be described for small results.
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`[{"message": "unexpected query ` + query + `", "errorCode": "MALFORMED_QUERY"}]`))
+		}
+	}))
+	defer server.Close()
+
+	connector, err := NewConnector(
+		WithAuthenticatedClient(http.DefaultClient),
+		WithWorkspace("test"),
+	)
+	if err != nil {
+		t.Fatalf("error in test while constructing connector %v", err)
+	}
+
+	connector.BaseURL = server.URL + "/services/data/" + APIVersion()
+	connector.Client.HTTPClient.Base = server.URL
+
+	result, err := connector.Read(context.Background(), common.ReadParams{
+		ObjectName: "Account",
+		Fields:     []string{"*"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if city := result.Data[1].Fields["billingcity"]; city != "Burlington" || !result.Done {
+		t.Fatalf("expected every field of a single page, got: %v", result)
+	}
+}
+
+func TestReadChunkedFields(t *testing.T) { // nolint:funlen
+	t.Parallel()
+
+	fields := make([]string, 0, 1500)
+	for i := 0; i < cap(fields); i++ {
+		fields = append(fields, fmt.Sprintf("Field_%04d__c", i))
+	}
+
+	var queries atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if len(r.URL.String()) > maxURLLength {
+			w.WriteHeader(http.StatusRequestURITooLong)
+
+			return
+		}
+
+		queries.Add(1)
+
+		// Every record has value of each selected field.
+		query := r.URL.Query().Get("q")
+		selected := strings.Split(strings.TrimPrefix(query[:strings.Index(query, " FROM ")], "SELECT "), ",")
+		records := make([]map[string]any, 0, 2)
+
+		for _, id := range []string{"001A", "001B"} {
+			record := map[string]any{"Id": id}
+			for _, field := range selected {
+				record[field] = id + "-" + field
+			}
+
+			records = append(records, record)
+		}
+
+		// Total size counts every matching record, not only those within the limit.
+		_ = json.NewEncoder(w).Encode(map[string]any{"totalSize": 5000, "done": true, "records": records})
+	}))
+	defer server.Close()
+
+	connector, err := NewConnector(
+		WithAuthenticatedClient(http.DefaultClient),
+		WithWorkspace("test"),
+	)
+	if err != nil {
+		t.Fatalf("error in test while constructing connector %v", err)
+	}
+
+	connector.BaseURL = server.URL + "/services/data/" + APIVersion()
+	connector.Client.HTTPClient.Base = server.URL
+
+	result, err := connector.Read(context.Background(), common.ReadParams{
+		ObjectName: "Account",
+		Fields:     fields,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if queries.Load() < 2 {
+		t.Fatalf("expected fields to be split into several queries, got %v", queries.Load())
+	}
+
+	if len(result.Data) != 2 || result.Rows != 2 || !result.Done {
+		t.Fatalf("expected 2 merged rows, got: %v", len(result.Data))
+	}
+
+	for _, field := range []string{fields[0], fields[len(fields)-1]} {
+		if value := result.Data[1].Fields[strings.ToLower(field)]; value != "001B-"+field {
+			t.Fatalf("expected merged field %v, got: %v", field, value)
+		}
+	}
+}
@@ -10,11 +10,19 @@ import (
 
 // Write will write data to Salesforce.
 func (c *Connector) Write(ctx context.Context, config common.WriteParams) (*common.WriteResult, error) {
+	return common.IdempotentWrite(ctx, c.idempotency, config, c.write)
+}
+
+func (c *Connector) write(ctx context.Context, config common.WriteParams) (*common.WriteResult, error) {
 	var (
 		rsp *common.JSONHTTPResponse
 		err error
 	)
 
+	if err = c.metadata.ValidateWrite(ctx, config); err != nil {
+		return nil, err
+	}
+
 	location, joinErr := url.JoinPath(c.BaseURL+"/sobjects", config.ObjectName)
 	if joinErr != nil {
 		return nil, joinErr
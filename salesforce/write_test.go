@@ -0,0 +1,120 @@
+package salesforce
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/test/utils/mockutils"
+)
+
+func TestWriteValidation(t *testing.T) {
+	t.Parallel()
+
+	responseDescribe := mockutils.DataFromFile(t, "metadata-describe-account.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if !strings.HasSuffix(r.URL.Path, "/composite") {
+			// Invalid record must never reach the provider.
+			w.WriteHeader(http.StatusTeapot)
+
+			return
+		}
+
+		_, _ = w.Write(responseDescribe)
+	}))
+	defer server.Close()
+
+	connector, err := NewConnector(
+		WithAuthenticatedClient(http.DefaultClient),
+		WithWorkspace("test"),
+	)
+	if err != nil {
+		t.Fatalf("error in test while constructing connector %v", err)
+	}
+
+	// for testing we want to redirect calls to our mock server
+	connector.BaseURL = server.URL + "/services/data/" + APIVersion()
+	connector.Client.HTTPClient.Base = server.URL
+
+	_, err = connector.Write(context.Background(), common.WriteParams{
+		ObjectName: "Account",
+		RecordData: map[string]any{"Id": "001", "BillingCity": 42, "Rating": "Hot"},
+		Validate:   true,
+	})
+
+	var validationErr *common.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error, got: (%v)", err)
+	}
+
+	expected := []error{common.ErrFieldType, common.ErrUnknownField, common.ErrRequiredField}
+	if len(validationErr.Problems) != len(expected) {
+		t.Fatalf("expected %v problems, got: (%v)", len(expected), err)
+	}
+
+	for index, expectedErr := range expected {
+		if !errors.Is(validationErr.Problems[index].Err, expectedErr) {
+			t.Fatalf("expected Error: (%v), got: (%v)", expectedErr, validationErr.Problems[index])
+		}
+	}
+
+	// Record keys are accepted by updates, even though Salesforce reports them as not updatable.
+	_, err = connector.Write(context.Background(), common.WriteParams{
+		ObjectName: "Account",
+		RecordId:   "001",
+		RecordData: map[string]any{"Id": "001", "External_Id__c": "A-1", "Name": "Acme"},
+		Validate:   true,
+	})
+	if errors.As(err, &validationErr) {
+		t.Fatalf("expected record keys to be accepted, got: (%v)", err)
+	}
+}
+
+func TestWriteDryRun(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Dry run must never reach the provider.
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer server.Close()
+
+	connector, err := NewConnector(
+		WithAuthenticatedClient(http.DefaultClient),
+		WithWorkspace("test"),
+	)
+	if err != nil {
+		t.Fatalf("error in test while constructing connector %v", err)
+	}
+
+	// for testing we want to redirect calls to our mock server
+	connector.BaseURL = server.URL + "/services/data/" + APIVersion()
+	connector.Client.HTTPClient.Base = server.URL
+
+	_, err = connector.Write(common.WithDryRun(context.Background()), common.WriteParams{
+		ObjectName: "Account",
+		RecordId:   "0015j00000YcPqBAAV",
+		RecordData: map[string]any{"Name": "Edge Communications"},
+	})
+
+	var dryRun *common.DryRunError
+	if !errors.As(err, &dryRun) {
+		t.Fatalf("expected dry run error, got: (%v)", err)
+	}
+
+	expectedURL := connector.BaseURL + "/sobjects/Account/0015j00000YcPqBAAV?_HttpMethod=PATCH"
+	if dryRun.Request.Method != http.MethodPost || dryRun.Request.URL != expectedURL {
+		t.Fatalf("unexpected request: %v %v", dryRun.Request.Method, dryRun.Request.URL)
+	}
+
+	if string(dryRun.Request.Body) != `{"Name":"Edge Communications"}` {
+		t.Fatalf("unexpected request body: %s", dryRun.Request.Body)
+	}
+}
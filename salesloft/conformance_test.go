@@ -0,0 +1,63 @@
+package salesloft
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/test/conformance"
+	"github.com/amp-labs/connectors/test/utils/mockutils"
+)
+
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	responseListPeople := mockutils.DataFromFile(t, "read-list-people.json")
+	responseListPeopleLastPage := mockutils.DataFromFile(t, "read-list-people-last-page.json")
+	responseCreateTask := mockutils.DataFromFile(t, "write-create-task.json")
+
+	conformance.Run(t, conformance.Suite{
+		NewConnector: func(serverURL string) (any, error) {
+			connector, err := NewConnector(
+				WithAuthenticatedClient(http.DefaultClient),
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			// for testing we want to redirect calls to our mock server
+			connector.setBaseURL(serverURL)
+
+			return connector, nil
+		},
+		Read: &conformance.ReadSpec{
+			Params: common.ReadParams{
+				ObjectName: "people",
+				Fields:     []string{"First_Name", "email_address"},
+			},
+			Pages: []conformance.Response{
+				conformance.JSON(responseListPeople),
+				conformance.JSON(responseListPeopleLastPage),
+			},
+			Since: &conformance.SinceSpec{
+				Timestamp: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+				IsFiltered: func(req *http.Request, since time.Time) bool {
+					return req.URL.Query().Get("updated_at[gte]") == "2024-05-01T10:00:00Z"
+				},
+			},
+		},
+		Write: &conformance.WriteSpec{
+			Params: common.WriteParams{
+				ObjectName: "tasks",
+				RecordData: map[string]any{"subject": "call me maybe"},
+			},
+			Response: conformance.JSON(responseCreateTask),
+		},
+		Delete: &conformance.DeleteSpec{
+			Params:   common.DeleteParams{ObjectName: "tasks", RecordId: "175204275"},
+			Response: conformance.Response{Status: http.StatusNoContent},
+		},
+		Errors: conformance.StandardErrorCases([]byte(`{"error": "Not Authorized"}`)),
+	})
+}
@@ -19,6 +19,11 @@ type Connector struct {
 	BaseURL string
 	Module  string
 	Client  *common.JSONHTTPClient
+
+	// metadata is used to expand wildcard fields and to validate writes.
+	metadata *common.MetadataCache
+	// idempotency remembers records created with an idempotency key.
+	idempotency common.IdempotencyStore
 }
 
 func NewConnector(opts ...Option) (conn *Connector, outErr error) {
@@ -38,8 +43,10 @@ func NewConnector(opts ...Option) (conn *Connector, outErr error) {
 	}
 
 	httpClient := params.Client.Caller
+	params.ApplyInterceptors(httpClient)
 	conn = &Connector{
-		Module: params.Module.Suffix,
+		Module:      params.Module.Suffix,
+		idempotency: params.IdempotencyStore(),
 		Client: &common.JSONHTTPClient{
 			HTTPClient: httpClient,
 		},
@@ -49,6 +56,7 @@ func NewConnector(opts ...Option) (conn *Connector, outErr error) {
 	conn.Client.HTTPClient.ErrorHandler = interpreter.ErrorHandler{
 		JSON: conn.interpretJSONError,
 	}.Handle
+	conn.metadata = common.NewMetadataCache(conn.ListObjectMetadata)
 
 	return conn, nil
 }
@@ -22,6 +22,8 @@ type Option func(params *parameters)
 type parameters struct {
 	paramsbuilder.Client
 	paramsbuilder.Module
+	paramsbuilder.Idempotency
+	paramsbuilder.Interceptors
 }
 
 func (p parameters) FromOptions(opts ...Option) (*parameters, error) {
@@ -37,6 +39,8 @@ func (p parameters) ValidateParams() error {
 	return errors.Join(
 		p.Client.ValidateParams(),
 		p.Module.ValidateParams(),
+		p.Idempotency.ValidateParams(),
+		p.Interceptors.ValidateParams(),
 	)
 }
 
@@ -59,3 +63,21 @@ func WithModule(module paramsbuilder.APIModule) Option {
 		params.WithModule(module)
 	}
 }
+
+func WithIdempotencyStore(store common.IdempotencyStore) Option {
+	return func(params *parameters) {
+		params.WithIdempotencyStore(store)
+	}
+}
+
+func WithInterceptors(interceptors ...common.Interceptor) Option {
+	return func(params *parameters) {
+		params.WithInterceptors(interceptors...)
+	}
+}
+
+func WithCircuitBreaker(breaker *common.CircuitBreaker) Option {
+	return func(params *parameters) {
+		params.WithCircuitBreaker(breaker)
+	}
+}
@@ -20,7 +20,9 @@ Response example:
 	      "per_page": 25,
 	      "current_page": 1,
 	      "next_page": 2,
-	      "prev_page": null
+	      "prev_page": null,
+	      "total_pages": 4,
+	      "total_count": 100
 	    },
 	    "sorting": {
 	      "sort_by": "updated_at",
@@ -29,8 +31,19 @@ Response example:
 	  },
 	  "data": [...]
 	}
+
+Paging counts are included on request, resources without pagination report only the size of the list.
 */
 func getTotalSize(node *ajson.Node) (int64, error) {
+	total, err := jsonquery.New(node, "metadata", "paging").Integer("total_count", true)
+	if err != nil && !errors.Is(err, jsonquery.ErrKeyNotFound) {
+		return 0, err
+	}
+
+	if total != nil {
+		return *total, nil
+	}
+
 	return jsonquery.New(node).ArraySize("data")
 }
 
@@ -67,15 +80,17 @@ func makeNextRecordsURL(reqLink *urlbuilder.URL) common.NextPageFunc {
 	}
 }
 
-func getMarshaledData(records []map[string]interface{}, fields []string) ([]common.ReadResultRow, error) {
-	data := make([]common.ReadResultRow, len(records))
+func getMarshaledData(extractFields common.FieldsExtractor) common.MarshalFunc {
+	return func(records []map[string]interface{}, fields []string) ([]common.ReadResultRow, error) {
+		data := make([]common.ReadResultRow, len(records))
 
-	for i, record := range records {
-		data[i] = common.ReadResultRow{
-			Fields: common.ExtractLowercaseFieldsFromRaw(fields, record),
-			Raw:    record,
+		for i, record := range records {
+			data[i] = common.ReadResultRow{
+				Fields: extractFields(fields, record),
+				Raw:    record,
+			}
 		}
-	}
 
-	return data, nil
+		return data, nil
+	}
 }
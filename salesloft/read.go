@@ -3,12 +3,20 @@ package salesloft
 import (
 	"context"
 	"strconv"
+	"time"
 
 	"github.com/amp-labs/connectors/common"
 	"github.com/amp-labs/connectors/common/urlbuilder"
 )
 
 func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common.ReadResult, error) {
+	fields, err := c.metadata.ReadFields(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Fields = fields
+
 	link, err := c.buildReadURL(config)
 	if err != nil {
 		return nil, err
@@ -24,7 +32,7 @@ func (c *Connector) Read(ctx context.Context, config common.ReadParams) (*common
 		getTotalSize,
 		getRecords,
 		makeNextRecordsURL(link),
-		getMarshaledData,
+		getMarshaledData(common.FieldsExtractorFor(config)),
 		config.Fields,
 	)
 }
@@ -42,6 +50,13 @@ func (c *Connector) buildReadURL(config common.ReadParams) (*urlbuilder.URL, err
 	}
 
 	link.WithQueryParam("per_page", strconv.Itoa(DefaultPageSize))
+	// Total is reported only when requested.
+	link.WithQueryParam("include_paging_counts", "true")
+
+	if !config.Since.IsZero() {
+		// Next pages are built from this link, so the filter is kept.
+		link.WithQueryParam("updated_at[gte]", config.Since.UTC().Format(time.RFC3339))
+	}
 
 	return link, nil
 }
@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/amp-labs/connectors/common"
 	"github.com/amp-labs/connectors/common/interpreter"
@@ -87,6 +88,53 @@ func TestRead(t *testing.T) { //nolint:funlen,gocognit,cyclop,maintidx
 			},
 			expectedErrs: nil,
 		},
+		{
+			name: "Since is sent as updated_at filter in UTC",
+			input: common.ReadParams{
+				Since: time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+			},
+			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("updated_at[gte]") != "2024-05-01T10:00:00Z" {
+					w.WriteHeader(http.StatusTeapot)
+
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write(responseEmptyRead)
+			})),
+			expected: &common.ReadResult{
+				Data: []common.ReadResultRow{},
+				Done: true,
+			},
+			expectedErrs: nil,
+		},
+		{
+			name: "Paging counts are requested and total is reported as Rows",
+			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("include_paging_counts") != "true" {
+					w.WriteHeader(http.StatusTeapot)
+
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				mockutils.WriteBody(w, `{
+					"metadata": {
+						"paging": {"per_page": 100, "current_page": 1, "next_page": null, "total_count": 7}
+					},
+					"data": []
+				}`)
+			})),
+			expected: &common.ReadResult{
+				Rows: 7,
+				Data: []common.ReadResultRow{},
+				Done: true,
+			},
+			expectedErrs: nil,
+		},
 		{
 			name: "Next page URL is correctly inferred",
 			server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -99,7 +147,7 @@ func TestRead(t *testing.T) { //nolint:funlen,gocognit,cyclop,maintidx
 				return actual.NextPage.String() == expectedNextPage // nolint:nlreturn
 			},
 			expected: &common.ReadResult{
-				NextPage: "{{testServerURL}}?page=2&per_page=100",
+				NextPage: "{{testServerURL}}?include_paging_counts=true&page=2&per_page=100",
 			},
 			expectedErrs: nil,
 		},
@@ -9,11 +9,20 @@ import (
 	"github.com/spyzhov/ajson"
 )
 
+// Write creates or updates a record.
 func (c *Connector) Write(ctx context.Context, config common.WriteParams) (*common.WriteResult, error) {
+	return common.IdempotentWrite(ctx, c.idempotency, config, c.write)
+}
+
+func (c *Connector) write(ctx context.Context, config common.WriteParams) (*common.WriteResult, error) {
 	if len(config.ObjectName) == 0 {
 		return nil, common.ErrMissingObjects
 	}
 
+	if err := c.metadata.ValidateWrite(ctx, config); err != nil {
+		return nil, err
+	}
+
 	url, err := c.getURL(config.ObjectName)
 	if err != nil {
 		return nil, err
@@ -2,8 +2,7 @@
 package main
 
 import (
-	"encoding/base64"
-	"encoding/json"
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -12,12 +11,12 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/amp-labs/connectors/providers"
 	"github.com/amp-labs/connectors/utils"
+	"github.com/amp-labs/connectors/utils/oauthapp"
 	"golang.org/x/oauth2"
 )
 
@@ -41,6 +40,10 @@ import (
 
 // Remember to run the script in the same directory as the script.
 // go run token.go
+//
+// Obtained token is written back into creds.json. Providers with PKCE grant type use PKCE automatically.
+// Without a browser, use device authorization grant, if the provider supports it:
+// go run token.go -device -deviceurl https://example.com/oauth/device/code
 
 const (
 	HttpProtocol = "http"
@@ -92,127 +95,91 @@ var readers = []utils.Reader{
 		JSONPath: "$['state']",
 		CredKey:  "State",
 	},
+	&utils.JSONReader{
+		FilePath: DefaultCredsFile,
+		JSONPath: "$['accessToken']",
+		CredKey:  oauthapp.AccessTokenKey,
+	},
+	&utils.JSONReader{
+		FilePath: DefaultCredsFile,
+		JSONPath: "$['refreshToken']",
+		CredKey:  oauthapp.RefreshTokenKey,
+	},
+	&utils.JSONReader{
+		FilePath: DefaultCredsFile,
+		JSONPath: "$['expiry']",
+		CredKey:  oauthapp.ExpiryKey,
+	},
+	&utils.JSONReader{
+		FilePath: DefaultCredsFile,
+		JSONPath: "$['expiryFormat']",
+		CredKey:  oauthapp.ExpiryFormatKey,
+	},
 }
 
-// OAuthApp is a simple OAuth app that can be used to get an OAuth token.
+// OAuthApp serves the authorization code flow on localhost.
 type OAuthApp struct {
-	Callback string
-	Port     int
-	Config   *oauth2.Config
-	Options  []oauth2.AuthCodeOption
-	State    string
-	Proto    string
-	SSLCert  string
-	SSLKey   string
-}
-
-// ServeHTTP implements the http.Handler interface.
-func (a *OAuthApp) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
-	switch {
-	case request.URL.Path == a.Callback && request.Method == "GET":
-		// Process the callback.
-		a.processCallback(writer, request)
-
-	case request.URL.Path == "/" && request.Method == "GET":
-		// Redirect to the OAuth provider.
-		encState := base64.URLEncoding.EncodeToString([]byte(a.State))
-		url := a.Config.AuthCodeURL(encState, a.Options...)
-		writer.Header().Set("Location", url)
-		writer.WriteHeader(http.StatusTemporaryRedirect)
-
-	default:
-		writer.WriteHeader(http.StatusNotFound)
-	}
+	*oauthapp.App
+	Port    int
+	Proto   string
+	SSLCert string
+	SSLKey  string
+	// Device switches to device authorization grant, nothing is served then.
+	Device bool
 }
 
-// processCallback processes the code obtained from the OAuth callback.
-func (a *OAuthApp) processCallback(writer http.ResponseWriter, request *http.Request) {
-	// Get the code from the query string.
-	query := request.URL.Query()
-	code := query.Get("code")
-
-	// If given, get the state from the query string.
-	var state string
-
-	encState := query.Get("state")
-
-	if encState != "" {
-		stateBts, err := base64.URLEncoding.DecodeString(encState)
-		if err != nil {
-			slog.Error("Error base64-decoding state", "error", err)
-			http.Error(writer, err.Error(), http.StatusBadRequest)
-
-			return
-		}
-
-		state = string(stateBts)
-	}
-
-	if len(state) > 0 {
-		slog.Info("got a state", "state", state)
-	}
-
-	// Exchange the code for a token.
-	tok, err := a.Config.Exchange(request.Context(), code)
-	if err != nil {
-		slog.Error("Error exchanging code for token", "error", err)
-		http.Error(writer, err.Error(), http.StatusInternalServerError)
-
-		return
+// Run executes the OAuth flow to get a token.
+func (a *OAuthApp) Run(ctx context.Context) (*oauth2.Token, error) {
+	if a.Device {
+		return oauthapp.DeviceToken(ctx, a.Config, func(auth *oauth2.DeviceAuthResponse) {
+			fmt.Printf("Open %s and enter the code %s\n", auth.VerificationURI, auth.UserCode)
+		}, a.Options...)
 	}
 
-	// And also in the browser
-	jsonBody, err := json.MarshalIndent(tok, "", "  ")
-	if err != nil {
-		slog.Error("Error marshalling token", "error", err)
-		http.Error(writer, err.Error(), http.StatusInternalServerError)
+	slog.Info("starting OAuth app", "port", a.Port)
 
-		return
+	server := &http.Server{
+		Addr:              fmt.Sprintf("0.0.0.0:%d", a.Port),
+		Handler:           a.App,
+		ReadHeaderTimeout: ReadHeaderTimeoutSeconds * time.Second,
 	}
 
-	// Print the token which will also print raw metadata
-	fmt.Printf("%+v", tok)
-
-	writer.Header().Set("Content-Type", "application/json")
-	writer.Header().Set("Content-Length", strconv.FormatInt(int64(len(jsonBody)), 10))
-	writer.WriteHeader(http.StatusOK)
+	failed := make(chan error, 1)
 
-	// All done
-	if _, err = writer.Write([]byte("Received a token, printed in the console")); err != nil { // nosemgrep
-		slog.Error("Error writing token", "error", err)
-
-		os.Exit(1)
-	}
+	go func() {
+		if a.Proto == HttpProtocol {
+			// nosemgrep: go.lang.security.audit.net.use-tls.use-tls
+			failed <- server.ListenAndServe()
+		} else {
+			failed <- server.ListenAndServeTLS(a.SSLCert, a.SSLKey)
+		}
+	}()
 
 	go func() {
+		time.Sleep(1 * time.Second)
+		openBrowser(fmt.Sprintf("%s://localhost:%d", a.Proto, a.Port))
+	}()
+
+	defer func() {
+		// Let the browser receive the response.
 		time.Sleep(WaitBeforeExitSeconds * time.Second)
 
-		os.Exit(0)
+		_ = server.Shutdown(context.Background())
 	}()
-}
 
-// Run executes the OAuth flow to get a token.
-func (a *OAuthApp) Run() error {
-	slog.Info("starting OAuth app", "port", a.Port)
-
-	http.Handle("/", a)
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
 	go func() {
-		time.Sleep(1 * time.Second)
-		openBrowser(fmt.Sprintf("%s://localhost:%d", a.Proto, a.Port))
+		select {
+		case err := <-failed:
+			slog.Error("OAuth app server stopped", "error", err)
+			cancel()
+		case <-waitCtx.Done():
+		}
 	}()
 
-	server := &http.Server{
-		Addr:              fmt.Sprintf("0.0.0.0:%d", a.Port),
-		ReadHeaderTimeout: ReadHeaderTimeoutSeconds * time.Second,
-	}
-
-	if a.Proto == HttpProtocol {
-		// nosemgrep: go.lang.security.audit.net.use-tls.use-tls
-		return server.ListenAndServe()
-	} else {
-		return server.ListenAndServeTLS(a.SSLCert, a.SSLKey)
-	}
+	return a.Wait(waitCtx)
 }
 
 // openBrowser tries to open the URL in a browser. Should work on most standard platforms.
@@ -244,6 +211,8 @@ func setup() *OAuthApp {
 	SSLCert := flag.String("sslcert", DefaultSSLCert, "ssl certificate")
 	SSLKey := flag.String("sslkey", DefaultSSLKey, "ssl key")
 	proto := flag.String("proto", HttpProtocol, "http or https protocol")
+	device := flag.Bool("device", false, "use device authorization grant instead of the browser callback")
+	deviceURL := flag.String("deviceurl", "", "device authorization endpoint of the provider, required with -device")
 
 	callback := flag.String("callback", DefaultCallbackPath, "the full OAuth callback path (arbitrary)")
 	flag.Parse()
@@ -259,7 +228,8 @@ func setup() *OAuthApp {
 	// Get the OAuth scopes from the flag.
 	provider := registry.MustString("Provider")
 	clientId := registry.MustString("ClientId")
-	clientSecret := registry.MustString("ClientSecret")
+	// Public clients, ex: PKCE or device grant, may have no secret.
+	clientSecret, _ := registry.GetString("ClientSecret")
 
 	state, err := registry.GetString("State")
 	if err != nil {
@@ -275,20 +245,24 @@ func setup() *OAuthApp {
 
 	// Create the OAuth app.
 	app := &OAuthApp{
-		Callback: *callback,
-		Port:     *port,
-		Proto:    *proto,
-		SSLCert:  *SSLCert,
-		SSLKey:   *SSLKey,
-		Config: &oauth2.Config{
-			ClientID:     clientId,
-			ClientSecret: clientSecret,
-			RedirectURL:  redirect,
-			Scopes:       oauthScopes,
+		App: &oauthapp.App{
+			Callback: *callback,
+			State:    state,
+			Config: &oauth2.Config{
+				ClientID:     clientId,
+				ClientSecret: clientSecret,
+				RedirectURL:  redirect,
+				Scopes:       oauthScopes,
+			},
+			OnToken: func(_ context.Context, token *oauth2.Token) error {
+				return oauthapp.SaveToken(registry, token)
+			},
 		},
-	}
-	if state != "" {
-		app.State = state
+		Port:    *port,
+		Proto:   *proto,
+		SSLCert: *SSLCert,
+		SSLKey:  *SSLKey,
+		Device:  *device,
 	}
 
 	substitutions, err := registry.GetMap("Substitutions")
@@ -311,10 +285,12 @@ func setup() *OAuthApp {
 
 	// Set up the OAuth config based on the provider.
 	app.Config.Endpoint = oauth2.Endpoint{
-		AuthURL:   providerInfo.OauthOpts.AuthURL,
-		TokenURL:  providerInfo.OauthOpts.TokenURL,
-		AuthStyle: oauth2.AuthStyleAutoDetect,
+		AuthURL:       providerInfo.OauthOpts.AuthURL,
+		TokenURL:      providerInfo.OauthOpts.TokenURL,
+		DeviceAuthURL: *deviceURL,
+		AuthStyle:     oauth2.AuthStyleAutoDetect,
 	}
+	app.PKCE = providerInfo.SupportsGrantType(providers.PKCE)
 
 	return app
 }
@@ -324,11 +300,22 @@ func main() {
 	app := setup()
 
 	// Run the OAuth app.
-	if err := app.Run(); err != nil {
+	token, err := app.Run(context.Background())
+	if err != nil {
 		slog.Error("failed to run OAuth app", "error", err)
 
-		time.Sleep(WaitBeforeExitSeconds * time.Second)
-
 		os.Exit(1)
 	}
+
+	if app.Device {
+		// Callback saves the token itself, device grant returns it here.
+		if err = oauthapp.SaveToken(registry, token); err != nil {
+			slog.Error("failed to save token", "error", err)
+
+			os.Exit(1)
+		}
+	}
+
+	// Print the token which will also print raw metadata
+	fmt.Printf("%+v\n", token)
 }
@@ -377,7 +377,9 @@ func startOAuthAuthCodeProxy(ctx context.Context, provider string, scopes []stri
 func buildOAuth2ClientCredentialsProxy(ctx context.Context, provider string, scopes []string, clientId, clientSecret string, substitutions map[string]string) *Proxy {
 	providerInfo := getProviderConfig(provider, substitutions)
 	cfg := configureOAuthClientCredentials(clientId, clientSecret, scopes, providerInfo)
-	httpClient := setupOAuth2ClientCredentialsHttpClient(ctx, providerInfo, cfg)
+	httpClient := setupHttpClient(ctx, providerInfo, &providers.NewClientParams{
+		OAuth2ClientCreds: cfg,
+	})
 
 	target, err := url.Parse(providerInfo.BaseURL)
 	if err != nil {
@@ -389,7 +391,9 @@ func buildOAuth2ClientCredentialsProxy(ctx context.Context, provider string, sco
 
 func buildApiKeyProxy(ctx context.Context, provider string, substitutions map[string]string, apiKey string) *Proxy {
 	providerInfo := getProviderConfig(provider, substitutions)
-	httpClient := setupApiKeyHttpClient(ctx, providerInfo, apiKey)
+	httpClient := setupHttpClient(ctx, providerInfo, &providers.NewClientParams{
+		ApiKey: apiKey,
+	})
 
 	target, err := url.Parse(providerInfo.BaseURL)
 	if err != nil {
@@ -401,7 +405,12 @@ func buildApiKeyProxy(ctx context.Context, provider string, substitutions map[st
 
 func buildBasicAuthProxy(ctx context.Context, provider string, substitutions map[string]string, user, pass string) *Proxy {
 	providerInfo := getProviderConfig(provider, substitutions)
-	httpClient := setupBasicAuthHttpClient(ctx, providerInfo, user, pass)
+	httpClient := setupHttpClient(ctx, providerInfo, &providers.NewClientParams{
+		BasicCreds: &providers.BasicParams{
+			User: user,
+			Pass: pass,
+		},
+	})
 
 	target, err := url.Parse(providerInfo.BaseURL)
 	if err != nil {
@@ -414,7 +423,12 @@ func buildBasicAuthProxy(ctx context.Context, provider string, substitutions map
 func buildOAuth2AuthCodeProxy(ctx context.Context, provider string, scopes []string, clientId, clientSecret string, substitutions map[string]string, tokens *oauth2.Token) *Proxy {
 	providerInfo := getProviderConfig(provider, substitutions)
 	cfg := configureOAuthAuthCode(clientId, clientSecret, scopes, providerInfo)
-	httpClient := setupOAuth2AuthCodeHttpClient(ctx, providerInfo, cfg, tokens)
+	httpClient := setupHttpClient(ctx, providerInfo, &providers.NewClientParams{
+		OAuth2AuthCodeCreds: &providers.OAuth2AuthCodeParams{
+			Config: cfg,
+			Token:  tokens,
+		},
+	})
 
 	target, err := url.Parse(providerInfo.BaseURL)
 	if err != nil {
@@ -455,69 +469,11 @@ func configureOAuthAuthCode(clientId, clientSecret string, scopes []string, prov
 	}
 }
 
-func setupOAuth2ClientCredentialsHttpClient(ctx context.Context, prov *providers.ProviderInfo, cfg *clientcredentials.Config) common.AuthenticatedHTTPClient {
-	c, err := prov.NewClient(ctx, &providers.NewClientParams{
-		Debug:             *debug,
-		OAuth2ClientCreds: cfg,
-	})
-	if err != nil {
-		panic(err)
-	}
-
-	cc, err := connector.NewConnector(prov.Name, connector.WithAuthenticatedClient(c))
-	if err != nil {
-		panic(err)
-	}
-
-	return cc.HTTPClient().Client
-}
+// setupHttpClient creates the client for the provider's auth type, it refreshes OAuth tokens automatically.
+func setupHttpClient(ctx context.Context, prov *providers.ProviderInfo, params *providers.NewClientParams) common.AuthenticatedHTTPClient {
+	params.Debug = *debug
 
-// This helps with refreshing tokens automatically.
-func setupOAuth2AuthCodeHttpClient(ctx context.Context, prov *providers.ProviderInfo, cfg *oauth2.Config, tokens *oauth2.Token) common.AuthenticatedHTTPClient {
-	c, err := prov.NewClient(ctx, &providers.NewClientParams{
-		Debug: *debug,
-		OAuth2AuthCodeCreds: &providers.OAuth2AuthCodeParams{
-			Config: cfg,
-			Token:  tokens,
-		},
-	})
-	if err != nil {
-		panic(err)
-	}
-
-	cc, err := connector.NewConnector(prov.Name, connector.WithAuthenticatedClient(c))
-	if err != nil {
-		panic(err)
-	}
-
-	return cc.HTTPClient().Client
-}
-
-func setupBasicAuthHttpClient(ctx context.Context, prov *providers.ProviderInfo, user, pass string) common.AuthenticatedHTTPClient {
-	c, err := prov.NewClient(ctx, &providers.NewClientParams{
-		Debug: *debug,
-		BasicCreds: &providers.BasicParams{
-			User: user,
-			Pass: pass,
-		},
-	})
-	if err != nil {
-		panic(err)
-	}
-
-	cc, err := connector.NewConnector(prov.Name, connector.WithAuthenticatedClient(c))
-	if err != nil {
-		panic(err)
-	}
-
-	return cc.HTTPClient().Client
-}
-
-func setupApiKeyHttpClient(ctx context.Context, prov *providers.ProviderInfo, apiKey string) common.AuthenticatedHTTPClient {
-	c, err := prov.NewClient(ctx, &providers.NewClientParams{
-		Debug:  *debug,
-		ApiKey: apiKey,
-	})
+	c, err := prov.NewClient(ctx, params)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,88 @@
+package conformance
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// ServerURLPlaceholder inside a fixture is replaced with the URL of the fake server.
+// Use it for absolute next page links, ex: "{{testServerURL}}/v2/people?page=2".
+const ServerURLPlaceholder = "{{testServerURL}}"
+
+// Response is a canned reply of the fake provider.
+type Response struct {
+	// Status defaults to 200.
+	Status int
+	// ContentType defaults to application/json.
+	ContentType string
+	Body        []byte
+}
+
+// JSON is a shortcut for successful JSON response.
+func JSON(body []byte) Response {
+	return Response{Body: body}
+}
+
+// fakeServer replies with responses in order. Once responses run out, it fails every request,
+// which catches connectors that keep paginating after the last page.
+type fakeServer struct {
+	*httptest.Server
+
+	mut       sync.Mutex
+	responses []Response
+	requests  []*http.Request
+}
+
+func newFakeServer(responses ...Response) *fakeServer {
+	server := &fakeServer{responses: responses}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.handle))
+
+	return server
+}
+
+func (s *fakeServer) handle(w http.ResponseWriter, r *http.Request) {
+	// Body is closed once the handler returns, recorded request keeps a copy.
+	body, _ := io.ReadAll(r.Body)
+	request := r.Clone(r.Context())
+	request.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.mut.Lock()
+	index := len(s.requests)
+	s.requests = append(s.requests, request)
+	s.mut.Unlock()
+
+	if index >= len(s.responses) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotImplemented)
+		_, _ = w.Write([]byte(`{"error": "conformance server: unexpected request"}`))
+
+		return
+	}
+
+	response := s.responses[index]
+
+	contentType := response.ContentType
+	if len(contentType) == 0 {
+		contentType = "application/json"
+	}
+
+	status := response.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, _ = w.Write(bytes.ReplaceAll(response.Body, []byte(ServerURLPlaceholder), []byte(s.URL)))
+}
+
+// Requests returns every request received so far.
+func (s *fakeServer) Requests() []*http.Request {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	return append([]*http.Request(nil), s.requests...)
+}
@@ -0,0 +1,355 @@
+// Package conformance is a reusable test suite which verifies that a connector follows
+// the common contract of Read, Write and Delete operations.
+//
+// Connector package plugs in with a factory pointing the connector at a fake server,
+// and with fixtures the server should reply with:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, conformance.Suite{
+//			NewConnector: func(serverURL string) (any, error) {...},
+//			Read: &conformance.ReadSpec{...},
+//		})
+//	}
+package conformance
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/connectors/common"
+)
+
+type (
+	Reader interface {
+		Read(ctx context.Context, params common.ReadParams) (*common.ReadResult, error)
+	}
+	Writer interface {
+		Write(ctx context.Context, params common.WriteParams) (*common.WriteResult, error)
+	}
+	Deleter interface {
+		Delete(ctx context.Context, params common.DeleteParams) (*common.DeleteResult, error)
+	}
+)
+
+// Suite describes connector under test. Every nil spec is skipped.
+type Suite struct {
+	// NewConnector must return connector which sends all requests to the serverURL.
+	// Connector must implement Reader, Writer or Deleter depending on which specs are provided.
+	NewConnector func(serverURL string) (any, error)
+
+	Read   *ReadSpec
+	Write  *WriteSpec
+	Delete *DeleteSpec
+	// Errors are served in response to Read. Requires Read spec.
+	Errors []ErrorCase
+}
+
+// ReadSpec verifies pagination, fields extraction and incremental reading.
+type ReadSpec struct {
+	// Params of the first page. Fields should use provider casing, they are expected back in lowercase.
+	Params common.ReadParams
+	// Pages are replied in order. Every record of every page must contain all requested Fields.
+	// Every page except the last must produce NextPage, the last one must end pagination.
+	Pages []Response
+	// ExpectedRows is the total each page must report via Rows. When zero, the total is the number
+	// of records served by all Pages, so set it only when fixtures report a total beyond the served records.
+	// Rows is the total of the whole read for every connector, fixtures must include the total the provider reports.
+	ExpectedRows int64
+	// Since, when set, enables incremental read check.
+	Since *SinceSpec
+}
+
+// SinceSpec checks that ReadParams.Since reaches the provider.
+type SinceSpec struct {
+	// Timestamp is sent as ReadParams.Since.
+	Timestamp time.Time
+	// IsFiltered reports whether the outgoing request filters records by the Timestamp.
+	// Request body can be read, ex: when the filter is sent via POST.
+	IsFiltered func(req *http.Request, since time.Time) bool
+}
+
+// WriteSpec verifies that creating a record reports its identifier.
+type WriteSpec struct {
+	// Params must describe record creation, RecordId is empty.
+	Params   common.WriteParams
+	Response Response
+}
+
+// DeleteSpec verifies successful removal.
+type DeleteSpec struct {
+	Params   common.DeleteParams
+	Response Response
+}
+
+// ErrorCase verifies that erroneous provider response is mapped to error category.
+type ErrorCase struct {
+	Name     string
+	Response Response
+	// Expected errors must all match via errors.Is.
+	Expected []error
+}
+
+// StandardErrorCases returns cases every connector must map the same way.
+// The body should be an error payload the connector understands.
+func StandardErrorCases(body []byte) []ErrorCase {
+	return []ErrorCase{
+		{
+			Name:     "Unauthorized is access token error",
+			Response: Response{Status: http.StatusUnauthorized, Body: body},
+			Expected: []error{common.ErrAccessToken},
+		},
+		{
+			Name:     "Forbidden is forbidden error",
+			Response: Response{Status: http.StatusForbidden, Body: body},
+			Expected: []error{common.ErrForbidden},
+		},
+		{
+			Name:     "Internal server error is server error",
+			Response: Response{Status: http.StatusInternalServerError, Body: body},
+			Expected: []error{common.ErrServer},
+		},
+	}
+}
+
+// Run executes every check applicable to the suite.
+func Run(t *testing.T, suite Suite) {
+	t.Helper()
+
+	if suite.NewConnector == nil {
+		t.Fatal("invalid conformance suite: NewConnector is required")
+	}
+
+	if suite.Read != nil {
+		t.Run("Read pagination terminates", func(t *testing.T) {
+			t.Parallel()
+			checkPagination(t, suite)
+		})
+		t.Run("Read fields are lowercase", func(t *testing.T) {
+			t.Parallel()
+			checkFields(t, suite)
+		})
+
+		if suite.Read.Since != nil {
+			t.Run("Read honors Since", func(t *testing.T) {
+				t.Parallel()
+				checkSince(t, suite)
+			})
+		}
+
+		for _, errorCase := range suite.Errors {
+			errorCase := errorCase
+			t.Run("Error mapping: "+errorCase.Name, func(t *testing.T) {
+				t.Parallel()
+				checkErrorMapping(t, suite, errorCase)
+			})
+		}
+	}
+
+	if suite.Write != nil {
+		t.Run("Write create returns RecordId", func(t *testing.T) {
+			t.Parallel()
+			checkWrite(t, suite)
+		})
+	}
+
+	if suite.Delete != nil {
+		t.Run("Delete succeeds", func(t *testing.T) {
+			t.Parallel()
+			checkDelete(t, suite)
+		})
+	}
+}
+
+func checkPagination(t *testing.T, suite Suite) {
+	t.Helper()
+
+	server := newFakeServer(suite.Read.Pages...)
+	defer server.Close()
+
+	reader := newConnector[Reader](t, suite, server.URL)
+	params := suite.Read.Params
+	results := make([]*common.ReadResult, 0, len(suite.Read.Pages))
+
+	for page := 1; ; page++ {
+		result, err := reader.Read(context.Background(), params)
+		if err != nil {
+			t.Fatalf("page %v: unexpected error: %v", page, err)
+		}
+
+		if result.Done != (len(result.NextPage) == 0) {
+			t.Fatalf("page %v: Done (%v) must be true exactly when NextPage (%q) is empty",
+				page, result.Done, result.NextPage)
+		}
+
+		results = append(results, result)
+
+		if result.Done {
+			if page != len(suite.Read.Pages) {
+				t.Fatalf("pagination stopped at page %v, expected %v pages", page, len(suite.Read.Pages))
+			}
+
+			break
+		}
+
+		if page >= len(suite.Read.Pages) {
+			t.Fatalf("pagination didn't terminate after the last page %v, NextPage is %q", page, result.NextPage)
+		}
+
+		params.NextPage = result.NextPage
+	}
+
+	checkRows(t, suite, results)
+}
+
+// checkRows verifies that every page reports the total of the whole read, rather than the page size.
+func checkRows(t *testing.T, suite Suite, results []*common.ReadResult) {
+	t.Helper()
+
+	total := suite.Read.ExpectedRows
+	if total == 0 {
+		for _, result := range results {
+			total += int64(len(result.Data))
+		}
+	}
+
+	for index, result := range results {
+		if result.Rows != total {
+			t.Fatalf("page %v: Rows must be the total (%v), got (%v)", index+1, total, result.Rows)
+		}
+	}
+}
+
+func checkFields(t *testing.T, suite Suite) {
+	t.Helper()
+
+	if len(suite.Read.Params.Fields) == 0 {
+		t.Fatal("invalid conformance suite: ReadSpec.Params.Fields must not be empty")
+	}
+
+	server := newFakeServer(suite.Read.Pages...)
+	defer server.Close()
+
+	result, err := newConnector[Reader](t, suite, server.URL).Read(context.Background(), suite.Read.Params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Data) == 0 {
+		t.Fatal("invalid conformance suite: first page must have records")
+	}
+
+	for index, row := range result.Data {
+		for key := range row.Fields {
+			if key != strings.ToLower(key) {
+				t.Fatalf("record %v: field %q is not lowercase", index, key)
+			}
+		}
+
+		for _, field := range suite.Read.Params.Fields {
+			if _, ok := row.Fields[strings.ToLower(field)]; !ok {
+				t.Fatalf("record %v: requested field %q is missing, got %v", index, field, row.Fields)
+			}
+		}
+	}
+}
+
+func checkSince(t *testing.T, suite Suite) {
+	t.Helper()
+
+	server := newFakeServer(suite.Read.Pages...)
+	defer server.Close()
+
+	params := suite.Read.Params
+	params.Since = suite.Read.Since.Timestamp
+
+	if _, err := newConnector[Reader](t, suite, server.URL).Read(context.Background(), params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	requests := server.Requests()
+	if len(requests) == 0 {
+		t.Fatal("no request reached the server")
+	}
+
+	if !suite.Read.Since.IsFiltered(requests[0], params.Since) {
+		t.Fatalf("request doesn't filter by Since: %v %v", requests[0].Method, requests[0].URL)
+	}
+}
+
+func checkErrorMapping(t *testing.T, suite Suite, errorCase ErrorCase) {
+	t.Helper()
+
+	server := newFakeServer(errorCase.Response)
+	defer server.Close()
+
+	_, err := newConnector[Reader](t, suite, server.URL).Read(context.Background(), suite.Read.Params)
+	if err == nil {
+		t.Fatalf("expected errors (%v), but got nothing", errorCase.Expected)
+	}
+
+	for _, expected := range errorCase.Expected {
+		if !errors.Is(err, expected) {
+			t.Fatalf("expected error: (%v), got: (%v)", expected, err)
+		}
+	}
+}
+
+func checkWrite(t *testing.T, suite Suite) {
+	t.Helper()
+
+	if len(suite.Write.Params.RecordId) != 0 {
+		t.Fatal("invalid conformance suite: WriteSpec must describe record creation")
+	}
+
+	server := newFakeServer(suite.Write.Response)
+	defer server.Close()
+
+	result, err := newConnector[Writer](t, suite, server.URL).Write(context.Background(), suite.Write.Params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Success {
+		t.Fatalf("write must succeed, got %v", result)
+	}
+
+	if len(result.RecordId) == 0 {
+		t.Fatalf("created record must have RecordId, got %v", result)
+	}
+}
+
+func checkDelete(t *testing.T, suite Suite) {
+	t.Helper()
+
+	server := newFakeServer(suite.Delete.Response)
+	defer server.Close()
+
+	result, err := newConnector[Deleter](t, suite, server.URL).Delete(context.Background(), suite.Delete.Params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.Success {
+		t.Fatalf("delete must succeed, got %v", result)
+	}
+}
+
+func newConnector[C any](t *testing.T, suite Suite, serverURL string) C { // nolint:ireturn
+	t.Helper()
+
+	conn, err := suite.NewConnector(serverURL)
+	if err != nil {
+		t.Fatalf("error in test while constructing connector %v", err)
+	}
+
+	typed, ok := conn.(C)
+	if !ok {
+		t.Fatalf("connector %T doesn't implement %T", conn, new(C))
+	}
+
+	return typed
+}
@@ -0,0 +1,94 @@
+package recorder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+var (
+	ErrCassetteNotFound = errors.New("cassette file not found")
+	ErrNoInteraction    = errors.New("no recorded interaction matches the request")
+)
+
+// Cassette is a file holding a sequence of recorded HTTP interactions.
+// It is stored as indented JSON, so it can be reviewed and edited like any other test fixture.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a single request/response pair.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+
+	// replayed marks interaction as consumed during replay.
+	// Each interaction is served at most once, which preserves the order of repeated calls.
+	replayed bool
+}
+
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// LoadCassette reads cassette from file.
+func LoadCassette(filename string) (*Cassette, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %v", ErrCassetteNotFound, filename)
+		}
+
+		return nil, err
+	}
+
+	cassette := &Cassette{}
+	if err = json.Unmarshal(data, cassette); err != nil {
+		return nil, fmt.Errorf("invalid cassette %v: %w", filename, err)
+	}
+
+	return cassette, nil
+}
+
+// Save writes cassette to file, creating missing directories.
+func (c *Cassette) Save(filename string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(filename), os.ModePerm); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, data, 0o600) // nolint:gomnd
+}
+
+// next finds the first interaction that wasn't replayed yet and that matches the request.
+func (c *Cassette) next(req *RecordedRequest, matcher Matcher) (*Interaction, bool) {
+	for _, interaction := range c.Interactions {
+		if interaction.replayed {
+			continue
+		}
+
+		if matcher(req, &interaction.Request) {
+			interaction.replayed = true
+
+			return interaction, true
+		}
+	}
+
+	return nil, false
+}
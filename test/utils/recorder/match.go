@@ -0,0 +1,72 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+)
+
+// Matcher decides whether an incoming request corresponds to the recorded one.
+// Both requests are already scrubbed, so secrets do not affect the outcome.
+type Matcher func(incoming, recorded *RecordedRequest) bool
+
+// DefaultMatcher compares method, path, query parameters and body.
+// Query parameter order is ignored, JSON bodies are compared semantically.
+func DefaultMatcher(incoming, recorded *RecordedRequest) bool {
+	if incoming.Method != recorded.Method {
+		return false
+	}
+
+	return matchURL(incoming.URL, recorded.URL) && matchBody(incoming.Body, recorded.Body)
+}
+
+// MatchWithoutBody compares method, path and query parameters.
+// Useful when the body contains values that change on every run, ex: timestamps.
+func MatchWithoutBody(incoming, recorded *RecordedRequest) bool {
+	return incoming.Method == recorded.Method && matchURL(incoming.URL, recorded.URL)
+}
+
+func matchURL(incoming, recorded string) bool {
+	incomingURL, err := url.Parse(incoming)
+	if err != nil {
+		return false
+	}
+
+	recordedURL, err := url.Parse(recorded)
+	if err != nil {
+		return false
+	}
+
+	// Host is intentionally not compared, cassettes are replayed against test servers with random ports.
+	if incomingURL.Path != recordedURL.Path {
+		return false
+	}
+
+	// Encode sorts query parameters by key.
+	return incomingURL.Query().Encode() == recordedURL.Query().Encode()
+}
+
+func matchBody(incoming, recorded string) bool {
+	if incoming == recorded {
+		return true
+	}
+
+	var incomingJSON, recordedJSON any
+	if json.Unmarshal([]byte(incoming), &incomingJSON) != nil ||
+		json.Unmarshal([]byte(recorded), &recordedJSON) != nil {
+		return false
+	}
+
+	// Re-marshalling produces canonical form with sorted keys.
+	first, err := json.Marshal(incomingJSON)
+	if err != nil {
+		return false
+	}
+
+	second, err := json.Marshal(recordedJSON)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(first, second)
+}
@@ -0,0 +1,262 @@
+// Package recorder provides HTTP record/replay transport for offline connector tests.
+//
+// Interactions are recorded once against the real provider, with secrets and PII scrubbed,
+// and stored as a JSON cassette next to other test fixtures. Later, `go test` replays them
+// deterministically without network access or credentials.
+//
+// The Recorder is an http.RoundTripper, therefore it plugs into any connector:
+//
+//	rec, _ := recorder.New("test/cassettes/read-people.json")
+//	conn, _ := salesloft.NewConnector(salesloft.WithClient(ctx, rec.Client(), cfg, tok))
+//	// or
+//	conn, _ := salesloft.NewConnector(salesloft.WithAuthenticatedClient(rec.Client()))
+package recorder
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Mode controls whether the Recorder talks to the real provider.
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette. Network is never used.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the provider and appends interactions to the cassette.
+	ModeRecord
+)
+
+// EnvRecordMode when set to "true" switches all recorders constructed with ModeFromEnv to ModeRecord.
+const EnvRecordMode = "RECORDER_MODE_RECORD"
+
+// ModeFromEnv returns ModeRecord if EnvRecordMode is set, otherwise ModeReplay.
+func ModeFromEnv() Mode {
+	if os.Getenv(EnvRecordMode) == "true" {
+		return ModeRecord
+	}
+
+	return ModeReplay
+}
+
+// Option is a function which mutates the recorder configuration.
+type Option func(params *recorderParams)
+
+// WithMode sets recorder mode. Default is ModeReplay.
+func WithMode(mode Mode) Option {
+	return func(params *recorderParams) {
+		params.mode = mode
+	}
+}
+
+// WithTransport sets transport used to reach the provider in ModeRecord.
+// Default is http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(params *recorderParams) {
+		params.transport = transport
+	}
+}
+
+// WithMatcher replaces DefaultMatcher.
+func WithMatcher(matcher Matcher) Option {
+	return func(params *recorderParams) {
+		params.matcher = matcher
+	}
+}
+
+// WithSecretHeaders adds headers whose values must never reach the cassette.
+func WithSecretHeaders(names ...string) Option {
+	return func(params *recorderParams) {
+		params.scrubber.addHeaders(names...)
+	}
+}
+
+// WithRedactedFields adds query parameters, form values and JSON keys (at any depth)
+// whose values must never reach the cassette. Tokens, emails, personal names and phone numbers
+// are redacted by default, use it for other PII, ex: addresses or custom fields.
+func WithRedactedFields(names ...string) Option {
+	return func(params *recorderParams) {
+		params.scrubber.addFields(names...)
+	}
+}
+
+type recorderParams struct {
+	mode      Mode
+	transport http.RoundTripper
+	matcher   Matcher
+	scrubber  *Scrubber
+}
+
+// Recorder is http.RoundTripper which either records or replays interactions.
+type Recorder struct {
+	filename  string
+	mode      Mode
+	transport http.RoundTripper
+	matcher   Matcher
+	scrubber  *Scrubber
+
+	mut      sync.Mutex
+	cassette *Cassette
+}
+
+// New creates a recorder for the cassette file.
+// In ModeReplay the cassette must exist. In ModeRecord it is created on Save.
+func New(filename string, opts ...Option) (*Recorder, error) {
+	params := &recorderParams{
+		mode:      ModeReplay,
+		transport: http.DefaultTransport,
+		matcher:   DefaultMatcher,
+		scrubber:  newScrubber(),
+	}
+	for _, opt := range opts {
+		opt(params)
+	}
+
+	cassette := &Cassette{}
+
+	if params.mode == ModeReplay {
+		var err error
+
+		cassette, err = LoadCassette(filename)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Recorder{
+		filename:  filename,
+		mode:      params.mode,
+		transport: params.transport,
+		matcher:   params.matcher,
+		scrubber:  params.scrubber,
+		cassette:  cassette,
+	}, nil
+}
+
+// Client returns http client backed by this recorder.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Mode returns current recorder mode.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Save flushes recorded interactions to the cassette file. It does nothing in ModeReplay.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	return r.cassette.Save(r.filename)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := readRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.scrubber.scrubRequest(recorded)
+
+	if r.mode == ModeRecord {
+		return r.record(req, recorded)
+	}
+
+	return r.replay(req, recorded)
+}
+
+func (r *Recorder) record(req *http.Request, recorded *RecordedRequest) (*http.Response, error) {
+	rsp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(rsp.Body)
+	_ = rsp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Caller still gets the original response.
+	rsp.Body = io.NopCloser(bytes.NewReader(body))
+
+	response := RecordedResponse{
+		StatusCode: rsp.StatusCode,
+		Headers:    rsp.Header,
+		Body:       string(body),
+	}
+	r.scrubber.scrubResponse(&response)
+
+	r.mut.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Request:  *recorded,
+		Response: response,
+	})
+	r.mut.Unlock()
+
+	return rsp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded *RecordedRequest) (*http.Response, error) {
+	r.mut.Lock()
+	interaction, ok := r.cassette.next(recorded, r.matcher)
+	r.mut.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %v %v", ErrNoInteraction, recorded.Method, recorded.URL)
+	}
+
+	body := []byte(interaction.Response.Body)
+	code := interaction.Response.StatusCode
+
+	headers := interaction.Response.Headers.Clone()
+	if headers == nil {
+		headers = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        headers,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// readRequest copies request into its recorded form. Request body is restored, so it can be sent.
+func readRequest(req *http.Request) (*RecordedRequest, error) {
+	var body []byte
+
+	if req.Body != nil {
+		var err error
+
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	return &RecordedRequest{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: req.Header.Clone(),
+		Body:    string(body),
+	}, nil
+}
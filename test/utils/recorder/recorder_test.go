@@ -0,0 +1,248 @@
+package recorder
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/amp-labs/connectors/salesloft"
+	"github.com/amp-labs/connectors/test/utils/mockutils"
+	"github.com/go-test/deep"
+)
+
+const peopleResponse = `{
+	"metadata": {"paging": {"per_page": 100, "current_page": 1, "next_page": null}},
+	"data": [{"id": 12345678901234567891, "title": "Engineer", "first_name": "Lynnelle", "email_address": "losbourn29@paypal.com"}]
+}`
+
+func TestRecordAndReplay(t *testing.T) { // nolint:funlen
+	t.Parallel()
+
+	cassettePath := filepath.Join(t.TempDir(), "read-people.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		mockutils.WriteBody(w, peopleResponse)
+	}))
+
+	params := common.ReadParams{ObjectName: "people", Fields: []string{"title", "first_name", "email_address"}}
+
+	// Record phase talks to the "provider".
+	rec, err := New(cassettePath, WithMode(ModeRecord))
+	if err != nil {
+		t.Fatalf("failed to create recorder: %v", err)
+	}
+
+	recordedResult := readWith(t, rec, server.URL, params, "secret-token")
+
+	if err = rec.Save(); err != nil {
+		t.Fatalf("failed to save cassette: %v", err)
+	}
+
+	server.Close()
+
+	cassette, err := LoadCassette(cassettePath)
+	if err != nil {
+		t.Fatalf("failed to load cassette: %v", err)
+	}
+
+	if len(cassette.Interactions) != 1 {
+		t.Fatalf("expected 1 interaction, got %v", len(cassette.Interactions))
+	}
+
+	interaction := cassette.Interactions[0]
+	if interaction.Request.Headers.Get("Authorization") != Redacted {
+		t.Fatalf("authorization header was not scrubbed: %v", interaction.Request.Headers)
+	}
+
+	if strings.Contains(interaction.Response.Body, "paypal.com") ||
+		strings.Contains(interaction.Response.Body, "Lynnelle") {
+		t.Fatalf("PII was not scrubbed: %v", interaction.Response.Body)
+	}
+
+	if !strings.Contains(interaction.Response.Body, "12345678901234567891") {
+		t.Fatalf("large id has changed: %v", interaction.Response.Body)
+	}
+
+	// Replay phase works without server and with a different token.
+	rec, err = New(cassettePath)
+	if err != nil {
+		t.Fatalf("failed to create recorder: %v", err)
+	}
+
+	replayedResult := readWith(t, rec, "http://replay.invalid", params, "other-token")
+
+	if replayedResult.Data[0].Fields["title"] != "Engineer" ||
+		replayedResult.Data[0].Fields["first_name"] != Redacted ||
+		replayedResult.Data[0].Fields["email_address"] != Redacted {
+		t.Fatalf("unexpected replayed fields: %v", replayedResult.Data[0].Fields)
+	}
+
+	if recordedResult.Rows != replayedResult.Rows || recordedResult.Done != replayedResult.Done {
+		t.Fatalf("replay differs from recording: %v", deep.Equal(recordedResult, replayedResult))
+	}
+
+	// Every interaction is served only once.
+	_, err = readWithErr(rec, "http://replay.invalid", params, "other-token")
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Fatalf("expected error (%v), got (%v)", ErrNoInteraction, err)
+	}
+}
+
+func TestReplayMissingCassette(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(filepath.Join(t.TempDir(), "missing.json"))
+	if !errors.Is(err, ErrCassetteNotFound) {
+		t.Fatalf("expected error (%v), got (%v)", ErrCassetteNotFound, err)
+	}
+}
+
+func TestScrubBody(t *testing.T) {
+	t.Parallel()
+
+	scrubber := newScrubber()
+
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		expected    string
+	}{
+		{
+			name:        "JSON keeps large numbers",
+			body:        `{"id": 12345678901234567891, "amount": 1.50, "email": "a@b.c"}`,
+			contentType: "application/json",
+			expected:    `{"amount":1.50,"email":"REDACTED","id":12345678901234567891}`,
+		},
+		{
+			name:        "Form is scrubbed",
+			body:        "grant_type=refresh_token&refresh_token=secret",
+			contentType: "application/x-www-form-urlencoded; charset=utf-8",
+			expected:    "grant_type=refresh_token&refresh_token=REDACTED",
+		},
+		{
+			name:        "XML is kept as is",
+			body:        `<?xml version="1.0"?><a href="x?b=c&amp;d=e">f=g</a>`,
+			contentType: "text/xml; charset=utf-8",
+			expected:    `<?xml version="1.0"?><a href="x?b=c&amp;d=e">f=g</a>`,
+		},
+		{
+			name:        "Text with equal sign is kept as is",
+			body:        "password=hunter2 is not a form",
+			contentType: "text/plain",
+			expected:    "password=hunter2 is not a form",
+		},
+	}
+
+	for _, tt := range tests { // nolint:varnamelen
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if output := scrubber.scrubBody(tt.body, tt.contentType); output != tt.expected {
+				t.Fatalf("%s: expected %v, got %v", tt.name, tt.expected, output)
+			}
+		})
+	}
+}
+
+func TestDefaultMatcher(t *testing.T) {
+	t.Parallel()
+
+	recorded := &RecordedRequest{
+		Method: http.MethodPost,
+		URL:    "https://api.provider.com/v2/people?b=2&a=1",
+		Body:   `{"name":"Bob","tags":["x"]}`,
+	}
+
+	tests := []struct {
+		name     string
+		incoming *RecordedRequest
+		expected bool
+	}{
+		{
+			name: "Host, query order and JSON key order are ignored",
+			incoming: &RecordedRequest{
+				Method: http.MethodPost,
+				URL:    "http://127.0.0.1:1234/v2/people?a=1&b=2",
+				Body:   `{"tags":["x"], "name":"Bob"}`,
+			},
+			expected: true,
+		},
+		{
+			name:     "Method must match",
+			incoming: &RecordedRequest{Method: http.MethodPut, URL: recorded.URL, Body: recorded.Body},
+			expected: false,
+		},
+		{
+			name: "Query values must match",
+			incoming: &RecordedRequest{
+				Method: http.MethodPost, URL: "https://api.provider.com/v2/people?a=1&b=3", Body: recorded.Body,
+			},
+			expected: false,
+		},
+		{
+			name: "Body must match",
+			incoming: &RecordedRequest{
+				Method: http.MethodPost, URL: recorded.URL, Body: `{"name":"Alice","tags":["x"]}`,
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt // rebind, omit loop side effects for parallel goroutine
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if output := DefaultMatcher(tt.incoming, recorded); output != tt.expected {
+				t.Fatalf("%s: expected: (%v), got: (%v)", tt.name, tt.expected, output)
+			}
+		})
+	}
+}
+
+func readWith(t *testing.T, rec *Recorder,
+	baseURL string, params common.ReadParams, token string,
+) *common.ReadResult {
+	t.Helper()
+
+	result, err := readWithErr(rec, baseURL, params, token)
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+
+	return result
+}
+
+func readWithErr(rec *Recorder, baseURL string, params common.ReadParams, token string) (*common.ReadResult, error) {
+	client, err := common.NewHeaderAuthHTTPClient(context.Background(),
+		common.WithHeaderClient(rec.Client()),
+		common.WithHeaders(common.Header{Key: "Authorization", Value: "Bearer " + token}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := salesloft.NewConnector(salesloft.WithAuthenticatedClient(client))
+	if err != nil {
+		return nil, err
+	}
+
+	conn.BaseURL = baseURL
+	conn.Client.HTTPClient.Base = baseURL
+
+	return conn.Read(context.Background(), params)
+}
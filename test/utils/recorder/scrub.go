@@ -0,0 +1,202 @@
+package recorder
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces every secret or personal value stored in a cassette.
+const Redacted = "REDACTED"
+
+// nolint:gochecknoglobals
+var (
+	defaultSecretHeaders = []string{
+		"Authorization",
+		"Proxy-Authorization",
+		"Cookie",
+		"Set-Cookie",
+		"X-Api-Key",
+	}
+
+	// defaultSecretFields apply to query parameters, form values and JSON object keys.
+	defaultSecretFields = []string{
+		"access_token",
+		"refresh_token",
+		"id_token",
+		"client_secret",
+		"client_id",
+		"api_key",
+		"apikey",
+		"password",
+	}
+
+	// defaultPIIFields are personal data commonly returned by CRMs, ex: contacts and users.
+	// Keys are matched ignoring the case, so "firstName" and "firstname" are the same.
+	defaultPIIFields = []string{
+		"email",
+		"email_address",
+		"emailaddress",
+		"first_name",
+		"firstname",
+		"last_name",
+		"lastname",
+		"full_name",
+		"fullname",
+		"phone",
+		"phone_number",
+		"phonenumber",
+		"mobile_phone",
+		"mobilephone",
+	}
+)
+
+// Scrubber removes tokens and PII from interactions before they are written to disk.
+// Incoming requests are scrubbed the same way during replay, which keeps matching stable.
+type Scrubber struct {
+	headers map[string]bool
+	fields  map[string]bool
+}
+
+func newScrubber() *Scrubber {
+	scrubber := &Scrubber{
+		headers: make(map[string]bool),
+		fields:  make(map[string]bool),
+	}
+
+	scrubber.addHeaders(defaultSecretHeaders...)
+	scrubber.addFields(defaultSecretFields...)
+	scrubber.addFields(defaultPIIFields...)
+
+	return scrubber
+}
+
+func (s *Scrubber) addHeaders(names ...string) {
+	for _, name := range names {
+		s.headers[http.CanonicalHeaderKey(name)] = true
+	}
+}
+
+func (s *Scrubber) addFields(names ...string) {
+	for _, name := range names {
+		s.fields[strings.ToLower(name)] = true
+	}
+}
+
+func (s *Scrubber) scrubRequest(req *RecordedRequest) {
+	req.Headers = s.scrubHeaders(req.Headers)
+	req.URL = s.scrubURL(req.URL)
+	req.Body = s.scrubBody(req.Body, req.Headers.Get("Content-Type"))
+}
+
+func (s *Scrubber) scrubResponse(rsp *RecordedResponse) {
+	rsp.Headers = s.scrubHeaders(rsp.Headers)
+	rsp.Body = s.scrubBody(rsp.Body, rsp.Headers.Get("Content-Type"))
+}
+
+func (s *Scrubber) scrubHeaders(headers http.Header) http.Header {
+	if headers == nil {
+		return nil
+	}
+
+	out := headers.Clone()
+	for key := range out {
+		if s.headers[http.CanonicalHeaderKey(key)] {
+			out[key] = []string{Redacted}
+		}
+	}
+
+	return out
+}
+
+func (s *Scrubber) scrubURL(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+
+	parsed.RawQuery = s.scrubValues(parsed.Query()).Encode()
+
+	return parsed.String()
+}
+
+func (s *Scrubber) scrubValues(values url.Values) url.Values {
+	for key := range values {
+		if s.fields[strings.ToLower(key)] {
+			values[key] = []string{Redacted}
+		}
+	}
+
+	return values
+}
+
+// scrubBody handles JSON documents and URL encoded forms (OAuth token exchange).
+// Any other payload, ex: XML or plain text, is stored as is.
+func (s *Scrubber) scrubBody(body string, contentType string) string {
+	if len(body) == 0 {
+		return body
+	}
+
+	if document, ok := decodeJSON(body); ok {
+		data, err := json.Marshal(s.scrubJSON(document))
+		if err != nil {
+			return body
+		}
+
+		return string(data)
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil &&
+		mediaType == "application/x-www-form-urlencoded" {
+		if form, err := url.ParseQuery(body); err == nil {
+			return s.scrubValues(form).Encode()
+		}
+	}
+
+	return body
+}
+
+// decodeJSON parses the whole body as a single JSON document.
+// Numbers are kept as written, IDs above 2^53 would lose precision as float64.
+func decodeJSON(body string) (any, bool) {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, false
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, false
+	}
+
+	return document, true
+}
+
+func (s *Scrubber) scrubJSON(node any) any {
+	switch value := node.(type) {
+	case map[string]any:
+		for key, nested := range value {
+			if s.fields[strings.ToLower(key)] {
+				value[key] = Redacted
+			} else {
+				value[key] = s.scrubJSON(nested)
+			}
+		}
+
+		return value
+	case []any:
+		for index, nested := range value {
+			value[index] = s.scrubJSON(nested)
+		}
+
+		return value
+	default:
+		return node
+	}
+}
@@ -30,3 +30,35 @@ func (r *ObjectMetadataResult) Select(objectNames []string) (*common.ListObjectM
 
 	return list, nil
 }
+
+// ToListObjectMetadata converts every object stored in the file.
+func (r *ObjectMetadataResult) ToListObjectMetadata() *common.ListObjectMetadataResult {
+	list := &common.ListObjectMetadataResult{
+		Result: make(map[string]common.ObjectMetadata, len(r.Result)),
+		Errors: nil,
+	}
+
+	for name, v := range r.Result {
+		list.Result[name] = common.ObjectMetadata{
+			DisplayName: v.DisplayName,
+			FieldsMap:   v.FieldsMap,
+		}
+	}
+
+	return list
+}
+
+// NewObjectMetadataResultFromList is the reverse of ToListObjectMetadata.
+// Field details are dropped, since static files store only display names.
+func NewObjectMetadataResultFromList(list *common.ListObjectMetadataResult) *ObjectMetadataResult {
+	result := NewObjectMetadataResult()
+
+	for name, v := range list.Result {
+		result.Result[name] = ObjectMetadata{
+			DisplayName: v.DisplayName,
+			FieldsMap:   v.FieldsMap,
+		}
+	}
+
+	return result
+}
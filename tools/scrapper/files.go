@@ -1,10 +1,15 @@
 package scrapper
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/amp-labs/connectors/common/jsonschema"
+)
 
 const (
-	IndexFile   = "index.json"
-	SchemasFile = "schemas.json"
+	IndexFile      = "index.json"
+	SchemasFile    = "schemas.json"
+	JSONSchemaFile = "schemas.jsonschema.json"
 )
 
 // MetadataFileLocator locates index and schema files.
@@ -54,3 +59,27 @@ func (m MetadataFileManager) LoadSchemas() (*ObjectMetadataResult, error) {
 
 	return result, nil
 }
+
+// SaveJSONSchema exports schemas as JSON Schema document, where each object is defined under $defs.
+func (m MetadataFileManager) SaveJSONSchema(schemas *ObjectMetadataResult) error {
+	return FlushToFile(m.locator.AbsPathTo(JSONSchemaFile), jsonschema.FromListObjectMetadata(
+		schemas.ToListObjectMetadata(),
+	))
+}
+
+// LoadJSONSchema imports schemas from JSON Schema document produced by SaveJSONSchema.
+func (m MetadataFileManager) LoadJSONSchema() (*ObjectMetadataResult, error) {
+	var document *jsonschema.Schema
+
+	err := LoadFile(m.locator.AbsPathTo(JSONSchemaFile), &document)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := jsonschema.ToListObjectMetadata(document)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewObjectMetadataResultFromList(list), nil
+}
@@ -3,6 +3,7 @@ package utils
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -97,3 +98,36 @@ func TestCredentialOptions(t *testing.T) {
 		),
 	)
 }
+
+func TestJSONReaderWrite(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "creds.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"clientId": "id", "accessToken": ""}`), 0o600))
+
+	registry := NewCredentialsRegistry()
+	require.NoError(t, registry.AddReaders(
+		&JSONReader{FilePath: path, JSONPath: "$['accessToken']", CredKey: "AccessToken"},
+		&JSONReader{FilePath: path, JSONPath: "$['refreshToken']", CredKey: "RefreshToken"},
+		&JSONReader{FilePath: path, JSONPath: "$['clientId']", CredKey: "ClientId"},
+		&EnvReader{EnvName: "TEST_ENV_READ_ONLY", CredKey: "ReadOnly"},
+	))
+
+	require.NoError(t, registry.Set("AccessToken", "access"))
+	require.NoError(t, registry.Set("RefreshToken", "refresh"))
+	require.ErrorIs(t, registry.Set("ReadOnly", "value"), ErrNotWritable)
+	require.ErrorIs(t, registry.Set("Missing", "value"), ErrReaderNotFound)
+
+	require.Equal(t, "access", registry.MustString("AccessToken"))
+	require.Equal(t, "refresh", registry.MustString("RefreshToken"))
+	require.Equal(t, "id", registry.MustString("ClientId"))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// File is replaced, no temporary files are left behind.
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/amp-labs/connectors/common"
+	"github.com/spyzhov/ajson"
+)
+
+var (
+	ErrNotWritable         = errors.New("credential is read only")
+	ErrUnsupportedJSONPath = errors.New("only top-level keys can be created, ex: $['accessToken']")
+)
+
+// Writer is implemented by readers which can persist a new value, so that the next Value call returns it.
+type Writer interface {
+	Write(value any) error
+}
+
+// Set writes the value through the reader registered under the key.
+func (c CredentialsRegistry) Set(key string, value any) error {
+	reader, ok := c[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrReaderNotFound, key)
+	}
+
+	writer, ok := reader.(Writer)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotWritable, key)
+	}
+
+	return writer.Write(value)
+}
+
+func (r *ValueReader) Write(value any) error {
+	r.Val = value
+
+	return nil
+}
+
+// Write updates the value at the JSON path, other content of the file is kept.
+// The file is created if missing. Top-level key which doesn't exist yet is added.
+func (r *JSONReader) Write(value any) error {
+	root, perm, err := r.readForUpdate()
+	if err != nil {
+		return err
+	}
+
+	list, err := root.JSONPath(r.JSONPath)
+	if err != nil {
+		return err
+	}
+
+	if len(list) != 0 && list[0] != nil {
+		if err = list[0].Set(value); err != nil {
+			return err
+		}
+	} else if err = r.appendKey(root, value); err != nil {
+		return err
+	}
+
+	data, err := ajson.Marshal(root)
+	if err != nil {
+		return err
+	}
+
+	var pretty bytes.Buffer
+	if err = json.Indent(&pretty, data, "", "    "); err != nil {
+		return err
+	}
+
+	pretty.WriteString("\n")
+
+	return common.WriteFileAtomic(r.FilePath, pretty.Bytes(), perm)
+}
+
+func (r *JSONReader) readForUpdate() (*ajson.Node, os.FileMode, error) {
+	const defaultPerm = 0o600
+
+	data, err := os.ReadFile(r.FilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return ajson.ObjectNode("", map[string]*ajson.Node{}), defaultPerm, nil
+	}
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	info, err := os.Stat(r.FilePath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	root, err := ajson.Unmarshal(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return root, info.Mode().Perm(), nil
+}
+
+func (r *JSONReader) appendKey(root *ajson.Node, value any) error {
+	path, err := ajson.ParseJSONPath(r.JSONPath)
+	if err != nil {
+		return err
+	}
+
+	if len(path) != 2 || path[0] != "$" || !root.IsObject() { // nolint:gomnd
+		return fmt.Errorf("%w: %s", ErrUnsupportedJSONPath, r.JSONPath)
+	}
+
+	key := strings.Trim(path[1], `'"`)
+
+	node := ajson.NullNode(key)
+	if err = node.Set(value); err != nil {
+		return err
+	}
+
+	return root.AppendObject(key, node)
+}
@@ -0,0 +1,262 @@
+// Package oauthapp obtains OAuth tokens for development and testing: authorization code grant
+// with optional PKCE, served as a local callback handler, and device authorization grant for headless environments.
+package oauthapp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	stateNonceBytes = 32
+	// pendingStateTTL is how long the user has to complete the authorization request.
+	pendingStateTTL = 10 * time.Minute
+	// maxPendingStates caps authorization requests awaiting the callback, the oldest ones are dropped first.
+	maxPendingStates = 100
+)
+
+var (
+	ErrStateMismatch       = errors.New("state doesn't match any authorization request")
+	ErrAuthorizationDenied = errors.New("authorization denied")
+	ErrMissingCode         = errors.New("callback has no authorization code")
+)
+
+// App runs authorization code flow. Serving "/" redirects the user to the provider,
+// which redirects back to the Callback path with the code, which is then exchanged for a token.
+// Every authorization request gets a random state, which must come back with the callback.
+type App struct {
+	// Config of the provider, RedirectURL must point to the Callback path of this handler.
+	Config *oauth2.Config
+	// Callback is the path of the redirect URL.
+	Callback string
+	// Options are extra parameters of the authorization request, ex: access_type=offline.
+	Options []oauth2.AuthCodeOption
+	// State is an optional payload sent along with the random state, it is logged when the callback is received.
+	State string
+	// PKCE enables proof key for code exchange, it is required by providers with PKCE grant type.
+	PKCE bool
+	// OnToken is called with the obtained token, ex: to save it. Optional.
+	OnToken func(ctx context.Context, token *oauth2.Token) error
+
+	mut     sync.Mutex
+	pending map[string]pendingRequest
+	once    sync.Once
+	results chan result
+}
+
+// pendingRequest is the authorization request awaiting the callback.
+type pendingRequest struct {
+	pkce    *PKCE
+	created time.Time
+}
+
+type result struct {
+	token *oauth2.Token
+	err   error
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (a *App) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	switch {
+	case request.URL.Path == a.Callback && request.Method == http.MethodGet:
+		a.processCallback(writer, request)
+
+	case request.URL.Path == "/" && request.Method == http.MethodGet:
+		url, err := a.AuthCodeURL()
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		writer.Header().Set("Location", url)
+		writer.WriteHeader(http.StatusTemporaryRedirect)
+
+	default:
+		writer.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// AuthCodeURL starts new authorization request and returns the URL of the provider consent page.
+func (a *App) AuthCodeURL() (string, error) {
+	state, err := a.newState()
+	if err != nil {
+		return "", err
+	}
+
+	options := a.Options
+
+	var pkce *PKCE
+	if a.PKCE {
+		pkce = NewPKCE()
+		options = append(append([]oauth2.AuthCodeOption{}, options...), pkce.AuthCodeOptions()...)
+	}
+
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	if a.pending == nil {
+		a.pending = make(map[string]pendingRequest)
+	}
+
+	a.prunePendingLocked(time.Now())
+	a.pending[state] = pendingRequest{pkce: pkce, created: time.Now()}
+
+	return a.Config.AuthCodeURL(state, options...), nil
+}
+
+// Wait blocks until the first callback is processed and returns its outcome.
+func (a *App) Wait(ctx context.Context) (*oauth2.Token, error) {
+	select {
+	case res := <-a.resultChannel():
+		return res.token, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// processCallback processes the code obtained from the OAuth callback.
+func (a *App) processCallback(writer http.ResponseWriter, request *http.Request) {
+	token, err := a.exchange(request)
+	if err != nil {
+		slog.Error("OAuth callback failed", "error", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrStateMismatch) || errors.Is(err, ErrMissingCode) {
+			status = http.StatusBadRequest
+		}
+
+		http.Error(writer, err.Error(), status)
+	} else {
+		writer.Header().Set("Content-Type", "text/plain")
+		writer.WriteHeader(http.StatusOK)
+		_, _ = writer.Write([]byte("Received a token, printed in the console")) // nosemgrep
+	}
+
+	// Callbacks with foreign state don't complete the flow, they might be forged.
+	if !errors.Is(err, ErrStateMismatch) {
+		select {
+		case a.resultChannel() <- result{token: token, err: err}:
+		default:
+		}
+	}
+}
+
+func (a *App) exchange(request *http.Request) (*oauth2.Token, error) {
+	query := request.URL.Query()
+
+	pkce, err := a.takeState(query.Get("state"))
+	if err != nil {
+		return nil, err
+	}
+
+	if denied := query.Get("error"); len(denied) != 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrAuthorizationDenied, denied, query.Get("error_description"))
+	}
+
+	code := query.Get("code")
+	if len(code) == 0 {
+		return nil, ErrMissingCode
+	}
+
+	var options []oauth2.AuthCodeOption
+	if pkce != nil {
+		options = pkce.ExchangeOptions()
+	}
+
+	token, err := a.Config.Exchange(request.Context(), code, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.OnToken != nil {
+		if err = a.OnToken(request.Context(), token); err != nil {
+			return nil, err
+		}
+	}
+
+	return token, nil
+}
+
+// newState returns random nonce followed by the encoded payload, if any.
+func (a *App) newState() (string, error) {
+	nonce := make([]byte, stateNonceBytes)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	state := base64.RawURLEncoding.EncodeToString(nonce)
+	if len(a.State) != 0 {
+		state += "." + base64.RawURLEncoding.EncodeToString([]byte(a.State))
+	}
+
+	return state, nil
+}
+
+// takeState removes the pending request, so that the state cannot be used twice.
+func (a *App) takeState(state string) (*PKCE, error) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	request, ok := a.pending[state]
+	if !ok {
+		return nil, ErrStateMismatch
+	}
+
+	delete(a.pending, state)
+
+	if time.Since(request.created) > pendingStateTTL {
+		return nil, ErrStateMismatch
+	}
+
+	if _, payload, found := strings.Cut(state, "."); found {
+		if data, err := base64.RawURLEncoding.DecodeString(payload); err == nil {
+			slog.Info("got a state", "state", string(data))
+		}
+	}
+
+	return request.pkce, nil
+}
+
+// prunePendingLocked drops expired requests and makes room for a new one,
+// so that repeated visits of "/" don't grow the map without bound.
+func (a *App) prunePendingLocked(now time.Time) {
+	var (
+		oldestState string
+		oldest      time.Time
+	)
+
+	for state, request := range a.pending {
+		if now.Sub(request.created) > pendingStateTTL {
+			delete(a.pending, state)
+
+			continue
+		}
+
+		if len(oldestState) == 0 || request.created.Before(oldest) {
+			oldestState, oldest = state, request.created
+		}
+	}
+
+	if len(a.pending) >= maxPendingStates {
+		delete(a.pending, oldestState)
+	}
+}
+
+func (a *App) resultChannel() chan result {
+	a.once.Do(func() {
+		a.results = make(chan result, 1)
+	})
+
+	return a.results
+}
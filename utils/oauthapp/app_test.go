@@ -0,0 +1,305 @@
+package oauthapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/connectors/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// fakeAuthServer is a minimal authorization server, which approves every request.
+// It supports authorization code grant with PKCE and device authorization grant.
+type fakeAuthServer struct {
+	*httptest.Server
+
+	mut        sync.Mutex
+	challenges map[string][2]string // code -> challenge, method
+	approved   atomic.Bool          // device code approved by the user
+	issued     atomic.Int32
+}
+
+func newFakeAuthServer(t *testing.T) *fakeAuthServer {
+	t.Helper()
+
+	fake := &fakeAuthServer{challenges: make(map[string][2]string)}
+	fake.Server = httptest.NewServer(http.HandlerFunc(fake.handle))
+	t.Cleanup(fake.Close)
+
+	return fake
+}
+
+func (f *fakeAuthServer) endpoint() oauth2.Endpoint {
+	return oauth2.Endpoint{
+		AuthURL:       f.URL + "/authorize",
+		TokenURL:      f.URL + "/token",
+		DeviceAuthURL: f.URL + "/device",
+		AuthStyle:     oauth2.AuthStyleInParams,
+	}
+}
+
+func (f *fakeAuthServer) handle(writer http.ResponseWriter, request *http.Request) {
+	switch request.URL.Path {
+	case "/authorize":
+		query := request.URL.Query()
+		code := fmt.Sprintf("code-%d", time.Now().UnixNano())
+
+		f.mut.Lock()
+		f.challenges[code] = [2]string{query.Get("code_challenge"), query.Get("code_challenge_method")}
+		f.mut.Unlock()
+
+		redirect := fmt.Sprintf("%s?code=%s&state=%s",
+			query.Get("redirect_uri"), code, url.QueryEscape(query.Get("state")))
+		http.Redirect(writer, request, redirect, http.StatusFound)
+	case "/device":
+		writeJSON(writer, http.StatusOK, map[string]any{
+			"device_code":      "device-code",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": f.URL + "/activate",
+			"expires_in":       60,
+			"interval":         1,
+		})
+	case "/token":
+		f.token(writer, request)
+	default:
+		writer.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeAuthServer) token(writer http.ResponseWriter, request *http.Request) {
+	switch request.FormValue("grant_type") {
+	case "authorization_code":
+		f.mut.Lock()
+		challenge, ok := f.challenges[request.FormValue("code")]
+		delete(f.challenges, request.FormValue("code"))
+		f.mut.Unlock()
+
+		if !ok {
+			writeJSON(writer, http.StatusBadRequest, map[string]any{"error": "invalid_grant"})
+
+			return
+		}
+
+		if len(challenge[0]) != 0 {
+			if err := VerifyPKCE(request.FormValue("code_verifier"), challenge[0], challenge[1]); err != nil {
+				writeJSON(writer, http.StatusBadRequest, map[string]any{"error": "invalid_grant"})
+
+				return
+			}
+		}
+	case "urn:ietf:params:oauth:grant-type:device_code":
+		if !f.approved.Load() {
+			writeJSON(writer, http.StatusBadRequest, map[string]any{"error": "authorization_pending"})
+
+			return
+		}
+	default:
+		writeJSON(writer, http.StatusBadRequest, map[string]any{"error": "unsupported_grant_type"})
+
+		return
+	}
+
+	count := f.issued.Add(1)
+	writeJSON(writer, http.StatusOK, map[string]any{
+		"access_token":  fmt.Sprintf("access-%d", count),
+		"refresh_token": fmt.Sprintf("refresh-%d", count),
+		"token_type":    "Bearer",
+		"expires_in":    3600,
+	})
+}
+
+func writeJSON(writer http.ResponseWriter, status int, body any) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	_ = json.NewEncoder(writer).Encode(body)
+}
+
+func newTestApp(t *testing.T, fake *fakeAuthServer, pkce bool) (*App, *httptest.Server) {
+	t.Helper()
+
+	app := &App{
+		Config: &oauth2.Config{
+			ClientID: "client",
+			Endpoint: fake.endpoint(),
+		},
+		Callback: "/callback",
+		State:    "payload",
+		PKCE:     pkce,
+	}
+
+	server := httptest.NewServer(app)
+	t.Cleanup(server.Close)
+
+	app.Config.RedirectURL = server.URL + app.Callback
+
+	return app, server
+}
+
+func get(t *testing.T, url string) int {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	rsp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	_ = rsp.Body.Close()
+
+	return rsp.StatusCode
+}
+
+func TestAuthCodeFlow(t *testing.T) {
+	t.Parallel()
+
+	for _, pkce := range []bool{true, false} {
+		pkce := pkce
+
+		t.Run(fmt.Sprintf("PKCE %v", pkce), func(t *testing.T) {
+			t.Parallel()
+
+			fake := newFakeAuthServer(t)
+			app, server := newTestApp(t, fake, pkce)
+
+			registry := utils.NewCredentialsRegistry()
+			for _, key := range []string{AccessTokenKey, RefreshTokenKey, ExpiryKey, ExpiryFormatKey} {
+				require.NoError(t, registry.AddReader(&utils.ValueReader{Val: "", CredKey: key}))
+			}
+
+			app.OnToken = func(_ context.Context, token *oauth2.Token) error {
+				return SaveToken(registry, token)
+			}
+
+			// Browser follows redirects to the provider and back to the callback.
+			assert.Equal(t, http.StatusOK, get(t, server.URL+"/"))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			token, err := app.Wait(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "access-1", token.AccessToken)
+
+			assert.Equal(t, "access-1", registry.MustString(AccessTokenKey))
+			assert.Equal(t, "refresh-1", registry.MustString(RefreshTokenKey))
+			assert.Equal(t, "RFC3339", registry.MustString(ExpiryFormatKey))
+
+			expiry, err := time.Parse(time.RFC3339, registry.MustString(ExpiryKey))
+			require.NoError(t, err)
+			assert.WithinDuration(t, time.Now().Add(time.Hour), expiry, time.Minute)
+		})
+	}
+}
+
+func TestAuthCodeFlowState(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeAuthServer(t)
+	app, server := newTestApp(t, fake, true)
+
+	assert.Equal(t, http.StatusBadRequest, get(t, server.URL+"/callback?code=forged&state=unknown"))
+
+	authURL, err := app.AuthCodeURL()
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+
+	state := parsed.Query().Get("state")
+	assert.Equal(t, PKCEMethodS256, parsed.Query().Get("code_challenge_method"))
+
+	// Provider reports the user has denied the access.
+	callback := server.URL + "/callback?error=access_denied&state=" + url.QueryEscape(state)
+	assert.Equal(t, http.StatusInternalServerError, get(t, callback))
+
+	_, err = app.Wait(context.Background())
+	require.ErrorIs(t, err, ErrAuthorizationDenied)
+
+	// State is single use.
+	assert.Equal(t, http.StatusBadRequest, get(t, callback))
+}
+
+func TestAuthCodeFlowPendingStates(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeAuthServer(t)
+	app, server := newTestApp(t, fake, false)
+
+	states := make([]string, 0, maxPendingStates+1)
+
+	for i := 0; i <= maxPendingStates; i++ {
+		authURL, err := app.AuthCodeURL()
+		require.NoError(t, err)
+
+		parsed, err := url.Parse(authURL)
+		require.NoError(t, err)
+
+		states = append(states, parsed.Query().Get("state"))
+	}
+
+	// The oldest request made room for the newest one.
+	assert.Len(t, app.pending, maxPendingStates)
+	assert.Equal(t, http.StatusBadRequest, get(t, server.URL+"/callback?code=code&state="+url.QueryEscape(states[0])))
+
+	// Request which has expired is rejected and dropped.
+	app.mut.Lock()
+	expired := app.pending[states[1]]
+	expired.created = time.Now().Add(-2 * pendingStateTTL)
+	app.pending[states[1]] = expired
+	app.mut.Unlock()
+
+	assert.Equal(t, http.StatusBadRequest, get(t, server.URL+"/callback?code=code&state="+url.QueryEscape(states[1])))
+	assert.Len(t, app.pending, maxPendingStates-1)
+}
+
+func TestDeviceToken(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeAuthServer(t)
+	config := &oauth2.Config{ClientID: "client", Endpoint: fake.endpoint()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	token, err := DeviceToken(ctx, config, func(auth *oauth2.DeviceAuthResponse) {
+		assert.Equal(t, "ABCD-EFGH", auth.UserCode)
+		assert.Equal(t, fake.URL+"/activate", auth.VerificationURI)
+
+		fake.approved.Store(true)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", token.AccessToken)
+
+	config.Endpoint.DeviceAuthURL = ""
+	_, err = DeviceToken(ctx, config, func(*oauth2.DeviceAuthResponse) {})
+	require.ErrorIs(t, err, ErrMissingDeviceAuthURL)
+}
+
+func TestVerifyPKCE(t *testing.T) {
+	t.Parallel()
+
+	pkce := NewPKCE()
+	require.NoError(t, ValidateVerifier(pkce.Verifier))
+	require.NoError(t, VerifyPKCE(pkce.Verifier, pkce.Challenge, pkce.Method))
+	require.ErrorIs(t, VerifyPKCE(NewPKCE().Verifier, pkce.Challenge, pkce.Method), ErrPKCEMismatch)
+
+	verifier := strings.Repeat("a", minVerifierLength)
+	require.NoError(t, VerifyPKCE(verifier, verifier, ""))
+	require.NoError(t, VerifyPKCE(verifier, verifier, PKCEMethodPlain))
+	require.ErrorIs(t, VerifyPKCE(verifier, verifier, "S512"), ErrUnsupportedPKCEMethod)
+
+	require.ErrorIs(t, ValidateVerifier("short"), ErrInvalidVerifier)
+	require.ErrorIs(t, ValidateVerifier(strings.Repeat("a", maxVerifierLength+1)), ErrInvalidVerifier)
+	require.ErrorIs(t, ValidateVerifier(strings.Repeat("a", minVerifierLength)+"+"), ErrInvalidVerifier)
+}
@@ -0,0 +1,30 @@
+package oauthapp
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/oauth2"
+)
+
+var ErrMissingDeviceAuthURL = errors.New("device authorization URL is not set in the OAuth endpoint")
+
+// DeviceToken obtains token using device authorization grant (RFC 8628), for environments without
+// a browser or where the callback cannot be received. Prompt should show the user where to go and which code to enter,
+// then the provider is polled until the user approves or denies the request, or the code expires.
+func DeviceToken(ctx context.Context, config *oauth2.Config,
+	prompt func(auth *oauth2.DeviceAuthResponse), opts ...oauth2.AuthCodeOption,
+) (*oauth2.Token, error) {
+	if len(config.Endpoint.DeviceAuthURL) == 0 {
+		return nil, ErrMissingDeviceAuthURL
+	}
+
+	auth, err := config.DeviceAuth(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt(auth)
+
+	return config.DeviceAccessToken(ctx, auth, opts...)
+}
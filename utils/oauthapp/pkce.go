@@ -0,0 +1,102 @@
+package oauthapp
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// PKCEMethodS256 sends SHA-256 hash of the verifier as the challenge. It is the recommended method.
+	PKCEMethodS256 = "S256"
+	// PKCEMethodPlain sends the verifier itself as the challenge.
+	PKCEMethodPlain = "plain"
+
+	minVerifierLength = 43
+	maxVerifierLength = 128
+)
+
+var (
+	ErrInvalidVerifier       = errors.New("code verifier must be 43-128 characters of [A-Za-z0-9-._~]")
+	ErrPKCEMismatch          = errors.New("code verifier doesn't match the challenge")
+	ErrUnsupportedPKCEMethod = errors.New("unsupported code challenge method")
+)
+
+// PKCE is the proof key for code exchange (RFC 7636), used by public clients which cannot keep a secret.
+// Challenge is sent with the authorization request, verifier is sent when the code is exchanged for a token.
+type PKCE struct {
+	Verifier  string
+	Challenge string
+	Method    string
+}
+
+// NewPKCE generates random verifier with S256 challenge.
+func NewPKCE() *PKCE {
+	verifier := oauth2.GenerateVerifier()
+
+	return &PKCE{
+		Verifier:  verifier,
+		Challenge: oauth2.S256ChallengeFromVerifier(verifier),
+		Method:    PKCEMethodS256,
+	}
+}
+
+// AuthCodeOptions returns parameters of the authorization request.
+func (p *PKCE) AuthCodeOptions() []oauth2.AuthCodeOption {
+	return []oauth2.AuthCodeOption{
+		oauth2.SetAuthURLParam("code_challenge", p.Challenge),
+		oauth2.SetAuthURLParam("code_challenge_method", p.Method),
+	}
+}
+
+// ExchangeOptions returns parameters of the token request.
+func (p *PKCE) ExchangeOptions() []oauth2.AuthCodeOption {
+	return []oauth2.AuthCodeOption{
+		oauth2.VerifierOption(p.Verifier),
+	}
+}
+
+// ValidateVerifier checks the verifier has the length and characters required by RFC 7636.
+func ValidateVerifier(verifier string) error {
+	if len(verifier) < minVerifierLength || len(verifier) > maxVerifierLength {
+		return ErrInvalidVerifier
+	}
+
+	for _, char := range verifier {
+		switch {
+		case char >= 'A' && char <= 'Z', char >= 'a' && char <= 'z', char >= '0' && char <= '9':
+		case char == '-', char == '.', char == '_', char == '~':
+		default:
+			return ErrInvalidVerifier
+		}
+	}
+
+	return nil
+}
+
+// VerifyPKCE checks the verifier against the challenge, as done by the authorization server.
+// Empty method means "plain", as defined by RFC 7636.
+func VerifyPKCE(verifier, challenge, method string) error {
+	if err := ValidateVerifier(verifier); err != nil {
+		return err
+	}
+
+	var expected string
+
+	switch method {
+	case PKCEMethodS256:
+		expected = oauth2.S256ChallengeFromVerifier(verifier)
+	case PKCEMethodPlain, "":
+		expected = verifier
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedPKCEMethod, method)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) != 1 {
+		return ErrPKCEMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package oauthapp
+
+import (
+	"time"
+
+	"github.com/amp-labs/connectors/utils"
+	"golang.org/x/oauth2"
+)
+
+// Credential keys written by SaveToken, the same keys are read by the proxy script.
+const (
+	AccessTokenKey  = "AccessToken"
+	RefreshTokenKey = "RefreshToken"
+	ExpiryKey       = "Expiry"
+	ExpiryFormatKey = "ExpiryFormat"
+)
+
+// SaveToken writes the token through the registry readers, ex: into creds.json.
+// Refresh token and expiry are written only if the provider has returned them.
+func SaveToken(registry utils.CredentialsRegistry, token *oauth2.Token) error {
+	if err := registry.Set(AccessTokenKey, token.AccessToken); err != nil {
+		return err
+	}
+
+	if len(token.RefreshToken) != 0 {
+		if err := registry.Set(RefreshTokenKey, token.RefreshToken); err != nil {
+			return err
+		}
+	}
+
+	if !token.Expiry.IsZero() {
+		if err := registry.Set(ExpiryKey, token.Expiry.Format(time.RFC3339)); err != nil {
+			return err
+		}
+
+		if err := registry.Set(ExpiryFormatKey, "RFC3339"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}